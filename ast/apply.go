@@ -0,0 +1,441 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// An ApplyFunc is invoked by Apply for each node n, even if n is nil,
+// before and/or after the node's children, using a Cursor describing
+// the current node and providing operations on it.
+//
+// The return value of ApplyFunc controls the syntax tree traversal.
+// See Apply for details.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses a syntax tree recursively, starting with root, and
+// calling pre and post for each node:
+//
+//   - If pre is not nil, it is called for each node before the node's
+//     children are traversed (pre-order). If pre returns false, no
+//     children are traversed, and post is not called for that node.
+//
+//   - If post is not nil, and a prior call of pre didn't return false,
+//     post is called for each node after its children are traversed
+//     (post-order). If post returns false, traversal is terminated and
+//     Apply returns immediately.
+//
+// Only fields that refer to AST nodes are considered children; i.e.,
+// flags like SelectStmt.Distinct are not. Children are traversed in
+// source order, like Walk, except that all vertices of a path are
+// traversed before its edges.
+//
+// Apply calls pre and post for nil children too, so that e.g. a
+// missing WHERE clause can be added with Cursor.Replace.
+//
+// Nodes inserted or replaced through the Cursor are not traversed.
+// The (possibly replaced) root is returned.
+func Apply(root Node, pre, post ApplyFunc) (result Node) {
+	parent := &struct{ Node }{root}
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+		result = parent.Node
+	}()
+
+	a := &application{pre: pre, post: post}
+	a.apply(parent, "Node", nil, root)
+	return
+}
+
+// abort is the panic value used to terminate an Apply early.
+var abort = new(int)
+
+// A Cursor describes a node encountered during Apply. Information
+// about the node and its parent is available from the Node, Parent,
+// Name, and Index methods.
+//
+// If p is a variable of type and value of the current parent node
+// c.Parent(), and f is the field identifier with name c.Name(), the
+// following invariants hold:
+//
+//	p.f            == c.Node()  if c.Index() <  0
+//	p.f[c.Index()] == c.Node()  if c.Index() >= 0
+//
+// The methods Replace, Delete, InsertBefore, and InsertAfter can be
+// used to change the AST without disrupting Apply.
+type Cursor struct {
+	parent Node
+	name   string
+	iter   *iterator // Valid if non-nil.
+	node   Node
+}
+
+// Node returns the current Node.
+func (c *Cursor) Node() Node { return c.node }
+
+// Parent returns the parent of the current Node.
+func (c *Cursor) Parent() Node { return c.parent }
+
+// Name returns the name of the parent Node field that contains the
+// current Node. If the parent is the root passed to Apply, Name
+// returns "Node".
+func (c *Cursor) Name() string { return c.name }
+
+// Index reports the index >= 0 of the current Node in the slice of
+// Nodes that contains it, or a value < 0 if the current Node is not
+// part of a slice. The index of the current node changes if
+// InsertBefore is called while processing the current node.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+	return -1
+}
+
+// field returns the current node's parent field value.
+func (c *Cursor) field() reflect.Value {
+	return reflect.Indirect(reflect.ValueOf(c.parent)).FieldByName(c.name)
+}
+
+// Replace replaces the current Node with n. The replacement node is
+// not walked by Apply. A nil n clears the field, or sets the slice
+// element to nil.
+func (c *Cursor) Replace(n Node) {
+	v := c.field()
+	if i := c.Index(); i >= 0 {
+		v = v.Index(i)
+	}
+	v.Set(nodeValue(v.Type(), n))
+}
+
+// Delete deletes the current Node from its containing slice. If the
+// current Node is not part of a slice, Delete panics.
+func (c *Cursor) Delete() {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Errorf("ast.Cursor.Delete: node %T is not part of a slice", c.node))
+	}
+	v := c.field()
+	l := v.Len()
+	reflect.Copy(v.Slice(i, l), v.Slice(i+1, l))
+	v.Index(l - 1).Set(reflect.Zero(v.Type().Elem()))
+	v.SetLen(l - 1)
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current Node in its containing
+// slice. If the current Node is not part of a slice, InsertAfter
+// panics. Apply does not walk n.
+func (c *Cursor) InsertAfter(n Node) {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Errorf("ast.Cursor.InsertAfter: node %T is not part of a slice", c.node))
+	}
+	v := c.field()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+	reflect.Copy(v.Slice(i+2, l), v.Slice(i+1, l))
+	v.Index(i + 1).Set(nodeValue(v.Type().Elem(), n))
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current Node in its containing
+// slice. If the current Node is not part of a slice, InsertBefore
+// panics. Apply will not walk n.
+func (c *Cursor) InsertBefore(n Node) {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Errorf("ast.Cursor.InsertBefore: node %T is not part of a slice", c.node))
+	}
+	v := c.field()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+	reflect.Copy(v.Slice(i+1, l), v.Slice(i, l))
+	v.Index(i).Set(nodeValue(v.Type().Elem(), n))
+	c.iter.index++
+}
+
+// nodeValue returns n as a value assignable to type t. A nil node
+// becomes the zero value.
+func nodeValue(t reflect.Type, n Node) reflect.Value {
+	if n == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(n)
+}
+
+// application carries all the shared data so we can pass it around
+// cheaply.
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+func (a *application) apply(parent Node, name string, iter *iterator, n Node) {
+	// Convert typed nil into untyped nil.
+	if v := reflect.ValueOf(n); v.Kind() == reflect.Ptr && v.IsNil() {
+		n = nil
+	}
+
+	saved := a.cursor
+	a.cursor.parent = parent
+	a.cursor.name = name
+	a.cursor.iter = iter
+	a.cursor.node = n
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+
+	switch n := n.(type) {
+	case nil:
+		// Nothing to do.
+
+	// Statements.
+
+	case *SelectStmt:
+		a.applyList(n, "PathMacros")
+		a.applyList(n, "Sels")
+		a.applyList(n, "From")
+		a.apply(n, "Where", nil, n.Where)
+		a.applyList(n, "GroupBy")
+		a.apply(n, "Having", nil, n.Having)
+		a.applyList(n, "OrderBy")
+		a.apply(n, "Limit", nil, n.Limit)
+		a.apply(n, "Offset", nil, n.Offset)
+
+	case *ModifyStmt:
+		a.applyList(n, "PathMacros")
+		a.applyList(n, "Mods")
+		a.applyList(n, "From")
+		a.apply(n, "Where", nil, n.Where)
+		a.applyList(n, "GroupBy")
+		a.apply(n, "Having", nil, n.Having)
+		a.applyList(n, "OrderBy")
+		a.apply(n, "Limit", nil, n.Limit)
+		a.apply(n, "Offset", nil, n.Offset)
+
+	case *CreateStmt:
+		a.apply(n, "GraphName", nil, n.GraphName)
+		a.applyList(n, "VertexTables")
+		a.applyList(n, "EdgeTables")
+
+	case *DropStmt:
+		a.apply(n, "GraphName", nil, n.GraphName)
+
+	// Graph definitions.
+
+	case *VertexTableDecl:
+		a.apply(n, "TableName", nil, n.TableName)
+		a.apply(n, "TableAlias", nil, n.TableAlias)
+		a.applyList(n, "Keys")
+		a.apply(n, "Label", nil, n.Label)
+		a.apply(n, "Props", nil, n.Props)
+
+	case *EdgeTableDecl:
+		a.apply(n, "TableName", nil, n.TableName)
+		a.apply(n, "TableAlias", nil, n.TableAlias)
+		a.applyList(n, "Keys")
+		a.apply(n, "Source", nil, n.Source)
+		a.apply(n, "Dest", nil, n.Dest)
+		a.apply(n, "Label", nil, n.Label)
+		a.apply(n, "Props", nil, n.Props)
+
+	case *PropsClause:
+		a.applyList(n, "Except")
+		a.applyList(n, "Exprs")
+
+	case *VertexTableRef:
+		a.applyList(n, "Keys")
+		a.apply(n, "TableName", nil, n.TableName)
+		a.applyList(n, "Columns")
+
+	case *PropExpr:
+		a.apply(n, "Column", nil, n.Column)
+		a.apply(n, "CastAs", nil, n.CastAs)
+		a.apply(n, "Name", nil, n.Name)
+
+	// Queries.
+
+	case *PathMacroClause:
+		a.apply(n, "Name", nil, n.Name)
+		a.apply(n, "Pattern", nil, n.Pattern)
+		a.apply(n, "Where", nil, n.Where)
+
+	case *SelectElem:
+		a.apply(n, "Named", nil, n.Named)
+		a.apply(n, "AllOf", nil, n.AllOf)
+		a.apply(n, "Prefix", nil, n.Prefix)
+
+	case *MatchClause:
+		a.applyList(n, "Patterns")
+		a.apply(n, "On", nil, n.On)
+		a.apply(n, "Rows", nil, n.Rows)
+
+	case *PathPattern:
+		a.apply(n, "K", nil, n.K)
+		a.applyList(n, "Vs")
+		a.applyList(n, "Es")
+
+	case *PathPatternPrimary:
+		a.applyList(n, "Vs")
+		a.applyList(n, "Es")
+		a.apply(n, "Where", nil, n.Where)
+		a.apply(n, "Cost", nil, n.Cost)
+		a.apply(n, "Quantity", nil, n.Quantity)
+
+	case *VertexPattern:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "LabelAlts")
+
+	case *EdgePattern:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyList(n, "LabelAlts")
+
+	case *MatchRows:
+		a.applyList(n, "Vars")
+
+	case *OrderTerm:
+		a.apply(n, "Expr", nil, n.Expr)
+
+	case *Quantifier:
+		a.apply(n, "Min", nil, n.Min)
+		a.apply(n, "Max", nil, n.Max)
+
+	// Modifications.
+
+	case *InsertClause:
+		a.apply(n, "Into", nil, n.Into)
+		a.applyList(n, "Vs")
+		a.applyList(n, "Es")
+
+	case *UpdateClause:
+		a.applyList(n, "Updates")
+
+	case *DeleteClause:
+		a.applyList(n, "Vars")
+
+	case *Update:
+		a.apply(n, "Var", nil, n.Var)
+		a.applyList(n, "Props")
+
+	case *VertexInsertion:
+		a.apply(n, "Var", nil, n.Var)
+		a.applyList(n, "Labels")
+		a.applyList(n, "Props")
+
+	case *EdgeInsertion:
+		a.apply(n, "Var", nil, n.Var)
+		a.apply(n, "Source", nil, n.Source)
+		a.apply(n, "Dest", nil, n.Dest)
+		a.applyList(n, "Labels")
+		a.applyList(n, "Props")
+
+	case *PropAssignment:
+		a.apply(n, "Prop", nil, n.Prop)
+		a.apply(n, "Value", nil, n.Value)
+
+	// Expressions.
+
+	case *OpExpr:
+		a.applyList(n, "Args")
+
+	case *AggregateExpr:
+		a.apply(n, "Arg", nil, n.Arg)
+		a.apply(n, "Separator", nil, n.Separator)
+
+	case *ExtractExpr:
+		a.apply(n, "Arg", nil, n.Arg)
+
+	case *SubstringExpr:
+		a.apply(n, "Arg", nil, n.Arg)
+		a.apply(n, "Start", nil, n.Start)
+		a.apply(n, "Length", nil, n.Length)
+
+	case *IsNullExpr:
+		a.apply(n, "Arg", nil, n.Arg)
+
+	case *ExistsExpr:
+		a.apply(n, "Query", nil, n.Query)
+
+	case *LabelExpr:
+		a.apply(n, "Arg", nil, n.Arg)
+
+	case *CallExpr:
+		a.apply(n, "Func", nil, n.Func)
+		a.applyList(n, "Args")
+
+	case *CastExpr:
+		a.apply(n, "Arg", nil, n.Arg)
+
+	case *CaseExpr:
+		a.apply(n, "Subject", nil, n.Subject)
+		a.applyList(n, "Whens")
+		a.apply(n, "Else", nil, n.Else)
+
+	case *WhenClause:
+		a.apply(n, "Cond", nil, n.Cond)
+		a.apply(n, "Then", nil, n.Then)
+
+	case *InExpr:
+		a.apply(n, "Subject", nil, n.Subject)
+		a.applyList(n, "Objects")
+		a.apply(n, "BindVar", nil, n.BindVar)
+
+	case *SubqueryExpr:
+		a.apply(n, "Query", nil, n.Query)
+
+	case *NamedExpr:
+		a.apply(n, "Expr", nil, n.Expr)
+		a.apply(n, "Name", nil, n.Name)
+
+	case *QIdent:
+		a.applyList(n, "Names")
+
+	case *Ident, *BasicLit, *BindVar:
+		// Leaves.
+
+	default:
+		panic(fmt.Errorf("ast.Apply: unexpected node type %T", n))
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+
+	a.cursor = saved
+}
+
+// An iterator controls iteration over a slice of nodes.
+type iterator struct {
+	index, step int
+}
+
+func (a *application) applyList(parent Node, name string) {
+	// Avoid heap-allocating a new iterator for each applyList call;
+	// reuse a.iter instead.
+	saved := a.iter
+	a.iter.index = 0
+	for {
+		// Must reload parent.name each time, since cursor
+		// modifications might change it.
+		v := reflect.Indirect(reflect.ValueOf(parent)).FieldByName(name)
+		if a.iter.index >= v.Len() {
+			break
+		}
+
+		var x Node
+		if e := v.Index(a.iter.index); e.IsValid() {
+			x, _ = e.Interface().(Node)
+		}
+
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, x)
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
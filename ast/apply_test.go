@@ -0,0 +1,179 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestApply(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Pre   ast.ApplyFunc
+		Want  string
+	}{
+		{
+			"noop",
+			"SELECT a FROM MATCH (a) WHERE a.x = 1;",
+			func(c *ast.Cursor) bool { return true },
+			"SELECT a FROM MATCH (a) WHERE a.x = 1;",
+		},
+		{
+			"replaceBindVar",
+			"SELECT a FROM MATCH (a) WHERE a.x = ? LIMIT ?;",
+			func(c *ast.Cursor) bool {
+				if _, ok := c.Node().(*ast.BindVar); ok {
+					c.Replace(&ast.BasicLit{S: "42", Kind: ast.UIntKind})
+				}
+				return true
+			},
+			"SELECT a FROM MATCH (a) WHERE a.x = 42 LIMIT 42;",
+		},
+		{
+			"addWhere",
+			"SELECT a FROM MATCH (a);",
+			func(c *ast.Cursor) bool {
+				if _, ok := c.Parent().(*ast.SelectStmt); ok && c.Name() == "Where" {
+					c.Replace(tenantFilter(c.Node()))
+					return false
+				}
+				return true
+			},
+			"SELECT a FROM MATCH (a) WHERE a.tenant = 'x';",
+		},
+		{
+			"extendWhere",
+			"SELECT a FROM MATCH (a) WHERE a.y > 1;",
+			func(c *ast.Cursor) bool {
+				if _, ok := c.Parent().(*ast.SelectStmt); ok && c.Name() == "Where" {
+					c.Replace(tenantFilter(c.Node()))
+					return false
+				}
+				return true
+			},
+			"SELECT a FROM MATCH (a) WHERE a.tenant = 'x' AND a.y > 1;",
+		},
+		{
+			"deleteSels",
+			"SELECT a, b, c FROM MATCH (a) -> (b) -> (c);",
+			func(c *ast.Cursor) bool {
+				if sel, ok := c.Node().(*ast.SelectElem); ok && sel.Named.Expr.(*ast.Ident).Name != "b" {
+					c.Delete()
+					return false
+				}
+				return true
+			},
+			"SELECT b FROM MATCH (a) -> (b) -> (c);",
+		},
+		{
+			"insertPatterns",
+			"SELECT a FROM MATCH ((a), (c));",
+			func(c *ast.Cursor) bool {
+				if _, ok := c.Node().(*ast.PathPattern); ok && c.Index() == 0 {
+					c.InsertBefore(&ast.PathPattern{Vs: []*ast.VertexPattern{{Name: &ast.Ident{Name: "z"}}}})
+					c.InsertAfter(&ast.PathPattern{Vs: []*ast.VertexPattern{{Name: &ast.Ident{Name: "b"}}}})
+				}
+				return true
+			},
+			"SELECT a FROM MATCH ((z), (a), (b), (c));",
+		},
+		{
+			"insertMods",
+			"UPDATE a SET (a.x = 1) FROM MATCH (a);",
+			func(c *ast.Cursor) bool {
+				if _, ok := c.Node().(*ast.UpdateClause); ok {
+					c.InsertAfter(&ast.DeleteClause{Vars: []*ast.Ident{{Name: "a"}}})
+				}
+				return true
+			},
+			"UPDATE a SET (a.x = 1) DELETE a FROM MATCH (a);",
+		},
+		{
+			"replaceArgs",
+			"SELECT a FROM MATCH (a) WHERE a.x + 1 > 2;",
+			func(c *ast.Cursor) bool {
+				if lit, ok := c.Node().(*ast.BasicLit); ok && c.Name() == "Args" && lit.S == "1" {
+					c.Replace(&ast.BasicLit{S: "3", Kind: ast.UIntKind})
+				}
+				return true
+			},
+			"SELECT a FROM MATCH (a) WHERE a.x + 3 > 2;",
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			in := parse(t, tst.Input)
+			want := parse(t, tst.Want)
+
+			got := ast.Apply(in, tst.Pre, nil)
+
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreTypes(ast.Pos(0))); diff != "" {
+				t.Errorf("Apply: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyPost(t *testing.T) {
+	in := parse(t, "SELECT a FROM MATCH (a) WHERE a.x = 1 AND a.y = 2;")
+
+	var got []string
+	ast.Apply(in, nil, func(c *ast.Cursor) bool {
+		if id, ok := c.Node().(*ast.Ident); ok {
+			got = append(got, id.Name)
+			return id.Name != "x"
+		}
+		return true
+	})
+
+	if diff := cmp.Diff([]string{"a", "a", "a", "x"}, got); diff != "" {
+		t.Errorf("Apply: +got, -want:\n%s", diff)
+	}
+}
+
+func TestApplyRoot(t *testing.T) {
+	in := parse(t, "SELECT a FROM MATCH (a);")
+	want := &ast.DropStmt{}
+
+	got := ast.Apply(in, func(c *ast.Cursor) bool {
+		c.Replace(want)
+		return false
+	}, nil)
+
+	if got != want {
+		t.Errorf("Apply: got %v, want %v", got, want)
+	}
+}
+
+// tenantFilter returns an expression that ANDs a tenant predicate
+// with the given condition, which may be nil. The operators are not
+// exported, so the expressions are parsed.
+func tenantFilter(cond ast.Node) ast.Expr {
+	if cond == nil {
+		return parse(nil, "SELECT a FROM MATCH (a) WHERE a.tenant = 'x';").(*ast.SelectStmt).Where
+	}
+
+	and := parse(nil, "SELECT a FROM MATCH (a) WHERE a.tenant = 'x' AND true;").(*ast.SelectStmt).Where.(*ast.OpExpr)
+	and.Args[1] = cond.(ast.Expr)
+	return and
+}
+
+func parse(t *testing.T, s string) ast.Stmt {
+	stmts, err := parser.Parse(strings.NewReader(s))
+	if err != nil {
+		if t == nil {
+			panic(err)
+		}
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return stmts.Stmts[0]
+}
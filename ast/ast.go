@@ -1,89 +1,233 @@
 package ast
 
-type Pos int
-
 type Stmt interface {
+	Node
 	stmtTag()
 }
 
 type Expr interface {
+	Node
 	exprTag()
 }
 
 type OpExpr struct {
-	Args []Expr
-	Op   int
+	Args  []Expr
+	Op    Op
+	OpPos Pos
 }
 
 func (OpExpr) exprTag() {}
 
+func (x *OpExpr) Pos() Pos {
+	if len(x.Args) > 0 && x.Args[0] != nil {
+		if p := x.Args[0].Pos(); p.IsValid() && p < x.OpPos {
+			return p
+		}
+	}
+	return x.OpPos
+}
+
+func (x *OpExpr) End() Pos { return lastEnd(x.Args) }
+
+type AggregateExpr struct {
+	Func      AggFunc
+	Distinct  bool
+	Arg       Expr      // Nil if Star.
+	Separator *BasicLit // Only for LISTAGG, and optional.
+	Star      bool      // COUNT(*).
+	FuncPos   Pos
+	Rparen    Pos
+}
+
+func (AggregateExpr) exprTag() {}
+
+func (x *AggregateExpr) Pos() Pos { return x.FuncPos }
+func (x *AggregateExpr) End() Pos { return after(x.Rparen, 1) }
+
+type ExtractExpr struct {
+	Field   DateTimeField
+	Arg     Expr
+	Extract Pos
+	Rparen  Pos
+}
+
+func (ExtractExpr) exprTag() {}
+
+func (x *ExtractExpr) Pos() Pos { return x.Extract }
+func (x *ExtractExpr) End() Pos { return after(x.Rparen, 1) }
+
+type SubstringExpr struct {
+	Arg       Expr
+	Start     Expr
+	Length    Expr // Optional.
+	Substring Pos
+	Rparen    Pos
+}
+
+func (SubstringExpr) exprTag() {}
+
+func (x *SubstringExpr) Pos() Pos { return x.Substring }
+func (x *SubstringExpr) End() Pos { return after(x.Rparen, 1) }
+
+type IsNullExpr struct {
+	Arg  Expr
+	Not  bool
+	Is   Pos
+	Null Pos
+}
+
+func (IsNullExpr) exprTag() {}
+
+func (x *IsNullExpr) Pos() Pos { return x.Arg.Pos() }
+func (x *IsNullExpr) End() Pos { return after(x.Null, len("NULL")) }
+
+type ExistsExpr struct {
+	Query  *SubqueryExpr
+	Exists Pos
+}
+
+func (ExistsExpr) exprTag() {}
+
+func (x *ExistsExpr) Pos() Pos { return x.Exists }
+func (x *ExistsExpr) End() Pos { return x.Query.End() }
+
+type LabelExpr struct {
+	Arg     Expr
+	Plural  bool // LABELS rather than LABEL.
+	FuncPos Pos
+	Rparen  Pos
+}
+
+func (LabelExpr) exprTag() {}
+
+func (x *LabelExpr) Pos() Pos { return x.FuncPos }
+func (x *LabelExpr) End() Pos { return after(x.Rparen, 1) }
+
 type CallExpr struct {
-	Func *QIdent
-	Args []Expr
+	Func   *QIdent
+	Args   []Expr
+	Rparen Pos
 }
 
 func (CallExpr) exprTag() {}
 
+func (x *CallExpr) Pos() Pos { return x.Func.Pos() }
+func (x *CallExpr) End() Pos { return after(x.Rparen, 1) }
+
 type CastExpr struct {
 	Arg      Expr
-	TypeKind int
+	TypeKind DataType
+	Cast     Pos
+	Rparen   Pos
 }
 
 func (CastExpr) exprTag() {}
 
+func (x *CastExpr) Pos() Pos { return x.Cast }
+func (x *CastExpr) End() Pos { return after(x.Rparen, 1) }
+
 type CaseExpr struct {
 	Subject Expr
 	Else    Expr
 	Whens   []*WhenClause
+	Case    Pos
+	EndPos  Pos // Position after END.
 }
 
 func (CaseExpr) exprTag() {}
 
+func (x *CaseExpr) Pos() Pos { return x.Case }
+func (x *CaseExpr) End() Pos { return x.EndPos }
+
 type WhenClause struct {
 	Cond Expr
 	Then Expr
+	When Pos
 }
 
+func (c *WhenClause) Pos() Pos { return c.When }
+func (c *WhenClause) End() Pos { return c.Then.End() }
+
 type InExpr struct {
 	Subject Expr
-	Objects []Expr // If empty, a bind variable exists.
+	Objects []Expr
+	BindVar *BindVar // Instead of Objects, for "IN ?".
 	Inv     bool
+	Rparen  Pos // Position of ")", or NoPos with a BindVar.
 }
 
 func (InExpr) exprTag() {}
 
+func (x *InExpr) Pos() Pos { return x.Subject.Pos() }
+func (x *InExpr) End() Pos {
+	if x.BindVar != nil {
+		return x.BindVar.End()
+	}
+	return after(x.Rparen, 1)
+}
+
 type SubqueryExpr struct {
-	Query *SelectStmt
+	Query  *SelectStmt
+	Lparen Pos
+	Rparen Pos
 }
 
 func (SubqueryExpr) exprTag() {}
 
+func (x *SubqueryExpr) Pos() Pos { return x.Lparen }
+func (x *SubqueryExpr) End() Pos { return after(x.Rparen, 1) }
+
 type NamedExpr struct {
 	Expr Expr
 	Name *Ident
 }
 
+func (x *NamedExpr) Pos() Pos { return x.Expr.Pos() }
+
+func (x *NamedExpr) End() Pos {
+	if x.Name != nil {
+		return x.Name.End()
+	}
+	return x.Expr.End()
+}
+
 type QIdent struct {
 	Names []*Ident
 }
 
 func (QIdent) exprTag() {}
 
+func (x *QIdent) Pos() Pos { return x.Names[0].Pos() }
+func (x *QIdent) End() Pos { return lastEnd(x.Names) }
+
 type Ident struct {
-	Name string
-	Pos  Pos
+	Name    string
+	NamePos Pos
+	EndPos  Pos // Position after the identifier, which may be quoted.
 }
 
 func (Ident) exprTag() {}
 
+func (x *Ident) Pos() Pos { return x.NamePos }
+func (x *Ident) End() Pos { return x.EndPos }
+
 type BasicLit struct {
-	S    string
-	Kind LitKind
-	Pos  Pos
+	S string
+
+	// Text is the decoded value of the quoted string in string, date,
+	// time, timestamp and interval literals.
+	Text string
+
+	Kind     LitKind
+	ValuePos Pos // Position of the literal, or its type keyword.
+	EndPos   Pos
 }
 
 func (BasicLit) exprTag() {}
 
+func (x *BasicLit) Pos() Pos { return x.ValuePos }
+func (x *BasicLit) End() Pos { return x.EndPos }
+
 type LitKind int
 
 const (
@@ -98,6 +242,12 @@ const (
 	IntervalKind
 )
 
-type BindVar struct{}
+type BindVar struct {
+	Index int // Zero-based ordinal of the "?" in the statement.
+	Qmark Pos // Position of "?".
+}
 
 func (BindVar) exprTag() {}
+
+func (x *BindVar) Pos() Pos { return x.Qmark }
+func (x *BindVar) End() Pos { return after(x.Qmark, 1) }
@@ -0,0 +1,88 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+)
+
+// A Comment is a single /*-style comment.
+type Comment struct {
+	Slash Pos    // Position of the "/" starting the comment.
+	Text  string // Comment text, including the delimiters.
+}
+
+// A CommentGroup is a sequence of comments with no tokens and no
+// empty lines between them.
+type CommentGroup struct {
+	List []*Comment
+}
+
+func (c *Comment) Pos() Pos { return c.Slash }
+func (c *Comment) End() Pos { return after(c.Slash, len([]rune(c.Text))) }
+
+// Pos returns the position of the first comment.
+func (g *CommentGroup) Pos() Pos {
+	return g.List[0].Pos()
+}
+
+// End returns the position after the last comment.
+func (g *CommentGroup) End() Pos {
+	return g.List[len(g.List)-1].End()
+}
+
+// Text returns the text of the comments, with the comment delimiters
+// and leading and trailing empty lines removed. Comments are separated
+// by newlines.
+func (g *CommentGroup) Text() string {
+	if g == nil {
+		return ""
+	}
+
+	var lines []string
+	for _, c := range g.List {
+		s := strings.TrimSuffix(strings.TrimPrefix(c.Text, "/*"), "*/")
+		for _, l := range strings.Split(s, "\n") {
+			lines = append(lines, strings.TrimRight(l, " \t"))
+		}
+	}
+
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// A CommentMap maps an AST node to the comment groups preceding it.
+// The parser associates a comment group with the outermost node
+// starting at the token following it.
+type CommentMap map[Node][]*CommentGroup
+
+// Comments returns all comment groups in the map, in source order.
+func (cmap CommentMap) Comments() []*CommentGroup {
+	var gs []*CommentGroup
+	for _, l := range cmap {
+		gs = append(gs, l...)
+	}
+	sort.Slice(gs, func(i, j int) bool { return gs[i].Pos() < gs[j].Pos() })
+	return gs
+}
+
+// Filter returns a new map containing only the entries for node and
+// the nodes reachable from it.
+func (cmap CommentMap) Filter(node Node) CommentMap {
+	ret := CommentMap{}
+	Inspect(node, func(n Node) bool {
+		if gs, ok := cmap[n]; ok {
+			ret[n] = gs
+		}
+		return true
+	})
+	return ret
+}
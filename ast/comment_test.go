@@ -0,0 +1,31 @@
+package ast
+
+import "testing"
+
+func TestCommentGroupText(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input []string
+		Want  string
+	}{
+		{"empty", []string{"/**/"}, ""},
+		{"single", []string{"/* abc */"}, " abc\n"},
+		{"multi", []string{"/*\n  abc\n  def  \n\n*/"}, "  abc\n  def\n"},
+		{"list", []string{"/* abc */", "/* def */"}, " abc\n def\n"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			var g CommentGroup
+			for _, s := range tst.Input {
+				g.List = append(g.List, &Comment{Text: s})
+			}
+
+			if got := g.Text(); got != tst.Want {
+				t.Errorf("Text: got %q, want %q", got, tst.Want)
+			}
+		})
+	}
+}
@@ -4,22 +4,58 @@ type CreateStmt struct {
 	GraphName    *QIdent
 	VertexTables []*VertexTableDecl
 	EdgeTables   []*EdgeTableDecl
+	Create       Pos
+	Rparen       Pos // Position of the last ")".
 }
 
 func (CreateStmt) stmtTag() {}
 
+func (s *CreateStmt) Pos() Pos { return s.Create }
+func (s *CreateStmt) End() Pos { return after(s.Rparen, 1) }
+
 type DropStmt struct {
 	GraphName *QIdent
+	Drop      Pos
 }
 
 func (DropStmt) stmtTag() {}
 
+func (s *DropStmt) Pos() Pos { return s.Drop }
+func (s *DropStmt) End() Pos { return s.GraphName.End() }
+
 type VertexTableDecl struct {
 	TableName  *QIdent
 	TableAlias *Ident
 	Label      *Ident
 	Props      *PropsClause
 	Keys       []*Ident
+	KeyRparen  Pos // Position of ")" after Keys, if any.
+}
+
+func (d *VertexTableDecl) Pos() Pos { return d.TableName.Pos() }
+
+func (d *VertexTableDecl) End() Pos {
+	switch {
+	case d.Props != nil:
+		return d.Props.End()
+	case d.Label != nil:
+		return d.Label.End()
+	default:
+		return tableEnd(d.TableName, d.TableAlias, d.KeyRparen)
+	}
+}
+
+// tableEnd returns the end of the table name, alias and keys shared
+// by VertexTableDecl and EdgeTableDecl.
+func tableEnd(name *QIdent, alias *Ident, keyRparen Pos) Pos {
+	switch {
+	case keyRparen.IsValid():
+		return after(keyRparen, 1)
+	case alias != nil:
+		return alias.End()
+	default:
+		return name.End()
+	}
 }
 
 type EdgeTableDecl struct {
@@ -30,18 +66,52 @@ type EdgeTableDecl struct {
 	Label      *Ident
 	Props      *PropsClause
 	Keys       []*Ident
+	KeyRparen  Pos // Position of ")" after Keys, if any.
+}
+
+func (d *EdgeTableDecl) Pos() Pos { return d.TableName.Pos() }
+
+func (d *EdgeTableDecl) End() Pos {
+	switch {
+	case d.Props != nil:
+		return d.Props.End()
+	case d.Label != nil:
+		return d.Label.End()
+	case d.Dest != nil:
+		return d.Dest.End()
+	case d.Source != nil:
+		return d.Source.End()
+	default:
+		return tableEnd(d.TableName, d.TableAlias, d.KeyRparen)
+	}
 }
 
 type PropsClause struct {
-	Except []*Ident    // Valid iff None is false.
-	Exprs  []*PropExpr // Valid iff None is false and Except is empty.
-	None   bool
+	Except     []*Ident    // Valid iff None is false.
+	Exprs      []*PropExpr // Valid iff None is false and Except is empty.
+	None       bool
+	Properties Pos // Position of PROPERTIES, or NO.
+	EndPos     Pos // Position after PROPERTIES, COLUMNS or ")".
 }
 
+func (c *PropsClause) Pos() Pos { return c.Properties }
+func (c *PropsClause) End() Pos { return c.EndPos }
+
 type VertexTableRef struct {
 	Keys      []*Ident
 	TableName *QIdent
 	Columns   []*Ident
+	Keyword   Pos // Position of SOURCE or DESTINATION.
+	Rparen    Pos // Position of ")" after Columns, if any.
+}
+
+func (r *VertexTableRef) Pos() Pos { return r.Keyword }
+
+func (r *VertexTableRef) End() Pos {
+	if r.Rparen.IsValid() {
+		return after(r.Rparen, 1)
+	}
+	return r.TableName.End()
 }
 
 type PropExpr struct {
@@ -49,3 +119,21 @@ type PropExpr struct {
 	Column *Ident
 	CastAs *CastExpr // The 1.5 spec suggests that the ValueExpression in the cast can only be a simple column name.
 }
+
+func (x *PropExpr) Pos() Pos {
+	if x.CastAs != nil {
+		return x.CastAs.Pos()
+	}
+	return x.Column.Pos()
+}
+
+func (x *PropExpr) End() Pos {
+	switch {
+	case x.Name != nil:
+		return x.Name.End()
+	case x.CastAs != nil:
+		return x.CastAs.End()
+	default:
+		return x.Column.End()
+	}
+}
@@ -0,0 +1,138 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itergia/pgql-go/token"
+)
+
+// Value decodes the literal into a Go value:
+//
+//   - UIntKind: int64, or *big.Int if it overflows.
+//   - UDecKind: float64, or *big.Rat if it overflows.
+//   - BoolKind: bool.
+//   - StringKind: string.
+//   - DateKind: time.Time, at midnight UTC.
+//   - TimeKind: time.Time, on January 1 of year 0.
+//   - TimestampKind: time.Time.
+//   - IntervalKind: Interval.
+//
+// Times and timestamps without a time zone are in time.UTC. Those
+// with one are in a time.FixedZone, even if the offset is zero.
+func (x *BasicLit) Value() (any, error) {
+	switch x.Kind {
+	case UIntKind:
+		i, err := strconv.ParseInt(x.S, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			if bi, ok := new(big.Int).SetString(x.S, 10); ok {
+				return bi, nil
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer literal %s", x.S)
+		}
+		return i, nil
+
+	case UDecKind:
+		f, err := strconv.ParseFloat(x.S, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			if r, ok := new(big.Rat).SetString(x.S); ok {
+				return r, nil
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid decimal literal %s", x.S)
+		}
+		return f, nil
+
+	case BoolKind:
+		b, err := strconv.ParseBool(x.S)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean literal %s", x.S)
+		}
+		return b, nil
+
+	case StringKind:
+		return token.UnquoteString(x.S)
+
+	case DateKind:
+		return parseTime("DATE", x.S, "2006-01-02")
+
+	case TimeKind:
+		return parseTime("TIME", x.S, "15:04:05", "15:04:05Z07:00")
+
+	case TimestampKind:
+		return parseTime("TIMESTAMP", x.S, "2006-01-02 15:04:05", "2006-01-02 15:04:05Z07:00")
+
+	case IntervalKind:
+		return parseInterval(x.S)
+
+	default:
+		return nil, fmt.Errorf("unknown literal kind %d", x.Kind)
+	}
+}
+
+// parseTime parses the quoted string s using the first matching
+// layout. A layout with a time zone must come after the one without.
+func parseTime(typ, s string, layouts ...string) (time.Time, error) {
+	str, err := token.UnquoteString(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for i, layout := range layouts {
+		t, err := time.Parse(layout, str)
+		if err != nil {
+			continue
+		}
+		if i > 0 {
+			// time.Parse uses time.Local if the offset matches.
+			_, off := t.Zone()
+			t = t.In(time.FixedZone("", off))
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid %s literal %s", typ, s)
+}
+
+// An Interval is the value of an INTERVAL literal, like "INTERVAL
+// '2' DAY".
+type Interval struct {
+	N     int64
+	Field DateTimeField
+}
+
+// String returns the interval as written in PGQL, without the
+// INTERVAL keyword.
+func (iv Interval) String() string {
+	return fmt.Sprintf("'%d' %v", iv.N, iv.Field)
+}
+
+// parseInterval parses an IntervalKind literal, like "'2' DAY".
+func parseInterval(s string) (Interval, error) {
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		return Interval{}, fmt.Errorf("invalid INTERVAL literal %s", s)
+	}
+
+	var iv Interval
+	for f := YearField; f <= SecondField; f++ {
+		if s[i+1:] == f.String() {
+			iv.Field = f
+		}
+	}
+	str, err := token.UnquoteString(s[:i])
+	if err != nil || iv.Field == UnknownDateTimeField {
+		return Interval{}, fmt.Errorf("invalid INTERVAL literal %s", s)
+	}
+	iv.N, err = strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid INTERVAL literal %s", s)
+	}
+	return iv, nil
+}
@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBasicLitValue(t *testing.T) {
+	bigInt, _ := new(big.Int).SetString("9223372036854775808", 10)
+	bigRatS := "1" + strings.Repeat("0", 400) + ".5"
+	bigRat, _ := new(big.Rat).SetString(bigRatS)
+
+	tsts := []struct {
+		Name  string
+		Input BasicLit
+		Want  any
+	}{
+		{"uint", BasicLit{S: "42", Kind: UIntKind}, int64(42)},
+		{"bigUint", BasicLit{S: "9223372036854775808", Kind: UIntKind}, bigInt},
+		{"udec", BasicLit{S: "4.5", Kind: UDecKind}, 4.5},
+		{"udecTrailingPeriod", BasicLit{S: "4.", Kind: UDecKind}, 4.0},
+		{"bigUdec", BasicLit{S: bigRatS, Kind: UDecKind}, bigRat},
+		{"bool", BasicLit{S: "false", Kind: BoolKind}, false},
+		{"string", BasicLit{S: "'it''s'", Kind: StringKind}, "it's"},
+		{"date", BasicLit{S: "'2000-01-02'", Kind: DateKind}, time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"time", BasicLit{S: "'12:34:56.5'", Kind: TimeKind}, time.Date(0, 1, 1, 12, 34, 56, 5e8, time.UTC)},
+		{"timeTZ", BasicLit{S: "'12:34:56+01:00'", Kind: TimeKind}, time.Date(0, 1, 1, 12, 34, 56, 0, time.FixedZone("", 3600))},
+		{"timestamp", BasicLit{S: "'2000-01-02 12:34:56'", Kind: TimestampKind}, time.Date(2000, 1, 2, 12, 34, 56, 0, time.UTC)},
+		{"timestampZ", BasicLit{S: "'2000-01-02 12:34:56Z'", Kind: TimestampKind}, time.Date(2000, 1, 2, 12, 34, 56, 0, time.FixedZone("", 0))},
+		{"interval", BasicLit{S: "'-3' DAY", Kind: IntervalKind}, Interval{N: -3, Field: DayField}},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tst.Input.Value()
+			if err != nil {
+				t.Fatalf("Value failed: %v", err)
+			}
+			opts := cmp.Options{
+				cmp.Comparer(func(a, b *big.Int) bool { return a.Cmp(b) == 0 }),
+				cmp.Comparer(func(a, b *big.Rat) bool { return a.Cmp(b) == 0 }),
+				cmp.Comparer(func(a, b time.Time) bool {
+					_, aoff := a.Zone()
+					_, boff := b.Zone()
+					return a.Equal(b) && aoff == boff && (a.Location() == time.UTC) == (b.Location() == time.UTC)
+				}),
+			}
+			if diff := cmp.Diff(tst.Want, got, opts); diff != "" {
+				t.Errorf("Value: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBasicLitValueError(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input BasicLit
+		Want  string
+	}{
+		{"date", BasicLit{S: "'2000-13-01'", Kind: DateKind}, "invalid DATE literal '2000-13-01'"},
+		{"time", BasicLit{S: "'25:00:00'", Kind: TimeKind}, "invalid TIME literal '25:00:00'"},
+		{"timestamp", BasicLit{S: "'2000-01-02'", Kind: TimestampKind}, "invalid TIMESTAMP literal '2000-01-02'"},
+		{"interval", BasicLit{S: "'a' DAY", Kind: IntervalKind}, "invalid INTERVAL literal 'a' DAY"},
+		{"intervalField", BasicLit{S: "'1' WEEK", Kind: IntervalKind}, "invalid INTERVAL literal '1' WEEK"},
+		{"unknown", BasicLit{S: "x"}, "unknown literal kind 0"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tst.Input.Value()
+			if err == nil || err.Error() != tst.Want {
+				t.Errorf("Value: got error %v, want %q", err, tst.Want)
+			}
+		})
+	}
+}
@@ -14,39 +14,89 @@ type ModifyStmt struct {
 
 func (ModifyStmt) stmtTag() {}
 
+func (s *ModifyStmt) Pos() Pos {
+	if len(s.PathMacros) > 0 {
+		return s.PathMacros[0].Pos()
+	}
+	return s.Mods[0].Pos()
+}
+
+func (s *ModifyStmt) End() Pos {
+	end := lastEnd(s.Mods)
+	if len(s.From) > 0 {
+		end = lastEnd(s.From)
+	}
+	return tailEnd(end, s.Where, s.GroupBy, s.Having, s.OrderBy, s.Limit, s.Offset)
+}
+
 type ModClause interface {
+	Node
 	modTag()
 }
 
 type InsertClause struct {
-	Into *QIdent
-	Vs   []*VertexInsertion
-	Es   []*EdgeInsertion
+	Into   *QIdent
+	Vs     []*VertexInsertion
+	Es     []*EdgeInsertion
+	Insert Pos
 }
 
 func (InsertClause) modTag() {}
 
+func (c *InsertClause) Pos() Pos { return c.Insert }
+
+// End returns the end of the last insertion. Vertices and edges can
+// be interleaved.
+func (c *InsertClause) End() Pos { return maxPos(lastEnd(c.Vs), lastEnd(c.Es)) }
+
 type UpdateClause struct {
 	Updates []*Update
+	Update  Pos
 }
 
 func (UpdateClause) modTag() {}
 
+func (c *UpdateClause) Pos() Pos { return c.Update }
+func (c *UpdateClause) End() Pos { return lastEnd(c.Updates) }
+
 type Update struct {
-	Var   *Ident
-	Props []*PropAssignment
+	Var    *Ident
+	Props  []*PropAssignment
+	Rparen Pos
 }
 
+func (u *Update) Pos() Pos { return u.Var.Pos() }
+func (u *Update) End() Pos { return after(u.Rparen, 1) }
+
 type DeleteClause struct {
-	Vars []*Ident
+	Vars   []*Ident
+	Delete Pos
 }
 
 func (DeleteClause) modTag() {}
 
+func (c *DeleteClause) Pos() Pos { return c.Delete }
+func (c *DeleteClause) End() Pos { return lastEnd(c.Vars) }
+
 type VertexInsertion struct {
 	Var    *Ident
 	Labels []*Ident
 	Props  []*PropAssignment
+	Vertex Pos
+	Rparen Pos // Position of the last ")", if any.
+}
+
+func (x *VertexInsertion) Pos() Pos { return x.Vertex }
+
+func (x *VertexInsertion) End() Pos {
+	switch {
+	case x.Rparen.IsValid():
+		return after(x.Rparen, 1)
+	case x.Var != nil:
+		return x.Var.End()
+	default:
+		return after(x.Vertex, len("VERTEX"))
+	}
 }
 
 type PropAssignment struct {
@@ -54,10 +104,24 @@ type PropAssignment struct {
 	Value Expr
 }
 
+func (a *PropAssignment) Pos() Pos { return a.Prop.Pos() }
+func (a *PropAssignment) End() Pos { return a.Value.End() }
+
 type EdgeInsertion struct {
 	Var    *Ident
 	Source *Ident
 	Dest   *Ident
 	Labels []*Ident
 	Props  []*PropAssignment
+	Edge   Pos
+	Rparen Pos // Position of the last ")", if any.
+}
+
+func (x *EdgeInsertion) Pos() Pos { return x.Edge }
+
+func (x *EdgeInsertion) End() Pos {
+	if x.Rparen.IsValid() {
+		return after(x.Rparen, 1)
+	}
+	return x.Dest.End()
 }
@@ -0,0 +1,170 @@
+package ast
+
+import "fmt"
+
+// Op is the operator of an OpExpr.
+type Op int
+
+const (
+	UnknownOp Op = iota
+
+	// Logical operators.
+	OrOp
+	AndOp
+	NotOp
+
+	// Comparisons.
+	EqOp
+	NeOp
+	LtOp
+	GtOp
+	LeOp
+	GeOp
+
+	// Arithmetic and string operators.
+	AddOp
+	SubOp
+	MulOp
+	DivOp
+	ModOp
+	NegOp // Unary minus.
+	ConcatOp
+)
+
+var opStrings = [...]string{
+	OrOp:  "OR",
+	AndOp: "AND",
+	NotOp: "NOT",
+
+	EqOp: "=",
+	NeOp: "<>",
+	LtOp: "<",
+	GtOp: ">",
+	LeOp: "<=",
+	GeOp: ">=",
+
+	AddOp:    "+",
+	SubOp:    "-",
+	MulOp:    "*",
+	DivOp:    "/",
+	ModOp:    "%",
+	NegOp:    "-",
+	ConcatOp: "||",
+}
+
+// String returns the operator as written in PGQL, e.g. "<>".
+func (op Op) String() string {
+	if op > UnknownOp && int(op) < len(opStrings) {
+		return opStrings[op]
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// AggFunc is the function of an AggregateExpr.
+type AggFunc int
+
+const (
+	UnknownAggFunc AggFunc = iota
+	CountFunc
+	MinFunc
+	MaxFunc
+	AvgFunc
+	SumFunc
+	ArrayAggFunc
+	ListaggFunc
+)
+
+var aggFuncStrings = [...]string{
+	CountFunc:    "COUNT",
+	MinFunc:      "MIN",
+	MaxFunc:      "MAX",
+	AvgFunc:      "AVG",
+	SumFunc:      "SUM",
+	ArrayAggFunc: "ARRAY_AGG",
+	ListaggFunc:  "LISTAGG",
+}
+
+// String returns the function name, e.g. "ARRAY_AGG".
+func (f AggFunc) String() string {
+	if f > UnknownAggFunc && int(f) < len(aggFuncStrings) {
+		return aggFuncStrings[f]
+	}
+	return fmt.Sprintf("AggFunc(%d)", int(f))
+}
+
+// DateTimeField is the field of an ExtractExpr.
+type DateTimeField int
+
+const (
+	UnknownDateTimeField DateTimeField = iota
+	YearField
+	MonthField
+	DayField
+	HourField
+	MinuteField
+	SecondField
+	TimezoneHourField
+	TimezoneMinuteField
+)
+
+var dateTimeFieldStrings = [...]string{
+	YearField:           "YEAR",
+	MonthField:          "MONTH",
+	DayField:            "DAY",
+	HourField:           "HOUR",
+	MinuteField:         "MINUTE",
+	SecondField:         "SECOND",
+	TimezoneHourField:   "TIMEZONE_HOUR",
+	TimezoneMinuteField: "TIMEZONE_MINUTE",
+}
+
+// String returns the field as written in PGQL, e.g. "TIMEZONE_HOUR".
+func (f DateTimeField) String() string {
+	if f > UnknownDateTimeField && int(f) < len(dateTimeFieldStrings) {
+		return dateTimeFieldStrings[f]
+	}
+	return fmt.Sprintf("DateTimeField(%d)", int(f))
+}
+
+// DataType is the target type of a CastExpr.
+type DataType int
+
+const (
+	UnknownDataType DataType = iota
+	StringType
+	BooleanType
+	IntegerType
+	IntType
+	LongType
+	FloatType
+	DoubleType
+	DateType
+	TimeType
+	TimeTZType
+	TimestampType
+	TimestampTZType
+)
+
+var dataTypeStrings = [...]string{
+	StringType:      "STRING",
+	BooleanType:     "BOOLEAN",
+	IntegerType:     "INTEGER",
+	IntType:         "INT",
+	LongType:        "LONG",
+	FloatType:       "FLOAT",
+	DoubleType:      "DOUBLE",
+	DateType:        "DATE",
+	TimeType:        "TIME",
+	TimeTZType:      "TIME WITH TIME ZONE",
+	TimestampType:   "TIMESTAMP",
+	TimestampTZType: "TIMESTAMP WITH TIME ZONE",
+}
+
+// String returns the type as written in PGQL, e.g. "TIME WITH TIME
+// ZONE".
+func (t DataType) String() string {
+	if t > UnknownDataType && int(t) < len(dataTypeStrings) {
+		return dataTypeStrings[t]
+	}
+	return fmt.Sprintf("DataType(%d)", int(t))
+}
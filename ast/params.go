@@ -0,0 +1,91 @@
+package ast
+
+import "sort"
+
+// ParamContext is where a bind variable is used.
+type ParamContext int
+
+const (
+	UnknownParamContext ParamContext = iota
+	ValueParam                       // Any other value expression.
+	LimitParam                       // The LIMIT value.
+	OffsetParam                      // The OFFSET value.
+	InListParam                      // The list of IN, or an element of it.
+	ComparisonParam                  // An operand of =, <>, <, >, <= or >=.
+)
+
+var paramContextStrings = [...]string{
+	ValueParam:      "value",
+	LimitParam:      "LIMIT",
+	OffsetParam:     "OFFSET",
+	InListParam:     "IN list",
+	ComparisonParam: "comparison",
+}
+
+// String returns a short description of the context, e.g. "IN list".
+func (pc ParamContext) String() string {
+	if pc > UnknownParamContext && int(pc) < len(paramContextStrings) {
+		return paramContextStrings[pc]
+	}
+	return "unknown"
+}
+
+// A Param is a bind variable of a statement, and the context where it
+// is used.
+type Param struct {
+	Var     *BindVar
+	Context ParamContext
+
+	// Operand is the other operand of a comparison, or the subject of
+	// an IN predicate.
+	Operand Expr
+
+	// Op is the operator of a comparison.
+	Op Op
+}
+
+// Params returns the bind variables of the node, including those in
+// subqueries, sorted by index.
+func Params(n Node) []*Param {
+	var ps []*Param
+	Apply(n, func(c *Cursor) bool {
+		v, ok := c.Node().(*BindVar)
+		if !ok {
+			return true
+		}
+
+		p := &Param{Var: v, Context: ValueParam}
+		switch parent := c.Parent().(type) {
+		case *SelectStmt, *ModifyStmt:
+			switch c.Name() {
+			case "Limit":
+				p.Context = LimitParam
+			case "Offset":
+				p.Context = OffsetParam
+			}
+
+		case *InExpr:
+			if c.Name() != "Subject" {
+				p.Context = InListParam
+				p.Operand = parent.Subject
+			}
+
+		case *OpExpr:
+			if isComparison(parent.Op) && len(parent.Args) == 2 && c.Index() >= 0 {
+				p.Context = ComparisonParam
+				p.Operand = parent.Args[1-c.Index()]
+				p.Op = parent.Op
+			}
+		}
+		ps = append(ps, p)
+		return true
+	}, nil)
+
+	sort.SliceStable(ps, func(i, j int) bool { return ps[i].Var.Index < ps[j].Var.Index })
+	return ps
+}
+
+// isComparison returns true for the comparison operators.
+func isComparison(op Op) bool {
+	return op >= EqOp && op <= GeOp
+}
@@ -0,0 +1,78 @@
+package ast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestParams(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{
+			"none",
+			"SELECT a FROM MATCH (a)",
+			nil,
+		},
+		{
+			"contexts",
+			"SELECT a, ? FROM MATCH (a) WHERE a.x = ? AND ? < a.y AND a.z IN ? AND a.w NOT IN (1, ?) LIMIT ? OFFSET ?",
+			[]string{
+				"0@11 value",
+				"1@40 comparison = a.x",
+				"2@46 comparison < a.y",
+				"3@65 IN list a.z",
+				"4@86 IN list a.w",
+				"5@95 LIMIT",
+				"6@104 OFFSET",
+			},
+		},
+		{
+			"subquery",
+			"SELECT a FROM MATCH (a) WHERE EXISTS (SELECT b FROM MATCH (a) -> (b) WHERE b.x > ?) AND a.y = ?",
+			[]string{
+				"0@82 comparison > b.x",
+				"1@95 comparison = a.y",
+			},
+		},
+		{
+			"inSubject",
+			"SELECT a FROM MATCH (a) WHERE ? IN (1, 2)",
+			[]string{"0@31 value"},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmt, err := parser.ParseStatement(tst.Input)
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+
+			src := []rune(tst.Input)
+			var got []string
+			for _, p := range ast.Params(stmt) {
+				s := fmt.Sprintf("%d@%d %v", p.Var.Index, p.Var.Pos(), p.Context)
+				if p.Op != ast.UnknownOp {
+					s += " " + p.Op.String()
+				}
+				if p.Operand != nil {
+					s += " " + string(src[p.Operand.Pos()-1:p.Operand.End()-1])
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Params: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
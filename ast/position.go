@@ -0,0 +1,98 @@
+package ast
+
+import (
+	"sort"
+
+	"github.com/itergia/pgql-go/token"
+)
+
+// Pos is a position in the source: the zero-based UTF-8 codepoint
+// offset, plus one. The zero value is NoPos. Use a File to turn it
+// into a line and column.
+type Pos int
+
+// NoPos is the position of nodes not present in the source, like
+// implicit quantifier bounds.
+const NoPos Pos = 0
+
+// IsValid returns true if the position is not NoPos.
+func (p Pos) IsValid() bool {
+	return p != NoPos
+}
+
+// A File maps positions to lines and columns.
+type File struct {
+	name  string
+	lines []int // Offsets of the first codepoint of each line.
+}
+
+// NewFile returns a File with a single line, which is extended by
+// AddLine as the source is read.
+func NewFile(name string) *File {
+	return &File{name: name, lines: []int{0}}
+}
+
+// Name returns the name given to NewFile.
+func (f *File) Name() string {
+	return f.name
+}
+
+// AddLine records that a line starts at the zero-based offset.
+// Offsets must be increasing, and offsets not larger than the last
+// one are ignored.
+func (f *File) AddLine(offset int) {
+	if offset > f.lines[len(f.lines)-1] {
+		f.lines = append(f.lines, offset)
+	}
+}
+
+// LineCount returns the number of lines seen so far.
+func (f *File) LineCount() int {
+	return len(f.lines)
+}
+
+// Position returns the line and column of p. The zero Position is
+// returned if p is NoPos.
+func (f *File) Position(p Pos) token.Position {
+	if !p.IsValid() {
+		return token.Position{}
+	}
+
+	off := int(p) - 1
+	line := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > off }) - 1
+	return token.Position{
+		Filename: f.name,
+		Offset:   off,
+		Line:     line,
+		Column:   off - f.lines[line],
+	}
+}
+
+// lastEnd returns the end of the last node in the list, or NoPos if
+// the list is empty.
+func lastEnd[N Node](ns []N) Pos {
+	if len(ns) == 0 {
+		return NoPos
+	}
+	return ns[len(ns)-1].End()
+}
+
+// after returns the position following a token of n codepoints at
+// p, or NoPos if p is NoPos.
+func after(p Pos, n int) Pos {
+	if !p.IsValid() {
+		return NoPos
+	}
+	return p + Pos(n)
+}
+
+// maxPos returns the largest position.
+func maxPos(ps ...Pos) Pos {
+	var ret Pos
+	for _, p := range ps {
+		if p > ret {
+			ret = p
+		}
+	}
+	return ret
+}
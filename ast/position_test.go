@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/itergia/pgql-go/token"
+)
+
+func TestFilePosition(t *testing.T) {
+	f := NewFile("a.pgql")
+	// "ab\n\ncd"
+	f.AddLine(3)
+	f.AddLine(4)
+
+	tsts := []struct {
+		Name  string
+		Input Pos
+		Want  token.Position
+	}{
+		{"noPos", NoPos, token.Position{}},
+		{"first", 1, token.Position{Filename: "a.pgql"}},
+		{"newline", 3, token.Position{Filename: "a.pgql", Offset: 2, Column: 2}},
+		{"emptyLine", 4, token.Position{Filename: "a.pgql", Offset: 3, Line: 1}},
+		{"last", 6, token.Position{Filename: "a.pgql", Offset: 5, Line: 2, Column: 1}},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := f.Position(tst.Input); got != tst.Want {
+				t.Errorf("Position: got %+v, want %+v", got, tst.Want)
+			}
+		})
+	}
+}
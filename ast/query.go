@@ -11,26 +11,115 @@ type SelectStmt struct {
 	Offset     Expr
 	OrderBy    []*OrderTerm
 	Distinct   bool
+	Select     Pos
 }
 
 func (SelectStmt) stmtTag() {}
 
+func (s *SelectStmt) Pos() Pos {
+	if len(s.PathMacros) > 0 {
+		return s.PathMacros[0].Pos()
+	}
+	return s.Select
+}
+
+func (s *SelectStmt) End() Pos {
+	// FROM is only missing in partial statements returned on errors.
+	end := maxPos(after(s.Select, len("SELECT")), lastEnd(s.Sels))
+	if len(s.From) > 0 {
+		end = lastEnd(s.From)
+	}
+	return tailEnd(end, s.Where, s.GroupBy, s.Having, s.OrderBy, s.Limit, s.Offset)
+}
+
+// tailEnd returns the end of the clauses following FROM, which are
+// shared by SelectStmt and ModifyStmt, or end if there are none.
+func tailEnd(end Pos, where Expr, groupBy []*NamedExpr, having Expr, orderBy []*OrderTerm, limit, offset Expr) Pos {
+	if where != nil {
+		end = where.End()
+	}
+	if len(groupBy) > 0 {
+		end = lastEnd(groupBy)
+	}
+	if having != nil {
+		end = having.End()
+	}
+	if len(orderBy) > 0 {
+		end = lastEnd(orderBy)
+	}
+	// LIMIT and OFFSET can come in any order.
+	if limit != nil {
+		end = limit.End()
+	}
+	if offset != nil {
+		end = maxPos(end, offset.End())
+	}
+	return end
+}
+
 type PathMacroClause struct {
 	Name    *Ident
 	Pattern *PathPattern
 	Where   Expr
+	Path    Pos
+}
+
+func (c *PathMacroClause) Pos() Pos { return c.Path }
+
+func (c *PathMacroClause) End() Pos {
+	if c.Where != nil {
+		return c.Where.End()
+	}
+	return c.Pattern.End()
 }
 
 type SelectElem struct {
 	Named  *NamedExpr
 	AllOf  *Ident
 	Prefix *BasicLit
+	Star   Pos // Position of "*" following AllOf.
+}
+
+func (e *SelectElem) Pos() Pos {
+	if e.AllOf != nil {
+		return e.AllOf.Pos()
+	}
+	return e.Named.Pos()
+}
+
+func (e *SelectElem) End() Pos {
+	switch {
+	case e.Prefix != nil:
+		return e.Prefix.End()
+	case e.AllOf != nil:
+		return after(e.Star, 1)
+	default:
+		return e.Named.End()
+	}
 }
 
 type MatchClause struct {
 	On       *QIdent
 	Rows     *MatchRows
 	Patterns []*PathPattern
+	Match    Pos
+	Lparen   Pos // Position of "(" around the patterns, if any.
+	Rparen   Pos // Position of ")" around the patterns, if any.
+}
+
+func (c *MatchClause) Pos() Pos { return c.Match }
+
+func (c *MatchClause) End() Pos {
+	switch {
+	case c.Rows != nil:
+		return c.Rows.End()
+	case c.On != nil:
+		return c.On.End()
+	case c.Rparen.IsValid():
+		return after(c.Rparen, 1)
+	default:
+		return lastEnd(c.Patterns)
+	}
 }
 
 type PathPattern struct {
@@ -39,6 +128,22 @@ type PathPattern struct {
 	Es          []*PathPatternPrimary
 	Cardinality Cardinality
 	Metric      Metric
+	CardPos     Pos // Position of ANY, ALL or TOP, if any.
+	Rparen      Pos // Position of ")" after the path, if any.
+}
+
+func (p *PathPattern) Pos() Pos {
+	if p.CardPos.IsValid() {
+		return p.CardPos
+	}
+	return p.Vs[0].Pos()
+}
+
+func (p *PathPattern) End() Pos {
+	if p.Rparen.IsValid() {
+		return after(p.Rparen, 1)
+	}
+	return lastEnd(p.Vs)
 }
 
 type Cardinality int
@@ -64,20 +169,49 @@ type PathPatternPrimary struct {
 	Cost     Expr
 	Vs       []*VertexPattern
 	Es       []*EdgePattern
+	Lparen   Pos // Position of "(", if parenthesized.
+	Rparen   Pos // Position of ")", if parenthesized.
+}
+
+func (p *PathPatternPrimary) Pos() Pos {
+	if p.Lparen.IsValid() {
+		return p.Lparen
+	}
+	return p.Es[0].Pos()
+}
+
+// End returns the end of the primary, including its quantifier, which
+// is inside the edge pattern of a reachability expression.
+func (p *PathPatternPrimary) End() Pos {
+	end := maxPos(after(p.Rparen, 1), lastEnd(p.Es))
+	if p.Quantity != nil {
+		end = maxPos(end, p.Quantity.End())
+	}
+	return end
 }
 
 type VertexPattern struct {
 	Name      *Ident
 	LabelAlts []*Ident
+	Lparen    Pos
+	Rparen    Pos
 }
 
+func (p *VertexPattern) Pos() Pos { return p.Lparen }
+func (p *VertexPattern) End() Pos { return after(p.Rparen, 1) }
+
 type EdgePattern struct {
 	Name         *Ident
 	LabelAlts    []*Ident
 	Dir          Dir
 	Reachability bool
+	EdgePos      Pos // Position of the first token, like "-[" or "<-/".
+	EndPos       Pos // Position after the last token, like "]->" or "/-".
 }
 
+func (p *EdgePattern) Pos() Pos { return p.EdgePos }
+func (p *EdgePattern) End() Pos { return p.EndPos }
+
 type Dir int
 
 const (
@@ -87,10 +221,15 @@ const (
 )
 
 type MatchRows struct {
-	Vars []*Ident
-	Kind MatchRowsKind
+	Vars   []*Ident
+	Kind   MatchRowsKind
+	One    Pos
+	EndPos Pos // Position after MATCH or ")".
 }
 
+func (r *MatchRows) Pos() Pos { return r.One }
+func (r *MatchRows) End() Pos { return r.EndPos }
+
 type MatchRowsKind int
 
 const (
@@ -101,8 +240,22 @@ const (
 )
 
 type OrderTerm struct {
-	Expr  Expr
-	Order Order
+	Expr     Expr
+	Order    Order
+	OrderPos Pos // Position of ASC or DESC, if any.
+}
+
+func (t *OrderTerm) Pos() Pos { return t.Expr.Pos() }
+
+func (t *OrderTerm) End() Pos {
+	switch t.Order {
+	case AscOrder:
+		return after(t.OrderPos, len("ASC"))
+	case DescOrder:
+		return after(t.OrderPos, len("DESC"))
+	default:
+		return t.Expr.End()
+	}
 }
 
 type Order int
@@ -116,4 +269,9 @@ const (
 type Quantifier struct {
 	Min, Max *BasicLit
 	Group    bool
+	Lbrace   Pos // Position of "{", or of the single character quantifier.
+	Rbrace   Pos // Position of "}", or of the single character quantifier.
 }
+
+func (q *Quantifier) Pos() Pos { return q.Lbrace }
+func (q *Quantifier) End() Pos { return after(q.Rbrace, 1) }
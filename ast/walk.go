@@ -0,0 +1,421 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Node is any node in the AST. It is one of the Stmt, Expr or
+// ModClause implementations, or a pointer to one of the auxiliary
+// structs, like *MatchClause or *PathPattern.
+type Node interface {
+	Pos() Pos // Position of the first character of the node.
+	End() Pos // Position of the character immediately after the node.
+}
+
+// A Visitor's Visit method is invoked for each node encountered by
+// Walk. If the result visitor w is not nil, Walk visits each of the
+// children of node with the visitor w, followed by a call of
+// w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order, in the order the nodes
+// appear in the source. It starts by calling v.Visit(node); node must
+// not be nil. If the visitor w returned by v.Visit(node) is not nil,
+// Walk is invoked recursively with visitor w for each of the non-nil
+// children of node, followed by a call of w.Visit(nil).
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	// Statements.
+
+	case *SelectStmt:
+		walkList(v, n.PathMacros)
+		walkList(v, n.Sels)
+		walkList(v, n.From)
+		walkTail(v, n.Where, n.GroupBy, n.Having, n.OrderBy, n.Limit, n.Offset)
+
+	case *ModifyStmt:
+		walkList(v, n.PathMacros)
+		walkList(v, n.Mods)
+		walkList(v, n.From)
+		walkTail(v, n.Where, n.GroupBy, n.Having, n.OrderBy, n.Limit, n.Offset)
+
+	case *CreateStmt:
+		if n.GraphName != nil {
+			Walk(v, n.GraphName)
+		}
+		walkList(v, n.VertexTables)
+		walkList(v, n.EdgeTables)
+
+	case *DropStmt:
+		if n.GraphName != nil {
+			Walk(v, n.GraphName)
+		}
+
+	// Graph definitions.
+
+	case *VertexTableDecl:
+		if n.TableName != nil {
+			Walk(v, n.TableName)
+		}
+		if n.TableAlias != nil {
+			Walk(v, n.TableAlias)
+		}
+		walkList(v, n.Keys)
+		if n.Label != nil {
+			Walk(v, n.Label)
+		}
+		if n.Props != nil {
+			Walk(v, n.Props)
+		}
+
+	case *EdgeTableDecl:
+		if n.TableName != nil {
+			Walk(v, n.TableName)
+		}
+		if n.TableAlias != nil {
+			Walk(v, n.TableAlias)
+		}
+		walkList(v, n.Keys)
+		if n.Source != nil {
+			Walk(v, n.Source)
+		}
+		if n.Dest != nil {
+			Walk(v, n.Dest)
+		}
+		if n.Label != nil {
+			Walk(v, n.Label)
+		}
+		if n.Props != nil {
+			Walk(v, n.Props)
+		}
+
+	case *PropsClause:
+		walkList(v, n.Except)
+		walkList(v, n.Exprs)
+
+	case *VertexTableRef:
+		walkList(v, n.Keys)
+		if n.TableName != nil {
+			Walk(v, n.TableName)
+		}
+		walkList(v, n.Columns)
+
+	case *PropExpr:
+		if n.Column != nil {
+			Walk(v, n.Column)
+		}
+		if n.CastAs != nil {
+			Walk(v, n.CastAs)
+		}
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+
+	// Queries.
+
+	case *PathMacroClause:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Pattern != nil {
+			Walk(v, n.Pattern)
+		}
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+
+	case *SelectElem:
+		if n.Named != nil {
+			Walk(v, n.Named)
+		}
+		if n.AllOf != nil {
+			Walk(v, n.AllOf)
+		}
+		if n.Prefix != nil {
+			Walk(v, n.Prefix)
+		}
+
+	case *MatchClause:
+		walkList(v, n.Patterns)
+		if n.On != nil {
+			Walk(v, n.On)
+		}
+		if n.Rows != nil {
+			Walk(v, n.Rows)
+		}
+
+	case *PathPattern:
+		if n.K != nil {
+			Walk(v, n.K)
+		}
+		walkPath(v, n.Vs, n.Es)
+
+	case *PathPatternPrimary:
+		walkPath(v, n.Vs, n.Es)
+		if n.Where != nil {
+			Walk(v, n.Where)
+		}
+		if n.Cost != nil {
+			Walk(v, n.Cost)
+		}
+		if n.Quantity != nil {
+			Walk(v, n.Quantity)
+		}
+
+	case *VertexPattern:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		walkList(v, n.LabelAlts)
+
+	case *EdgePattern:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		walkList(v, n.LabelAlts)
+
+	case *MatchRows:
+		walkList(v, n.Vars)
+
+	case *OrderTerm:
+		if n.Expr != nil {
+			Walk(v, n.Expr)
+		}
+
+	case *Quantifier:
+		if n.Min != nil {
+			Walk(v, n.Min)
+		}
+		if n.Max != nil {
+			Walk(v, n.Max)
+		}
+
+	// Modifications.
+
+	case *InsertClause:
+		if n.Into != nil {
+			Walk(v, n.Into)
+		}
+		walkList(v, n.Vs)
+		walkList(v, n.Es)
+
+	case *UpdateClause:
+		walkList(v, n.Updates)
+
+	case *DeleteClause:
+		walkList(v, n.Vars)
+
+	case *Update:
+		if n.Var != nil {
+			Walk(v, n.Var)
+		}
+		walkList(v, n.Props)
+
+	case *VertexInsertion:
+		if n.Var != nil {
+			Walk(v, n.Var)
+		}
+		walkList(v, n.Labels)
+		walkList(v, n.Props)
+
+	case *EdgeInsertion:
+		if n.Var != nil {
+			Walk(v, n.Var)
+		}
+		if n.Source != nil {
+			Walk(v, n.Source)
+		}
+		if n.Dest != nil {
+			Walk(v, n.Dest)
+		}
+		walkList(v, n.Labels)
+		walkList(v, n.Props)
+
+	case *PropAssignment:
+		if n.Prop != nil {
+			Walk(v, n.Prop)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	// Expressions.
+
+	case *OpExpr:
+		walkList(v, n.Args)
+
+	case *AggregateExpr:
+		if n.Arg != nil {
+			Walk(v, n.Arg)
+		}
+		if n.Separator != nil {
+			Walk(v, n.Separator)
+		}
+
+	case *ExtractExpr:
+		if n.Arg != nil {
+			Walk(v, n.Arg)
+		}
+
+	case *SubstringExpr:
+		if n.Arg != nil {
+			Walk(v, n.Arg)
+		}
+		if n.Start != nil {
+			Walk(v, n.Start)
+		}
+		if n.Length != nil {
+			Walk(v, n.Length)
+		}
+
+	case *IsNullExpr:
+		if n.Arg != nil {
+			Walk(v, n.Arg)
+		}
+
+	case *ExistsExpr:
+		if n.Query != nil {
+			Walk(v, n.Query)
+		}
+
+	case *LabelExpr:
+		if n.Arg != nil {
+			Walk(v, n.Arg)
+		}
+
+	case *CallExpr:
+		if n.Func != nil {
+			Walk(v, n.Func)
+		}
+		walkList(v, n.Args)
+
+	case *CastExpr:
+		if n.Arg != nil {
+			Walk(v, n.Arg)
+		}
+
+	case *CaseExpr:
+		if n.Subject != nil {
+			Walk(v, n.Subject)
+		}
+		walkList(v, n.Whens)
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+
+	case *WhenClause:
+		if n.Cond != nil {
+			Walk(v, n.Cond)
+		}
+		if n.Then != nil {
+			Walk(v, n.Then)
+		}
+
+	case *InExpr:
+		if n.Subject != nil {
+			Walk(v, n.Subject)
+		}
+		walkList(v, n.Objects)
+		if n.BindVar != nil {
+			Walk(v, n.BindVar)
+		}
+
+	case *SubqueryExpr:
+		if n.Query != nil {
+			Walk(v, n.Query)
+		}
+
+	case *NamedExpr:
+		if n.Expr != nil {
+			Walk(v, n.Expr)
+		}
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+
+	case *QIdent:
+		walkList(v, n.Names)
+
+	case *Ident, *BasicLit, *BindVar:
+		// Leaves.
+
+	default:
+		panic(fmt.Errorf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+// walkList walks the non-nil elements of a list of nodes.
+func walkList[N Node](v Visitor, list []N) {
+	for _, n := range list {
+		if !isNil(n) {
+			Walk(v, n)
+		}
+	}
+}
+
+// walkPath walks vertices and edges of a path, interleaved in source
+// order. Vertices of a PathPatternPrimary may be nil.
+func walkPath[E Node](v Visitor, vs []*VertexPattern, es []E) {
+	for i := 0; i < len(vs) || i < len(es); i++ {
+		if i < len(vs) && vs[i] != nil {
+			Walk(v, vs[i])
+		}
+		if i < len(es) && !isNil(es[i]) {
+			Walk(v, es[i])
+		}
+	}
+}
+
+// isNil returns true if n is nil, or holds a nil pointer.
+func isNil(n Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// walkTail walks the clauses following FROM, which are shared by
+// SelectStmt and ModifyStmt.
+func walkTail(v Visitor, where Expr, groupBy []*NamedExpr, having Expr, orderBy []*OrderTerm, limit, offset Expr) {
+	if where != nil {
+		Walk(v, where)
+	}
+	walkList(v, groupBy)
+	if having != nil {
+		Walk(v, having)
+	}
+	walkList(v, orderBy)
+	if limit != nil {
+		Walk(v, limit)
+	}
+	if offset != nil {
+		Walk(v, offset)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling
+// f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the non-nil children of node, followed by a
+// call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,148 @@
+package ast_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestInspect(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{
+			"select",
+			"SELECT a.x AS y FROM MATCH (a:L) -[e]-> (b) WHERE a.x > 1 ORDER BY y LIMIT 2;",
+			[]string{"a", "x", "y", "a", "L", "e", "b", "a", "x", "1", "y", "2"},
+		},
+		{
+			"subquery",
+			"SELECT a FROM MATCH (a) WHERE EXISTS (SELECT b FROM MATCH (a) -> (b));",
+			[]string{"a", "a", "b", "a", "b"},
+		},
+		{
+			"pathMacro",
+			"PATH p AS (x) -> (y) WHERE x.z = 'q' SELECT * FROM MATCH (a) -/:p*/-> (b);",
+			[]string{"p", "x", "y", "x", "z", "'q'", "a", "p", "b"},
+		},
+		{
+			"primary",
+			"SELECT * FROM MATCH ANY CHEAPEST (a) ((x) -[e]-> (y) WHERE e.w > 0 COST e.w)* (b);",
+			[]string{"a", "x", "e", "y", "e", "w", "0", "e", "w", "b"},
+		},
+		{
+			"case",
+			"SELECT CASE a WHEN 1 THEN b ELSE c END, f(d) FROM MATCH (a) WHERE a NOT IN (e, g);",
+			[]string{"a", "1", "b", "c", "f", "d", "a", "a", "e", "g"},
+		},
+		{
+			"functions",
+			"SELECT COUNT(*), LISTAGG(DISTINCT a.x, ';'), EXTRACT(YEAR FROM b), SUBSTRING(c FROM 1 FOR 2), LABEL(d) FROM MATCH (a) WHERE a.y IS NOT NULL;",
+			[]string{"a", "x", "';'", "b", "c", "1", "2", "d", "a", "a", "y"},
+		},
+		{
+			"modify",
+			"INSERT VERTEX v LABELS (L) PROPERTIES (v.p = 1), EDGE e BETWEEN v AND w UPDATE w SET (w.q = 2) DELETE x FROM MATCH (w), MATCH (x);",
+			[]string{"v", "L", "v", "p", "1", "e", "v", "w", "w", "w", "q", "2", "x", "w", "x"},
+		},
+		{
+			"create",
+			"CREATE PROPERTY GRAPH g VERTEX TABLES (t KEY (k) LABEL l PROPERTIES (c AS p)) EDGE TABLES (u SOURCE KEY (s) REFERENCES t (k) DESTINATION t);",
+			[]string{"g", "t", "k", "l", "c", "p", "u", "s", "t", "k", "t"},
+		},
+		{
+			"drop",
+			"DROP PROPERTY GRAPH s.g;",
+			[]string{"s", "g"},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := parser.Parse(strings.NewReader(tst.Input))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			var got []string
+			depth := 0
+			ast.Inspect(stmts.Stmts[0], func(n ast.Node) bool {
+				if n == nil {
+					depth--
+					return false
+				}
+				depth++
+
+				switch n := n.(type) {
+				case *ast.Ident:
+					got = append(got, n.Name)
+				case *ast.BasicLit:
+					got = append(got, n.S)
+				}
+				return true
+			})
+
+			if depth != 0 {
+				t.Errorf("Inspect: unbalanced nil calls: %d", depth)
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Inspect: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInspectNilElements(t *testing.T) {
+	stmt := &ast.SelectStmt{
+		Sels: []*ast.SelectElem{nil, {Named: &ast.NamedExpr{Expr: &ast.CallExpr{
+			Func: &ast.QIdent{Names: []*ast.Ident{{Name: "f"}, nil}},
+			Args: []ast.Expr{(*ast.Ident)(nil), &ast.Ident{Name: "a"}, nil},
+		}}}},
+		OrderBy: []*ast.OrderTerm{nil},
+	}
+
+	var got []string
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if n != nil {
+			got = append(got, fmt.Sprintf("%T", n))
+		}
+		return true
+	})
+
+	want := []string{"*ast.SelectStmt", "*ast.SelectElem", "*ast.NamedExpr", "*ast.CallExpr", "*ast.QIdent", "*ast.Ident", "*ast.Ident"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Inspect: +got, -want:\n%s", diff)
+	}
+}
+
+func TestInspectPrune(t *testing.T) {
+	stmts, err := parser.Parse(strings.NewReader("SELECT a FROM MATCH (a) WHERE EXISTS (SELECT b FROM MATCH (b));"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var got []string
+	ast.Inspect(stmts.Stmts[0], func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SubqueryExpr:
+			got = append(got, fmt.Sprintf("%T", n))
+			return false
+		case *ast.Ident:
+			got = append(got, n.Name)
+		}
+		return true
+	})
+
+	if diff := cmp.Diff([]string{"a", "a", "*ast.SubqueryExpr"}, got); diff != "" {
+		t.Errorf("Inspect: +got, -want:\n%s", diff)
+	}
+}
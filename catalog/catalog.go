@@ -0,0 +1,230 @@
+// Package catalog tracks property graphs declared by CREATE PROPERTY
+// GRAPH statements.
+//
+// The catalog only knows what the statements declare. Without a
+// database, the columns of tables declared with PROPERTIES ARE ALL
+// COLUMNS, and the types of properties without a CAST, are unknown.
+//
+// Names are compared exactly, like in package sema.
+package catalog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
+)
+
+// A Catalog is a set of property graphs. The zero value is an empty
+// catalog.
+type Catalog struct {
+	// DefaultSchema is the schema of graphs named without one.
+	DefaultSchema string
+
+	graphs map[graphKey]*Graph
+}
+
+type graphKey struct {
+	schema, name string
+}
+
+// New returns an empty catalog, using the default schema for graphs
+// named without one.
+func New(defaultSchema string) *Catalog {
+	return &Catalog{DefaultSchema: defaultSchema}
+}
+
+// Graph returns the graph with the given name, like in a MATCH ON
+// clause, or nil.
+func (c *Catalog) Graph(name *ast.QIdent) *Graph {
+	k, ok := c.key(name)
+	if !ok {
+		return nil
+	}
+	return c.graphs[k]
+}
+
+// LookupGraph returns the graph with the given schema and name, or
+// nil. An empty schema is the default schema.
+func (c *Catalog) LookupGraph(schema, name string) *Graph {
+	if schema == "" {
+		schema = c.DefaultSchema
+	}
+	return c.graphs[graphKey{schema, name}]
+}
+
+// Graphs returns all graphs, sorted by schema and name.
+func (c *Catalog) Graphs() []*Graph {
+	gs := make([]*Graph, 0, len(c.graphs))
+	for _, g := range c.graphs {
+		gs = append(gs, g)
+	}
+	sort.Slice(gs, func(i, j int) bool {
+		if gs[i].Schema != gs[j].Schema {
+			return gs[i].Schema < gs[j].Schema
+		}
+		return gs[i].Name < gs[j].Name
+	})
+	return gs
+}
+
+// key returns the key of a graph name, which has at most two parts.
+func (c *Catalog) key(name *ast.QIdent) (graphKey, bool) {
+	switch len(name.Names) {
+	case 1:
+		return graphKey{c.DefaultSchema, name.Names[0].Name}, true
+	case 2:
+		return graphKey{name.Names[0].Name, name.Names[1].Name}, true
+	default:
+		return graphKey{}, false
+	}
+}
+
+// A Graph is a property graph, mapping tables to vertices and edges.
+type Graph struct {
+	Schema string
+	Name   string
+
+	// Tables holds the vertex tables, followed by the edge tables, in
+	// declaration order.
+	Tables []*Table
+
+	Decl *ast.CreateStmt
+
+	labels map[string][]*Table
+}
+
+// String returns the schema-qualified name of the graph, quoted if
+// needed.
+func (g *Graph) String() string {
+	if g.Schema == "" {
+		return token.QuoteIdentifier(g.Name)
+	}
+	return token.QuoteIdentifier(g.Schema) + "." + token.QuoteIdentifier(g.Name)
+}
+
+// Table returns the table with the given name, or nil.
+func (g *Graph) Table(name string) *Table {
+	for _, t := range g.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// LabelTables returns the tables with the given label, in declaration
+// order. They are all vertex tables, or all edge tables.
+func (g *Graph) LabelTables(label string) []*Table {
+	return g.labels[label]
+}
+
+// Labels returns the labels of the tables of the given kind, sorted.
+func (g *Graph) Labels(kind TableKind) []string {
+	var ls []string
+	for l, ts := range g.labels {
+		if ts[0].Kind == kind {
+			ls = append(ls, l)
+		}
+	}
+	sort.Strings(ls)
+	return ls
+}
+
+// TableKind is the kind of elements a Table holds.
+type TableKind int
+
+const (
+	VertexTable TableKind = iota
+	EdgeTable
+)
+
+// String returns the kind in lower-case, e.g. "vertex".
+func (k TableKind) String() string {
+	if k == EdgeTable {
+		return "edge"
+	}
+	return "vertex"
+}
+
+// A Table is a vertex or edge table of a graph.
+type Table struct {
+	// Name is the table alias, or the last part of the table name.
+	// It is unique in the graph.
+	Name string
+
+	// TableName is the possibly schema-qualified table name.
+	TableName []string
+
+	Kind  TableKind
+	Label string // The LABEL, or Name.
+
+	// Keys are the key columns, or nil for the primary key.
+	Keys []string
+
+	// Props are the declared properties. If AllColumns is true, they
+	// are the columns of the table, except those in Except, and are
+	// unknown.
+	Props      []*Property
+	AllColumns bool
+	Except     []string
+
+	// Source and Dest are the endpoints of edges. They are nil for
+	// vertex tables.
+	Source, Dest *Reference
+
+	// Decl is an *ast.VertexTableDecl or *ast.EdgeTableDecl.
+	Decl ast.Node
+}
+
+// Property returns the declared property with the given name, or nil.
+func (t *Table) Property(name string) *Property {
+	for _, p := range t.Props {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// A Property is a column exposed as a property.
+type Property struct {
+	Name   string
+	Column string
+
+	// Type is the type of a CAST, or UnknownDataType.
+	Type ast.DataType
+}
+
+// A Reference is the SOURCE or DESTINATION of an edge table.
+type Reference struct {
+	Table *Table
+
+	// Keys are the columns of the edge table, and Columns the
+	// referenced columns of Table. Both are nil without a KEY clause,
+	// when they come from a foreign key of the edge table.
+	Keys    []string
+	Columns []string
+}
+
+// names returns the names of identifiers.
+func names(ids []*ast.Ident) []string {
+	if ids == nil {
+		return nil
+	}
+	ss := make([]string, len(ids))
+	for i, id := range ids {
+		ss[i] = id.Name
+	}
+	return ss
+}
+
+// qualifiedName returns a QIdent as written, quoted if needed.
+func qualifiedName(ss []string) string {
+	qs := make([]string, len(ss))
+	for i, s := range ss {
+		qs[i] = token.QuoteIdentifier(s)
+	}
+	return strings.Join(qs, ".")
+}
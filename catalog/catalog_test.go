@@ -0,0 +1,207 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestExec(t *testing.T) {
+	c := New("s")
+	exec := func(s string) {
+		t.Helper()
+
+		stmt, err := parser.ParseStatement(s)
+		if err != nil {
+			t.Fatalf("ParseStatement failed: %v", err)
+		}
+		if err := c.Exec(nil, stmt); err != nil {
+			t.Fatalf("Exec(%q) failed: %v", s, err)
+		}
+	}
+
+	exec("CREATE PROPERTY GRAPH g VERTEX TABLES (t AS u KEY (k) LABEL l PROPERTIES (c AS p, CAST(d AS INT) AS q), v NO PROPERTIES, x.w LABEL l PROPERTIES (e AS p, CAST(f AS INT) AS q)) EDGE TABLES (e SOURCE KEY (s) REFERENCES t (k) DESTINATION v PROPERTIES ARE ALL COLUMNS EXCEPT (y))")
+	exec("CREATE PROPERTY GRAPH o.h VERTEX TABLES (t)")
+	exec("CREATE PROPERTY GRAPH o.k VERTEX TABLES (t KEY (a)) EDGE TABLES (e KEY (b, c) SOURCE t DESTINATION t)")
+
+	g := c.LookupGraph("", "g")
+	if g == nil {
+		t.Fatalf("LookupGraph(g) = nil, want a graph")
+	}
+	if got, want := g.String(), "s.g"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+
+	var got []string
+	for _, tbl := range g.Tables {
+		s := fmt.Sprintf("%s %v %v label=%s keys=%v", tbl.Name, tbl.Kind, tbl.TableName, tbl.Label, tbl.Keys)
+		for _, p := range tbl.Props {
+			s += fmt.Sprintf(" %s=%s:%v", p.Name, p.Column, p.Type)
+		}
+		if tbl.AllColumns {
+			s += fmt.Sprintf(" all except %v", tbl.Except)
+		}
+		if tbl.Source != nil {
+			s += fmt.Sprintf(" %v->%s %v->%s", tbl.Source.Keys, tbl.Source.Table.Name, tbl.Dest.Keys, tbl.Dest.Table.Name)
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"u vertex [t] label=l keys=[k] p=c:DataType(0) q=d:INT",
+		"v vertex [v] label=v keys=[]",
+		"w vertex [x w] label=l keys=[] p=e:DataType(0) q=f:INT",
+		"e edge [e] label=e keys=[] all except [y] [s]->u []->v",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tables: +got, -want:\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"l", "v"}, g.Labels(VertexTable)); diff != "" {
+		t.Errorf("Labels: +got, -want:\n%s", diff)
+	}
+	if got := len(g.LabelTables("l")); got != 2 {
+		t.Errorf("LabelTables(l): got %d tables, want 2", got)
+	}
+
+	var gs []string
+	for _, g := range c.Graphs() {
+		gs = append(gs, g.String())
+	}
+	if diff := cmp.Diff([]string{"o.h", "o.k", "s.g"}, gs); diff != "" {
+		t.Errorf("Graphs: +got, -want:\n%s", diff)
+	}
+
+	exec("DROP PROPERTY GRAPH s.g")
+	if g := c.LookupGraph("s", "g"); g != nil {
+		t.Errorf("LookupGraph(s.g) after DROP: got %v, want nil", g)
+	}
+}
+
+func TestExecSpec(t *testing.T) {
+	des, err := os.ReadDir("../parser/testdata/spec")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	for _, de := range des {
+		if !strings.HasSuffix(de.Name(), ".pgql") {
+			continue
+		}
+
+		de := de
+		t.Run(de.Name(), func(t *testing.T) {
+			t.Parallel()
+
+			bs, err := os.ReadFile(filepath.Join("../parser/testdata/spec", de.Name()))
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			if !strings.HasPrefix(string(bs), "CREATE PROPERTY GRAPH") {
+				t.Skip("not a CREATE PROPERTY GRAPH statement")
+			}
+
+			stmt, err := parser.ParseStatement(string(bs))
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+			if err := New("").Exec(nil, stmt); err != nil {
+				t.Errorf("Exec failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestExecError(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{
+			"exists",
+			"CREATE PROPERTY GRAPH g VERTEX TABLES (t)",
+			"at 1:23: graph g already exists",
+		},
+		{
+			"dropMissing",
+			"DROP PROPERTY GRAPH h",
+			"at 1:21: graph h does not exist",
+		},
+		{
+			"tableName",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t, u AS t)",
+			"at 1:48: table name t is already used in graph h",
+		},
+		{
+			"labelKinds",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t) EDGE TABLES (e SOURCE t DESTINATION t LABEL t)",
+			"at 1:87: label t is used by both vertex and edge tables",
+		},
+		{
+			"labelProps",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t LABEL l PROPERTIES (a), u LABEL l PROPERTIES (b))",
+			"at 1:74: label l has different properties in tables t and u",
+		},
+		{
+			"references",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t) EDGE TABLES (e SOURCE u DESTINATION t)",
+			"at 1:65: u is not a vertex table of graph h",
+		},
+		{
+			"referencesColumns",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t) EDGE TABLES (e SOURCE KEY (a, b) REFERENCES t (c) DESTINATION t)",
+			"at 1:87: REFERENCES t has 1 columns, but KEY has 2",
+		},
+		{
+			"except",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t PROPERTIES ARE ALL COLUMNS EXCEPT (a, b, a))",
+			"at 1:83: duplicate EXCEPT column a",
+		},
+		{
+			"property",
+			"CREATE PROPERTY GRAPH h VERTEX TABLES (t PROPERTIES (a, b AS a))",
+			"at 1:62: duplicate property a",
+		},
+		{
+			"query",
+			"SELECT a FROM MATCH (a)",
+			"at 1:1: not a CREATE or DROP PROPERTY GRAPH statement",
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			c := New("")
+			stmt, err := parser.ParseStatement("CREATE PROPERTY GRAPH g VERTEX TABLES (t)")
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+			if err := c.Exec(nil, stmt); err != nil {
+				t.Fatalf("Exec failed: %v", err)
+			}
+
+			stmt, err = parser.ParseStatement(tst.Input)
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+			err = c.Exec(nil, stmt)
+			if err == nil {
+				t.Fatalf("Exec succeeded, want %q", tst.Want)
+			}
+			if got := err.Error(); got != tst.Want {
+				t.Errorf("Exec: got %q, want %q", got, tst.Want)
+			}
+			if got := len(c.Graphs()); got != 1 {
+				t.Errorf("Graphs: got %d graphs, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package catalog
+
+import (
+	"fmt"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/sema"
+)
+
+// Exec applies a CREATE or DROP PROPERTY GRAPH statement. The file
+// maps positions in errors to lines and columns, and may be nil, like
+// in sema.Check. On errors, the catalog is unchanged, and the error
+// is a sema.ErrorList.
+func (c *Catalog) Exec(file *ast.File, stmt ast.Stmt) error {
+	if file == nil {
+		file = ast.NewFile("")
+	}
+	x := executor{file: file}
+
+	switch s := stmt.(type) {
+	case *ast.CreateStmt:
+		g := x.create(c, s)
+		if len(x.errs) > 0 {
+			break
+		}
+		if c.graphs == nil {
+			c.graphs = map[graphKey]*Graph{}
+		}
+		c.graphs[graphKey{g.Schema, g.Name}] = g
+
+	case *ast.DropStmt:
+		k, ok := c.key(s.GraphName)
+		switch {
+		case !ok:
+			x.errorf(s.GraphName, "invalid graph name %s", qualifiedName(names(s.GraphName.Names)))
+		case c.graphs[k] == nil:
+			x.errorf(s.GraphName, "graph %s does not exist", qualifiedName(names(s.GraphName.Names)))
+		default:
+			delete(c.graphs, k)
+		}
+
+	default:
+		x.errorf(stmt, "not a CREATE or DROP PROPERTY GRAPH statement")
+	}
+
+	if len(x.errs) > 0 {
+		x.errs.Sort()
+		return x.errs
+	}
+	return nil
+}
+
+type executor struct {
+	file *ast.File
+	errs sema.ErrorList
+}
+
+func (x *executor) errorf(n ast.Node, format string, args ...any) {
+	x.errs = append(x.errs, &sema.Error{Pos: x.file.Position(n.Pos()), Msg: fmt.Sprintf(format, args...)})
+}
+
+// create returns the graph declared by s.
+func (x *executor) create(c *Catalog, s *ast.CreateStmt) *Graph {
+	g := &Graph{Decl: s, labels: map[string][]*Table{}}
+	if k, ok := c.key(s.GraphName); ok {
+		g.Schema, g.Name = k.schema, k.name
+		if c.graphs[k] != nil {
+			x.errorf(s.GraphName, "graph %s already exists", g)
+		}
+	} else {
+		x.errorf(s.GraphName, "invalid graph name %s", qualifiedName(names(s.GraphName.Names)))
+	}
+
+	for _, d := range s.VertexTables {
+		t := x.table(g, VertexTable, d, d.TableName, d.TableAlias, d.Label, d.Keys, d.Props)
+		g.Tables = append(g.Tables, t)
+	}
+	for _, d := range s.EdgeTables {
+		t := x.table(g, EdgeTable, d, d.TableName, d.TableAlias, d.Label, d.Keys, d.Props)
+		t.Source = x.reference(g, d.Source)
+		t.Dest = x.reference(g, d.Dest)
+		g.Tables = append(g.Tables, t)
+	}
+
+	return g
+}
+
+// table returns the table declared by d, which is added to the labels
+// of g.
+func (x *executor) table(g *Graph, kind TableKind, d ast.Node, tableName *ast.QIdent, alias, label *ast.Ident, keys []*ast.Ident, props *ast.PropsClause) *Table {
+	t := &Table{
+		TableName:  names(tableName.Names),
+		Kind:       kind,
+		Keys:       names(keys),
+		AllColumns: true,
+		Decl:       d,
+	}
+
+	nameID := tableName.Names[len(tableName.Names)-1]
+	if alias != nil {
+		nameID = alias
+	}
+	t.Name = nameID.Name
+	if g.Table(t.Name) != nil {
+		x.errorf(nameID, "table name %s is already used in graph %s", nameID.Name, g)
+	}
+
+	x.checkDuplicates(keys, "key column")
+	if props != nil {
+		x.props(t, props)
+	}
+
+	t.Label = t.Name
+	labelID := nameID
+	if label != nil {
+		t.Label, labelID = label.Name, label
+	}
+	if ts := g.labels[t.Label]; len(ts) > 0 {
+		switch {
+		case ts[0].Kind != kind:
+			x.errorf(labelID, "label %s is used by both vertex and edge tables", t.Label)
+		case !sameProps(ts[0], t):
+			x.errorf(labelID, "label %s has different properties in tables %s and %s", t.Label, ts[0].Name, t.Name)
+		}
+	}
+	g.labels[t.Label] = append(g.labels[t.Label], t)
+
+	return t
+}
+
+// props sets the properties of t.
+func (x *executor) props(t *Table, c *ast.PropsClause) {
+	switch {
+	case c.None:
+		t.AllColumns = false
+
+	case len(c.Exprs) > 0:
+		t.AllColumns = false
+		seen := map[string]bool{}
+		for _, e := range c.Exprs {
+			p := &Property{}
+			var n ast.Node = e
+			if e.CastAs != nil {
+				col, ok := e.CastAs.Arg.(*ast.Ident)
+				if !ok {
+					x.errorf(e.CastAs.Arg, "cast in a property must be of a column")
+					continue
+				}
+				p.Column, p.Type = col.Name, e.CastAs.TypeKind
+			} else {
+				p.Column = e.Column.Name
+			}
+			p.Name = p.Column
+			if e.Name != nil {
+				p.Name, n = e.Name.Name, e.Name
+			}
+
+			if seen[p.Name] {
+				x.errorf(n, "duplicate property %s", p.Name)
+				continue
+			}
+			seen[p.Name] = true
+			t.Props = append(t.Props, p)
+		}
+
+	default:
+		x.checkDuplicates(c.Except, "EXCEPT column")
+		t.Except = names(c.Except)
+	}
+}
+
+// reference returns the endpoint of edge table t.
+func (x *executor) reference(g *Graph, r *ast.VertexTableRef) *Reference {
+	if r == nil {
+		return nil
+	}
+
+	ref := &Reference{Keys: names(r.Keys), Columns: names(r.Columns)}
+	ref.Table = vertexTable(g, names(r.TableName.Names))
+	if ref.Table == nil {
+		x.errorf(r.TableName, "%s is not a vertex table of graph %s", qualifiedName(names(r.TableName.Names)), g)
+	}
+
+	x.checkDuplicates(r.Keys, "key column")
+	if r.Keys != nil && len(r.Keys) != len(r.Columns) {
+		x.errorf(r.TableName, "REFERENCES %s has %d columns, but KEY has %d", qualifiedName(names(r.TableName.Names)), len(r.Columns), len(r.Keys))
+	}
+
+	return ref
+}
+
+// vertexTable returns the vertex table of g named by a REFERENCES
+// clause. It is either the table name (its alias, if any), the label
+// of a single vertex table, or the name of the underlying table.
+func vertexTable(g *Graph, name []string) *Table {
+	if len(name) == 1 {
+		if t := g.Table(name[0]); t != nil {
+			if t.Kind == VertexTable {
+				return t
+			}
+			return nil
+		}
+		if ts := g.labels[name[0]]; len(ts) == 1 && ts[0].Kind == VertexTable {
+			return ts[0]
+		}
+	}
+	for _, t := range g.Tables {
+		if t.Kind == VertexTable && equalNames(t.TableName, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// checkDuplicates reports identifiers appearing twice.
+func (x *executor) checkDuplicates(ids []*ast.Ident, what string) {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id.Name] {
+			x.errorf(id, "duplicate %s %s", what, id.Name)
+		}
+		seen[id.Name] = true
+	}
+}
+
+// sameProps returns true if the tables may share a label. Properties
+// are only known if they are declared.
+func sameProps(a, b *Table) bool {
+	if a.AllColumns || b.AllColumns {
+		return true
+	}
+	if len(a.Props) != len(b.Props) {
+		return false
+	}
+	for _, p := range a.Props {
+		q := b.Property(p.Name)
+		if q == nil || q.Type != p.Type {
+			return false
+		}
+	}
+	return true
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
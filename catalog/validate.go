@@ -0,0 +1,285 @@
+package catalog
+
+import (
+	"sort"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/sema"
+)
+
+// Validate checks a query or modification against the graphs of the
+// catalog, using the variables resolved by sema.Check. The graph def
+// is used by MATCH clauses without ON, and may be nil, in which case
+// they are not checked. Subqueries without ON use the graph of the
+// first MATCH clause of the enclosing query.
+//
+// It reports unknown graphs, labels that no table of the right kind
+// has, property accesses that no possible table of the variable
+// defines, and insertions that match no table. The file may be nil,
+// like in sema.Check, and the error is a sema.ErrorList.
+func (c *Catalog) Validate(file *ast.File, stmt ast.Stmt, info *sema.Info, def *Graph) error {
+	v := c.validate(file, stmt, info, def)
+	if len(v.errs) > 0 {
+		v.errs.Sort()
+		return v.errs
+	}
+	return nil
+}
+
+// Tables returns the tables each vertex and edge variable of stmt can
+// be bound to, given its labels, in declaration order. Variables that
+// can be bound to any table, or only to unknown graphs, have no entry.
+// The arguments are those of Validate.
+func (c *Catalog) Tables(stmt ast.Stmt, info *sema.Info, def *Graph) map[*sema.Object][]*Table {
+	v := c.validate(nil, stmt, info, def)
+
+	m := make(map[*sema.Object][]*Table, len(v.tables))
+	for obj, ts := range v.tables {
+		var l []*Table
+		for t := range ts {
+			l = append(l, t)
+		}
+		sort.Slice(l, func(i, j int) bool { return l[i].Decl.Pos() < l[j].Decl.Pos() })
+		m[obj] = l
+	}
+	return m
+}
+
+func (c *Catalog) validate(file *ast.File, stmt ast.Stmt, info *sema.Info, def *Graph) *validator {
+	if file == nil {
+		file = ast.NewFile("")
+	}
+	v := &validator{
+		executor: executor{file: file},
+		c:        c,
+		info:     info,
+		tables:   map[*sema.Object]tableSet{},
+	}
+
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		v.query(def, s, s.PathMacros, s.From)
+	case *ast.ModifyStmt:
+		g := v.query(def, s, s.PathMacros, s.From)
+		v.modify(g, s)
+	}
+	return v
+}
+
+// A tableSet holds the tables a variable can be bound to.
+type tableSet map[*Table]bool
+
+type validator struct {
+	executor
+
+	c    *Catalog
+	info *sema.Info
+
+	// tables holds the possible tables of vertex and edge variables.
+	// Variables without an entry can be bound to anything.
+	tables map[*sema.Object]tableSet
+}
+
+// query checks the patterns of a query, then its property accesses
+// and subqueries. It returns the graph of the first MATCH clause.
+func (v *validator) query(def *Graph, n ast.Node, macros []*ast.PathMacroClause, from []*ast.MatchClause) *Graph {
+	qdef := def
+	for i, mc := range from {
+		g := def
+		if mc.On != nil {
+			g = v.c.Graph(mc.On)
+			if g == nil {
+				v.errorf(mc.On, "graph %s does not exist", qualifiedName(names(mc.On.Names)))
+			}
+		}
+		if i == 0 {
+			qdef = g
+		}
+		if g == nil {
+			continue
+		}
+		for _, p := range mc.Patterns {
+			v.path(g, p)
+		}
+	}
+	if qdef != nil {
+		for _, m := range macros {
+			v.path(qdef, m.Pattern)
+		}
+	}
+
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.QIdent:
+			v.prop(n)
+			return false
+
+		case *ast.SubqueryExpr:
+			v.query(qdef, n.Query, n.Query.PathMacros, n.Query.From)
+			return false
+		}
+		return true
+	})
+
+	return qdef
+}
+
+// path checks the labels of a path pattern in g.
+func (v *validator) path(g *Graph, p *ast.PathPattern) {
+	for _, vp := range p.Vs {
+		v.bind(v.labels(g, VertexTable, vp.LabelAlts, nil), vp.Name)
+	}
+	for _, pp := range p.Es {
+		for _, vp := range pp.Vs {
+			if vp != nil {
+				v.bind(v.labels(g, VertexTable, vp.LabelAlts, nil), vp.Name)
+			}
+		}
+		for _, e := range pp.Es {
+			v.bind(v.labels(g, EdgeTable, e.LabelAlts, v.info.Macros), e.Name)
+		}
+	}
+}
+
+// labels returns the tables of g with any of the labels, or all
+// tables of the kind if there are none. Labels naming a path macro
+// are skipped.
+func (v *validator) labels(g *Graph, kind TableKind, labels []*ast.Ident, macros map[*ast.Ident]*ast.PathMacroClause) tableSet {
+	ts := tableSet{}
+	if len(labels) == 0 {
+		for _, t := range g.Tables {
+			if t.Kind == kind {
+				ts[t] = true
+			}
+		}
+		return ts
+	}
+
+	for _, l := range labels {
+		if macros[l] != nil {
+			return nil
+		}
+		lts := g.LabelTables(l.Name)
+		if len(lts) == 0 || lts[0].Kind != kind {
+			v.errorf(l, "%s label %s does not exist in graph %s", kind, l.Name, g)
+		}
+		for _, t := range lts {
+			if t.Kind == kind {
+				ts[t] = true
+			}
+		}
+	}
+	return ts
+}
+
+// bind restricts the tables of the variable, if any, to ts. A
+// variable repeated in patterns must match all of them.
+func (v *validator) bind(ts tableSet, id *ast.Ident) {
+	if id == nil || ts == nil {
+		return
+	}
+	obj := v.info.ObjectOf(id)
+	if obj == nil {
+		return
+	}
+
+	old, ok := v.tables[obj]
+	if !ok {
+		v.tables[obj] = ts
+		return
+	}
+	for t := range old {
+		if !ts[t] {
+			delete(old, t)
+		}
+	}
+}
+
+// prop checks a property access, like "a.name".
+func (v *validator) prop(q *ast.QIdent) {
+	if len(q.Names) != 2 {
+		return
+	}
+	obj := v.info.ObjectOf(q.Names[0])
+	ts, ok := v.tables[obj]
+	if !ok || len(ts) == 0 {
+		return
+	}
+
+	name := q.Names[1].Name
+	for t := range ts {
+		if t.AllColumns || t.Property(name) != nil {
+			return
+		}
+	}
+	v.errorf(q.Names[1], "property %s is not defined by any label of %s", name, obj.Name)
+}
+
+// modify checks the insertions of a modification, in the graph of the
+// INTO clause, or g.
+func (v *validator) modify(g *Graph, s *ast.ModifyStmt) {
+	for _, mod := range s.Mods {
+		ins, ok := mod.(*ast.InsertClause)
+		if !ok {
+			continue
+		}
+
+		ig := g
+		if ins.Into != nil {
+			ig = v.c.Graph(ins.Into)
+			if ig == nil {
+				v.errorf(ins.Into, "graph %s does not exist", qualifiedName(names(ins.Into.Names)))
+			}
+		}
+		if ig == nil {
+			continue
+		}
+
+		for _, x := range ins.Vs {
+			v.bind(v.labels(ig, VertexTable, x.Labels, nil), x.Var)
+		}
+		for _, x := range ins.Es {
+			ts := v.labels(ig, EdgeTable, x.Labels, nil)
+			v.bind(ts, x.Var)
+			v.endpoints(x, ts)
+		}
+	}
+
+	// Property assignments are checked after all insertions are bound.
+	for _, mod := range s.Mods {
+		if ins, ok := mod.(*ast.InsertClause); ok {
+			for _, x := range ins.Vs {
+				v.assignments(x.Props)
+			}
+			for _, x := range ins.Es {
+				v.assignments(x.Props)
+			}
+		}
+	}
+}
+
+// endpoints checks that some edge table of ts connects the source and
+// destination of the insertion.
+func (v *validator) endpoints(x *ast.EdgeInsertion, ts tableSet) {
+	src, srcOK := v.tables[v.info.ObjectOf(x.Source)]
+	dst, dstOK := v.tables[v.info.ObjectOf(x.Dest)]
+	if len(ts) == 0 || !srcOK || !dstOK {
+		return
+	}
+
+	for t := range ts {
+		if t.Source != nil && src[t.Source.Table] && t.Dest != nil && dst[t.Dest.Table] {
+			return
+		}
+	}
+	v.errorf(x, "no edge table connects %s to %s", x.Source.Name, x.Dest.Name)
+}
+
+// assignments checks the properties assigned by an insertion. They
+// were not visited by Inspect, since inserted variables were unbound
+// at the time.
+func (v *validator) assignments(props []*ast.PropAssignment) {
+	for _, a := range props {
+		v.prop(a.Prop)
+	}
+}
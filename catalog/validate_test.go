@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/itergia/pgql-go/parser"
+	"github.com/itergia/pgql-go/sema"
+)
+
+func TestValidate(t *testing.T) {
+	c := New("")
+	for _, s := range []string{
+		"CREATE PROPERTY GRAPH g VERTEX TABLES (person PROPERTIES (name, age), company PROPERTIES (name), city) EDGE TABLES (knows SOURCE person DESTINATION person PROPERTIES (since), works SOURCE person DESTINATION company NO PROPERTIES)",
+		"CREATE PROPERTY GRAPH h VERTEX TABLES (t)",
+	} {
+		stmt, err := parser.ParseStatement(s)
+		if err != nil {
+			t.Fatalf("ParseStatement failed: %v", err)
+		}
+		if err := c.Exec(nil, stmt); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+	}
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"ok", "SELECT a.name, b.age, c.x FROM MATCH (a:person|company) -[:knows]-> (b) -> (c:city)", ""},
+		{"on", "SELECT a FROM MATCH (a) ON h", ""},
+		{"subquery", "SELECT a FROM MATCH (a:person) WHERE EXISTS (SELECT b FROM MATCH (a) -[e]-> (b) WHERE e.since > 1)", ""},
+		{"macro", "PATH p AS (x:person) -[:knows]-> (y) SELECT b FROM MATCH (a) -/:p*/-> (b)", ""},
+		{"insert", "INSERT EDGE e BETWEEN a AND b LABELS (works) FROM MATCH (a:person), MATCH (b:company)", ""},
+		{"update", "UPDATE a SET (a.age = 1) FROM MATCH (a:person)", ""},
+
+		{"unknownGraph", "SELECT a FROM MATCH (a) ON x", "at 1:28: graph x does not exist"},
+		{"vertexLabel", "SELECT a FROM MATCH (a:knows)", "at 1:24: vertex label knows does not exist in graph g"},
+		{"edgeLabel", "SELECT a FROM MATCH (a) -[:foo]-> (b)", "at 1:28: edge label foo does not exist in graph g"},
+		{"property", "SELECT a.age FROM MATCH (a:company)", "at 1:10: property age is not defined by any label of a"},
+		{"repeated", "SELECT a.name FROM MATCH (a:person|company), MATCH (a:company) WHERE a.age > 1", "at 1:72: property age is not defined by any label of a"},
+		{"edgeProperty", "SELECT e.since FROM MATCH () -[e:works]-> ()", "at 1:10: property since is not defined by any label of e"},
+		{"insertLabel", "INSERT VERTEX v LABELS (knows)", "at 1:25: vertex label knows does not exist in graph g"},
+		{"insertProperty", "INSERT VERTEX v LABELS (company) PROPERTIES (v.age = 1)", "at 1:48: property age is not defined by any label of v"},
+		{"insertEndpoints", "INSERT EDGE e BETWEEN a AND b LABELS (works) FROM MATCH (a:company), MATCH (b:person)", "at 1:8: no edge table connects a to b"},
+		{"into", "INSERT INTO x VERTEX v", "at 1:13: graph x does not exist"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmt, err := parser.ParseStatement(tst.Input)
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+			info, err := sema.Check(nil, stmt)
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+
+			var got string
+			if err := c.Validate(nil, stmt, info, c.LookupGraph("", "g")); err != nil {
+				got = err.Error()
+			}
+			if got != tst.Want {
+				t.Errorf("Validate: got %q, want %q", got, tst.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Command pgqlfmt formats PGQL files.
+//
+// Without an explicit path, it processes the standard input. Given a
+// file, it operates on that file; given a directory, it operates on
+// all .pgql files in that directory, recursively.
+//
+// Usage:
+//
+//	pgqlfmt [flags] [path ...]
+//
+// The flags are:
+//
+//	-d
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from pgqlfmt's, print diffs
+//		to standard output.
+//	-l
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from pgqlfmt's, print its name
+//		to standard output.
+//	-w
+//		Do not print reformatted sources to standard output.
+//		If a file's formatting is different from pgqlfmt's, overwrite it
+//		with pgqlfmt's version.
+//
+// A file holds a list of statements, each terminated by a semicolon.
+// A file holding a single statement may omit the semicolon, and the
+// output then omits it too.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/itergia/pgql-go/parser"
+	"github.com/itergia/pgql-go/printer"
+)
+
+var (
+	list   = flag.Bool("l", false, "list files whose formatting differs from pgqlfmt's")
+	write  = flag.Bool("w", false, "write result to (source) file instead of stdout")
+	doDiff = flag.Bool("d", false, "display diffs instead of rewriting files")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: pgqlfmt [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+}
+
+// run processes the paths, or the standard input if there are none.
+// It returns the first error, but keeps processing remaining files.
+func run(paths []string) error {
+	if len(paths) == 0 {
+		if *write {
+			return errors.New("cannot use -w with standard input")
+		}
+		return processFile("<standard input>", os.Stdin, os.Stdout)
+	}
+
+	var firstErr error
+	report := func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Explicitly named files are formatted regardless of
+			// their extension.
+			if d.IsDir() || path != root && !isPGQLFile(d) {
+				return nil
+			}
+			if err := processFile(path, nil, os.Stdout); err != nil {
+				report(err)
+			}
+			return nil
+		})
+		if err != nil {
+			report(err)
+		}
+	}
+	if firstErr != nil {
+		return errors.New("pgqlfmt: errors occurred")
+	}
+	return nil
+}
+
+// isPGQLFile returns true for regular files with a .pgql extension.
+func isPGQLFile(d fs.DirEntry) bool {
+	return d.Type().IsRegular() && !strings.HasPrefix(d.Name(), ".") && strings.HasSuffix(d.Name(), ".pgql")
+}
+
+// processFile formats the file. If in is nil, the file is opened.
+// Depending on the flags, the result is written to out, written back
+// to the file, or compared with the original.
+func processFile(filename string, in io.Reader, out io.Writer) error {
+	if in == nil {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	src, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	res, err := format(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if !*list && !*write && !*doDiff {
+		_, err := out.Write(res)
+		return err
+	}
+	if bytes.Equal(src, res) {
+		return nil
+	}
+
+	if *list {
+		fmt.Fprintln(out, filename)
+	}
+	if *write {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, res, fi.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if *doDiff {
+		data, err := diff(src, res, filename)
+		if err != nil {
+			return fmt.Errorf("computing diff: %w", err)
+		}
+		fmt.Fprintf(out, "diff -u %s %s\n", filepath.ToSlash(filename+".orig"), filepath.ToSlash(filename))
+		out.Write(data)
+	}
+	return nil
+}
+
+// format parses src as a list of statements and returns the canonical
+// formatting.
+func format(src []byte) ([]byte, error) {
+	stmts, err := parser.Parse(bytes.NewReader(src))
+	single := false
+	if err != nil {
+		// A single statement may lack the semicolon.
+		stmts2, err2 := parser.Parse(bytes.NewReader(append(append([]byte{}, src...), "\n;"...)))
+		if err2 != nil || len(stmts2.Stmts) != 1 {
+			return nil, err
+		}
+		stmts, single = stmts2, true
+	}
+
+	var buf bytes.Buffer
+	for i, stmt := range stmts.Stmts {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if err := printer.Fprint(&buf, &printer.CommentedNode{Node: stmt, Comments: stmts.Comments}); err != nil {
+			return nil, err
+		}
+		if !single {
+			buf.WriteString(";")
+		}
+		buf.WriteString("\n")
+	}
+
+	// Comments after the last statement.
+	for _, g := range stmts.Comments[stmts] {
+		for _, c := range g.List {
+			buf.WriteString(c.Text)
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// diff returns the output of diff -u between b1 and b2.
+func diff(b1, b2 []byte, filename string) ([]byte, error) {
+	f1, err := writeTempFile("pgqlfmt", b1)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f1)
+
+	f2, err := writeTempFile("pgqlfmt", b2)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f2)
+
+	data, err := exec.Command("diff", "-u", f1, f2).CombinedOutput()
+	if len(data) > 0 {
+		// diff exits with a non-zero status when the files don't
+		// match. Ignore that failure as long as we get output.
+		return replaceTempFilenames(data, filename), nil
+	}
+	return data, err
+}
+
+func writeTempFile(prefix string, data []byte) (string, error) {
+	f, err := os.CreateTemp("", prefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// replaceTempFilenames replaces the temporary file names in the diff
+// header with filename.orig and filename.
+func replaceTempFilenames(diff []byte, filename string) []byte {
+	lines := bytes.SplitN(diff, []byte("\n"), 3)
+	if len(lines) < 3 || !bytes.HasPrefix(lines[0], []byte("--- ")) || !bytes.HasPrefix(lines[1], []byte("+++ ")) {
+		return diff
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n", filepath.ToSlash(filename+".orig"))
+	fmt.Fprintf(&buf, "+++ %s\n", filepath.ToSlash(filename))
+	buf.Write(lines[2])
+	return buf.Bytes()
+}
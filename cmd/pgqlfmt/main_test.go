@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormat(t *testing.T) {
+	fns, err := filepath.Glob("testdata/*.input")
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+
+	for _, fn := range fns {
+		fn := fn
+		t.Run(filepath.Base(fn), func(t *testing.T) {
+			t.Parallel()
+
+			src, err := os.ReadFile(fn)
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			want, err := os.ReadFile(strings.TrimSuffix(fn, ".input") + ".golden")
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+
+			got, err := format(src)
+			if err != nil {
+				t.Fatalf("format failed: %v", err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("format: +got, -want:\n%s", diff)
+			}
+
+			// The output is stable.
+			got2, err := format(got)
+			if err != nil {
+				t.Fatalf("format(format) failed: %v", err)
+			}
+			if diff := cmp.Diff(string(got), string(got2)); diff != "" {
+				t.Errorf("format(format): +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+	}{
+		{"syntax", "SELECT FROM"},
+		{"missingSemicolon", "SELECT n FROM MATCH (n); SELECT m FROM MATCH (m)"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, err := format([]byte(tst.Input)); err == nil {
+				t.Errorf("format: got %q, want error", got)
+			}
+		})
+	}
+}
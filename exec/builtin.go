@@ -0,0 +1,535 @@
+package exec
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+)
+
+// toList returns the values of a list given as a bind variable, which
+// can be any slice.
+func toList(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	l := make([]any, rv.Len())
+	for i := range l {
+		l[i] = normalize(rv.Index(i).Interface(), ast.UnknownDataType)
+	}
+	return l, true
+}
+
+// dateArith adds or subtracts intervals to datetimes. It returns false
+// for other operands, including two intervals.
+func dateArith(op ast.Op, a, b any) (any, bool, error) {
+	if op != ast.AddOp && op != ast.SubOp {
+		return nil, false, nil
+	}
+
+	switch a := a.(type) {
+	case datetime:
+		if iv, ok := b.(ast.Interval); ok {
+			if op == ast.SubOp {
+				iv.N = -iv.N
+			}
+			return addInterval(a, iv), true, nil
+		}
+
+	case ast.Interval:
+		if b, ok := b.(datetime); ok && op == ast.AddOp {
+			return addInterval(b, a), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// addInterval adds an interval to a datetime. Adding years or months
+// keeps the day of the month, or uses the last day of the month if it
+// is shorter. Times of day wrap around midnight, and dates become
+// timestamps when adding hours, minutes or seconds.
+func addInterval(d datetime, iv ast.Interval) datetime {
+	n := int(iv.N)
+	t := d.t
+	switch iv.Field {
+	case ast.YearField, ast.MonthField, ast.DayField:
+		switch {
+		case d.isTime():
+			// Whole days do not change the time of day.
+		case iv.Field == ast.YearField:
+			t = addMonths(t, 12*n)
+		case iv.Field == ast.MonthField:
+			t = addMonths(t, n)
+		default:
+			t = t.AddDate(0, 0, n)
+		}
+		return datetime{t, d.typ}
+	}
+
+	dur := time.Duration(iv.N) * time.Second
+	switch iv.Field {
+	case ast.HourField:
+		dur = time.Duration(iv.N) * time.Hour
+	case ast.MinuteField:
+		dur = time.Duration(iv.N) * time.Minute
+	}
+	switch {
+	case d.isTime():
+		t = t.Add(dur % (24 * time.Hour))
+		return datetime{time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()), d.typ}
+	case d.typ == ast.DateType:
+		return datetime{t.Add(dur), ast.TimestampType}
+	default:
+		return datetime{t.Add(dur), d.typ}
+	}
+}
+
+// addMonths adds months to a time, clamping the day to the end of the
+// month.
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	// The day before the first of the next month.
+	last := time.Date(y, m+time.Month(n)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if d > last {
+		d = last
+	}
+	return time.Date(y, m+time.Month(n), d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// cast converts a non-NULL value to the type of a CAST.
+func (x *executor) cast(e *ast.CastExpr, v any) (any, error) {
+	if s, ok := v.(string); ok && e.TypeKind != ast.StringType {
+		return x.parse(e, strings.TrimSpace(s))
+	}
+
+	switch e.TypeKind {
+	case ast.StringType:
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		case datetime:
+			return formatTime(v), nil
+		case ast.Interval:
+			return v.String(), nil
+		}
+
+	case ast.BooleanType:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+
+	case ast.IntegerType, ast.IntType, ast.LongType:
+		var i int64
+		switch v := v.(type) {
+		case int64:
+			i = v
+		case float64:
+			if !(v >= math.MinInt64 && v < math.MaxInt64) {
+				return nil, x.errorf(e, "%v overflows %s", v, e.TypeKind)
+			}
+			i = int64(v)
+		default:
+			return nil, x.errorf(e, "cannot cast %s to %s", typeName(v), e.TypeKind)
+		}
+		if e.TypeKind != ast.LongType && (i < math.MinInt32 || i > math.MaxInt32) {
+			return nil, x.errorf(e, "%d overflows %s", i, e.TypeKind)
+		}
+		return i, nil
+
+	case ast.FloatType, ast.DoubleType:
+		if f, ok := toFloat(v); ok {
+			return f, nil
+		}
+
+	case ast.DateType, ast.TimeType, ast.TimeTZType, ast.TimestampType, ast.TimestampTZType:
+		// Times of day have no date.
+		toTime := e.TypeKind == ast.TimeType || e.TypeKind == ast.TimeTZType
+		if d, ok := v.(datetime); ok && (!d.isTime() || toTime) && (d.typ != ast.DateType || !toTime) {
+			return castTime(d.t, e.TypeKind), nil
+		}
+	}
+	return nil, x.errorf(e, "cannot cast %s to %s", typeName(v), e.TypeKind)
+}
+
+// parse converts a string to the type of a CAST.
+func (x *executor) parse(e *ast.CastExpr, s string) (any, error) {
+	var v any
+	var err error
+	switch e.TypeKind {
+	case ast.BooleanType:
+		v, err = strconv.ParseBool(strings.ToLower(s))
+	case ast.IntegerType, ast.IntType:
+		v, err = strconv.ParseInt(s, 10, 32)
+	case ast.LongType:
+		v, err = strconv.ParseInt(s, 10, 64)
+	case ast.FloatType, ast.DoubleType:
+		v, err = strconv.ParseFloat(s, 64)
+	case ast.DateType:
+		v, err = parseTime(s, "2006-01-02")
+	case ast.TimeType, ast.TimeTZType:
+		v, err = parseTime(s, "15:04:05", "15:04:05Z07:00")
+	case ast.TimestampType, ast.TimestampTZType:
+		v, err = parseTime(s, "2006-01-02 15:04:05", "2006-01-02 15:04:05Z07:00", "2006-01-02")
+	}
+	if err != nil || v == nil {
+		return nil, x.errorf(e, "cannot cast %q to %s", s, e.TypeKind)
+	}
+	if t, ok := v.(time.Time); ok {
+		return castTime(t, e.TypeKind), nil
+	}
+	return v, nil
+}
+
+// parseTime parses a datetime using the first matching layout. Times
+// with an offset are in a time.FixedZone, like literals.
+func parseTime(s string, layouts ...string) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			if strings.Contains(layout, "Z07") {
+				_, off := t.Zone()
+				t = t.In(time.FixedZone("", off))
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// castTime converts a time to a datetime of a temporal type.
+func castTime(t time.Time, dt ast.DataType) datetime {
+	return datetime{castTimeRepr(t, dt), dt}
+}
+
+// castTimeRepr returns the representation of a time in a temporal
+// type, as described for datetime.
+func castTimeRepr(t time.Time, dt ast.DataType) time.Time {
+	zoned := t.Location() != time.UTC
+	switch dt {
+	case ast.DateType:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	case ast.TimeType, ast.TimeTZType:
+		loc := time.UTC
+		if dt == ast.TimeTZType {
+			loc = t.Location()
+			if !zoned {
+				loc = time.FixedZone("", 0)
+			}
+		} else if zoned {
+			t = t.UTC()
+		}
+		return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+
+	case ast.TimestampType:
+		return t.UTC()
+
+	default:
+		if !zoned {
+			return t.In(time.FixedZone("", 0))
+		}
+		return t
+	}
+}
+
+// formatTime formats a datetime like the literal of its type.
+func formatTime(d datetime) string {
+	var layout string
+	switch d.typ {
+	case ast.DateType:
+		return d.t.Format("2006-01-02")
+	case ast.TimeType, ast.TimeTZType:
+		layout = "15:04:05.999999999"
+	default:
+		layout = "2006-01-02 15:04:05.999999999"
+	}
+	if d.isZoned() {
+		layout += "Z07:00"
+	}
+	return d.t.Format(layout)
+}
+
+// extract evaluates EXTRACT of a non-NULL value. SECOND includes the
+// fraction of a second.
+func (x *executor) extract(e *ast.ExtractExpr, v any) (any, error) {
+	d, ok := v.(datetime)
+	switch {
+	case !ok:
+	case e.Field == ast.YearField || e.Field == ast.MonthField || e.Field == ast.DayField:
+		ok = !d.isTime()
+	case e.Field == ast.TimezoneHourField || e.Field == ast.TimezoneMinuteField:
+		ok = d.isZoned()
+	default:
+		ok = d.typ != ast.DateType
+	}
+	if !ok {
+		return nil, x.errorf(e, "cannot extract %v from %s", e.Field, typeName(v))
+	}
+
+	t := d.t
+	_, off := t.Zone()
+	switch e.Field {
+	case ast.YearField:
+		return int64(t.Year()), nil
+	case ast.MonthField:
+		return int64(t.Month()), nil
+	case ast.DayField:
+		return int64(t.Day()), nil
+	case ast.HourField:
+		return int64(t.Hour()), nil
+	case ast.MinuteField:
+		return int64(t.Minute()), nil
+	case ast.SecondField:
+		return float64(t.Second()) + float64(t.Nanosecond())/1e9, nil
+	case ast.TimezoneHourField:
+		return int64(off / 3600), nil
+	case ast.TimezoneMinuteField:
+		return int64(off % 3600 / 60), nil
+	default:
+		return nil, x.errorf(e, "cannot extract %v", e.Field)
+	}
+}
+
+// substring evaluates SUBSTRING, with the 1-based positions of SQL.
+// Positions outside the string are ignored.
+func (x *executor) substring(e *ast.SubstringExpr, env *env) (any, error) {
+	args := []ast.Expr{e.Arg, e.Start}
+	if e.Length != nil {
+		args = append(args, e.Length)
+	}
+	vals, err := x.args(args, env)
+	if err != nil || vals == nil {
+		return nil, err
+	}
+
+	s, ok := vals[0].(string)
+	if !ok {
+		return nil, x.errorf(e.Arg, "cannot take a substring of %s", typeName(vals[0]))
+	}
+	ns := make([]int64, len(vals)-1)
+	for i, v := range vals[1:] {
+		if ns[i], ok = toInt(v); !ok {
+			return nil, x.errorf(args[i+1], "SUBSTRING position is %s, not a number", typeName(v))
+		}
+	}
+
+	r := []rune(s)
+	begin, end := ns[0]-1, int64(len(r))
+	if len(ns) > 1 {
+		if ns[1] < 0 {
+			return nil, x.errorf(e.Length, "negative SUBSTRING length %d", ns[1])
+		}
+		end = begin + ns[1]
+	}
+	if begin < 0 {
+		begin = 0
+	}
+	if end > int64(len(r)) {
+		end = int64(len(r))
+	}
+	if end <= begin {
+		return "", nil
+	}
+	return string(r[begin:end]), nil
+}
+
+// args evaluates arguments. It returns nil values if any is NULL.
+func (x *executor) args(args []ast.Expr, env *env) ([]any, error) {
+	vals := make([]any, len(args))
+	for i, arg := range args {
+		v, err := x.eval(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, nil
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+// toInt returns a number truncated to an integer.
+func toInt(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// call evaluates a function call. Functions return NULL if an argument
+// is NULL.
+func (x *executor) call(e *ast.CallExpr, env *env) (any, error) {
+	if len(e.Func.Names) > 1 {
+		// Only built-in functions are supported.
+		return nil, x.errorf(e.Func, "unknown function %s", exprString(e.Func))
+	}
+	name := strings.ToUpper(e.Func.Names[0].Name)
+	f := builtins[name]
+	if f.fn == nil {
+		return nil, x.errorf(e.Func, "unknown function %s", name)
+	}
+	if len(e.Args) < f.min || f.max >= 0 && len(e.Args) > f.max {
+		return nil, x.errorf(e, "wrong number of arguments to %s", name)
+	}
+
+	vals, err := x.args(e.Args, env)
+	if err != nil || vals == nil {
+		return nil, err
+	}
+	v, err := f.fn(x, vals)
+	if err != nil {
+		return nil, x.errorf(e, "%s: %v", name, err)
+	}
+	return v, nil
+}
+
+// A builtin is a function, with its minimum and maximum number of
+// arguments. A negative maximum means any number.
+type builtin struct {
+	min, max int
+	fn       func(x *executor, args []any) (any, error)
+}
+
+var builtins = map[string]builtin{
+	"ABS":     {1, 1, numeric(math.Abs, absInt)},
+	"CEIL":    {1, 1, numeric(math.Ceil, nil)},
+	"CEILING": {1, 1, numeric(math.Ceil, nil)},
+	"FLOOR":   {1, 1, numeric(math.Floor, nil)},
+	"ROUND":   {1, 1, numeric(roundHalfUp, nil)},
+
+	"UPPER": {1, 1, str(strings.ToUpper)},
+	"LOWER": {1, 1, str(strings.ToLower)},
+
+	"JAVA_REGEXP_LIKE": {2, 2, regexpLike},
+	"ALL_DIFFERENT":    {1, -1, allDifferent},
+
+	"ID":         {1, 1, id},
+	"HAS_LABEL":  {2, 2, hasLabel},
+	"IN_DEGREE":  {1, 1, degree(func(g *graph.Graph, id graph.ID) int { return len(g.InEdges(id)) })},
+	"OUT_DEGREE": {1, 1, degree(func(g *graph.Graph, id graph.ID) int { return len(g.OutEdges(id)) })},
+}
+
+// numeric returns a function of a number. Integers are returned as is
+// if fi is nil.
+func numeric(ff func(float64) float64, fi func(int64) (int64, error)) func(*executor, []any) (any, error) {
+	return func(_ *executor, args []any) (any, error) {
+		switch v := args[0].(type) {
+		case int64:
+			if fi == nil {
+				return v, nil
+			}
+			return fi(v)
+		case float64:
+			return ff(v), nil
+		}
+		return nil, fmt.Errorf("argument is %s, not a number", typeName(args[0]))
+	}
+}
+
+func absInt(i int64) (int64, error) {
+	if i < 0 {
+		return negInt(i)
+	}
+	return i, nil
+}
+
+// roundHalfUp rounds half-way cases towards positive infinity, like
+// Java's Math.round.
+func roundHalfUp(f float64) float64 {
+	if f == math.Trunc(f) {
+		// Large floats are integral, and adding 0.5 may round them.
+		return f
+	}
+	return math.Floor(f + 0.5)
+}
+
+// str returns a function of a string.
+func str(f func(string) string) func(*executor, []any) (any, error) {
+	return func(_ *executor, args []any) (any, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("argument is %s, not STRING", typeName(args[0]))
+		}
+		return f(s), nil
+	}
+}
+
+// regexpLike returns true if the whole string matches the pattern,
+// like Java's String.matches.
+func regexpLike(_ *executor, args []any) (any, error) {
+	s, ok1 := args[0].(string)
+	p, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("arguments are %s and %s, not STRING", typeName(args[0]), typeName(args[1]))
+	}
+	re, err := regexp.Compile(`^(?:` + p + `)$`)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(s), nil
+}
+
+func allDifferent(_ *executor, args []any) (any, error) {
+	seen := map[string]bool{}
+	for _, v := range args {
+		k := valueKey(v)
+		if seen[k] {
+			return false, nil
+		}
+		seen[k] = true
+	}
+	return true, nil
+}
+
+func id(_ *executor, args []any) (any, error) {
+	switch v := args[0].(type) {
+	case *graph.Vertex:
+		return int64(v.ID), nil
+	case *graph.Edge:
+		return int64(v.ID), nil
+	}
+	return nil, fmt.Errorf("argument is %s, not VERTEX or EDGE", typeName(args[0]))
+}
+
+func hasLabel(_ *executor, args []any) (any, error) {
+	l, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("label is %s, not STRING", typeName(args[1]))
+	}
+	switch v := args[0].(type) {
+	case *graph.Vertex:
+		return v.HasLabel(l), nil
+	case *graph.Edge:
+		return v.HasLabel(l), nil
+	}
+	return nil, fmt.Errorf("argument is %s, not VERTEX or EDGE", typeName(args[0]))
+}
+
+// degree returns a function of a vertex counting its edges.
+func degree(f func(*graph.Graph, graph.ID) int) func(*executor, []any) (any, error) {
+	return func(x *executor, args []any) (any, error) {
+		v, ok := args[0].(*graph.Vertex)
+		if !ok {
+			return nil, fmt.Errorf("argument is %s, not VERTEX", typeName(args[0]))
+		}
+		return int64(f(x.g, v.ID)), nil
+	}
+}
@@ -0,0 +1,576 @@
+package exec
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/catalog"
+	"github.com/itergia/pgql-go/graph"
+	"github.com/itergia/pgql-go/sema"
+)
+
+// eval evaluates an expression. NULL is nil.
+func (x *executor) eval(e ast.Expr, env *env) (any, error) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return x.literal(e, false)
+
+	case *ast.BindVar:
+		if e.Index >= len(x.opts.Params) {
+			return nil, x.errorf(e, "missing value for bind variable %d", e.Index)
+		}
+		return normalize(x.opts.Params[e.Index], ast.UnknownDataType), nil
+
+	case *ast.Ident:
+		return x.variable(e, env)
+
+	case *ast.QIdent:
+		v, err := x.variable(e.Names[0], env)
+		if err != nil || len(e.Names) == 1 {
+			return v, err
+		}
+		return x.property(e, v, e.Names[1].Name)
+
+	case *ast.OpExpr:
+		return x.op(e, env)
+
+	case *ast.IsNullExpr:
+		v, err := x.eval(e.Arg, env)
+		if err != nil {
+			return nil, err
+		}
+		return (v == nil) != e.Not, nil
+
+	case *ast.AggregateExpr:
+		return x.aggregate(e, env)
+
+	case *ast.ExistsExpr:
+		_, rows, err := x.query(e.Query.Query, env.b)
+		if err != nil {
+			return nil, err
+		}
+		return len(rows) > 0, nil
+
+	case *ast.SubqueryExpr:
+		return x.scalar(e, env)
+
+	case *ast.LabelExpr:
+		return x.labels(e, env)
+
+	case *ast.CaseExpr:
+		return x.caseExpr(e, env)
+
+	case *ast.InExpr:
+		return x.in(e, env)
+
+	case *ast.CastExpr:
+		v, err := x.eval(e.Arg, env)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		return x.cast(e, v)
+
+	case *ast.CallExpr:
+		return x.call(e, env)
+
+	case *ast.ExtractExpr:
+		v, err := x.eval(e.Arg, env)
+		if err != nil || v == nil {
+			return nil, err
+		}
+		return x.extract(e, v)
+
+	case *ast.SubstringExpr:
+		return x.substring(e, env)
+
+	default:
+		return nil, x.errorf(e, "unsupported expression")
+	}
+}
+
+// literal returns the value of a literal. Integers are negated if neg
+// is true, and must then fit in a LONG. Decimals must fit in a DOUBLE.
+func (x *executor) literal(e *ast.BasicLit, neg bool) (any, error) {
+	v, err := e.Value()
+	if err != nil {
+		return nil, x.errorf(e, "%v", err)
+	}
+	switch v := v.(type) {
+	case int64:
+		if neg {
+			return -v, nil
+		}
+	case *big.Int:
+		if neg {
+			v = new(big.Int).Neg(v)
+		}
+		if !v.IsInt64() {
+			return nil, x.errorf(e, "%s overflows LONG", v)
+		}
+		return v.Int64(), nil
+	case *big.Rat:
+		return nil, x.errorf(e, "decimal literal overflows DOUBLE")
+	case time.Time:
+		return literal(e.Kind, v), nil
+	}
+	return v, nil
+}
+
+// variable returns the value of a variable.
+func (x *executor) variable(id *ast.Ident, env *env) (any, error) {
+	obj := x.info.ObjectOf(id)
+	if v, ok := env.b[obj]; ok {
+		return v, nil
+	}
+	if obj != nil && obj.Kind == sema.ExprKind {
+		return x.eval(obj.Decl.(*ast.NamedExpr).Expr, env)
+	}
+	return nil, x.errorf(id, "variable %s is not bound", id.Name)
+}
+
+// property returns the property of an element, or nil if it has none.
+func (x *executor) property(n ast.Node, v any, name string) (any, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case *graph.Vertex:
+		return normalize(v.Props[name], x.g.PropertyType(catalog.VertexTable, v.Labels, name)), nil
+	case *graph.Edge:
+		return normalize(v.Props[name], x.g.PropertyType(catalog.EdgeTable, v.Labels, name)), nil
+	case []any:
+		// Variables of quantified path patterns are lists.
+		vals := make([]any, len(v))
+		for i, elem := range v {
+			var err error
+			if vals[i], err = x.property(n, elem, name); err != nil {
+				return nil, err
+			}
+		}
+		return vals, nil
+	default:
+		return nil, x.errorf(n, "cannot access property %s of %s", name, typeName(v))
+	}
+}
+
+// compareEq compares values for equality, with NULL unknown.
+func (x *executor) compareEq(n ast.Node, a, b any) (any, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	c, ok := compare(a, b)
+	if !ok {
+		return nil, x.errorf(n, "cannot compare %s and %s", typeName(a), typeName(b))
+	}
+	return c == 0, nil
+}
+
+// caseExpr evaluates CASE. A simple CASE compares its subject to the
+// WHEN values with "=", so a NULL subject matches nothing.
+func (x *executor) caseExpr(e *ast.CaseExpr, env *env) (any, error) {
+	var subject any
+	if e.Subject != nil {
+		var err error
+		if subject, err = x.eval(e.Subject, env); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, w := range e.Whens {
+		var ok bool
+		if e.Subject != nil {
+			v, err := x.eval(w.Cond, env)
+			if err != nil {
+				return nil, err
+			}
+			eq, err := x.compareEq(w.Cond, subject, v)
+			if err != nil {
+				return nil, err
+			}
+			ok = eq == true
+		} else {
+			var err error
+			if ok, err = x.cond(w.Cond, env); err != nil {
+				return nil, err
+			}
+		}
+		if ok {
+			return x.eval(w.Then, env)
+		}
+	}
+	if e.Else == nil {
+		return nil, nil
+	}
+	return x.eval(e.Else, env)
+}
+
+// in evaluates IN and NOT IN. The result is NULL if the subject is
+// NULL, or if it equals no value and some value is NULL.
+func (x *executor) in(e *ast.InExpr, env *env) (any, error) {
+	subject, err := x.eval(e.Subject, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var vals []any
+	if e.BindVar != nil {
+		v, err := x.eval(e.BindVar, env)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, nil
+		}
+		var ok bool
+		if vals, ok = toList(v); !ok {
+			return nil, x.errorf(e.BindVar, "bind variable of IN is %s, not a list", typeName(v))
+		}
+	} else {
+		for _, o := range e.Objects {
+			v, err := x.eval(o, env)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+	}
+
+	var res any = false
+	for _, v := range vals {
+		eq, err := x.compareEq(e, subject, v)
+		if err != nil {
+			return nil, err
+		}
+		if eq == true {
+			res = true
+			break
+		}
+		if eq == nil {
+			res = nil
+		}
+	}
+	if b, ok := res.(bool); ok && e.Inv {
+		return !b, nil
+	}
+	return res, nil
+}
+
+// op evaluates an operator, with the three-valued logic of SQL: NULL
+// operands give NULL, except for AND and OR when the other operand
+// decides.
+func (x *executor) op(e *ast.OpExpr, env *env) (any, error) {
+	switch e.Op {
+	case ast.AndOp, ast.OrOp:
+		return x.logical(e, env)
+	case ast.NegOp:
+		// The smallest LONG is only valid as a negated literal.
+		if lit, ok := e.Args[0].(*ast.BasicLit); ok && lit.Kind == ast.UIntKind {
+			return x.literal(lit, true)
+		}
+	}
+
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		v, err := x.eval(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, nil
+		}
+		args[i] = v
+	}
+
+	switch e.Op {
+	case ast.NotOp:
+		b, ok := args[0].(bool)
+		if !ok {
+			return nil, x.errorf(e, "cannot apply NOT to %s", typeName(args[0]))
+		}
+		return !b, nil
+
+	case ast.NegOp:
+		switch v := args[0].(type) {
+		case int64:
+			n, err := negInt(v)
+			if err != nil {
+				return nil, x.errorf(e, "%v", err)
+			}
+			return n, nil
+		case float64:
+			return -v, nil
+		case ast.Interval:
+			return ast.Interval{N: -v.N, Field: v.Field}, nil
+		}
+		return nil, x.errorf(e, "cannot negate %s", typeName(args[0]))
+
+	case ast.EqOp, ast.NeOp, ast.LtOp, ast.GtOp, ast.LeOp, ast.GeOp:
+		c, ok := compare(args[0], args[1])
+		if !ok {
+			return nil, x.errorf(e, "cannot compare %s and %s", typeName(args[0]), typeName(args[1]))
+		}
+		switch e.Op {
+		case ast.EqOp:
+			return c == 0, nil
+		case ast.NeOp:
+			return c != 0, nil
+		case ast.LtOp:
+			return c < 0, nil
+		case ast.GtOp:
+			return c > 0, nil
+		case ast.LeOp:
+			return c <= 0, nil
+		default:
+			return c >= 0, nil
+		}
+
+	case ast.ConcatOp:
+		a, ok1 := args[0].(string)
+		b, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, x.errorf(e, "mismatched types %s and %s for ||", typeName(args[0]), typeName(args[1]))
+		}
+		return a + b, nil
+
+	default:
+		v, ok, err := arith(e.Op, args[0], args[1])
+		if !ok && err == nil {
+			v, ok, err = dateArith(e.Op, args[0], args[1])
+		}
+		if err != nil {
+			return nil, x.errorf(e, "%v", err)
+		}
+		if !ok {
+			return nil, x.errorf(e, "mismatched types %s and %s for %v", typeName(args[0]), typeName(args[1]), e.Op)
+		}
+		return v, nil
+	}
+}
+
+// logical evaluates AND and OR. The second operand is not evaluated if
+// the first one decides.
+func (x *executor) logical(e *ast.OpExpr, env *env) (any, error) {
+	decisive := e.Op == ast.OrOp
+	var res any = !decisive
+	for _, arg := range e.Args {
+		v, err := x.eval(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case nil:
+			res = nil
+		case bool:
+			if v == decisive {
+				return v, nil
+			}
+		default:
+			return nil, x.errorf(e, "cannot apply %v to %s", e.Op, typeName(v))
+		}
+	}
+	return res, nil
+}
+
+// aggregate evaluates an aggregate over the rows of the group, or
+// over the elements of the variables of quantified path patterns it
+// uses. NULLs are ignored, and aggregates of no values are NULL,
+// except COUNT.
+func (x *executor) aggregate(e *ast.AggregateExpr, g *env) (any, error) {
+	rows := g.group
+	if objs := x.groupVars(e); len(objs) > 0 {
+		var err error
+		if rows, err = x.unnest(e, g.b, objs); err != nil {
+			return nil, err
+		}
+	} else if !g.grouped {
+		return nil, x.errorf(e, "%v is not allowed here", e.Func)
+	}
+
+	var vals []any
+	seen := map[string]bool{}
+	for _, b := range rows {
+		if e.Star {
+			vals = append(vals, true)
+			continue
+		}
+		v, err := x.eval(e.Arg, &env{b: b})
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		if e.Distinct {
+			k := valueKey(v)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		}
+		vals = append(vals, v)
+	}
+
+	if e.Func == ast.CountFunc {
+		return int64(len(vals)), nil
+	}
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	switch e.Func {
+	case ast.MinFunc, ast.MaxFunc:
+		res := vals[0]
+		for _, v := range vals[1:] {
+			c, ok := compare(v, res)
+			if !ok {
+				return nil, x.errorf(e, "cannot compare %s and %s", typeName(v), typeName(res))
+			}
+			if (c < 0) == (e.Func == ast.MinFunc) && c != 0 {
+				res = v
+			}
+		}
+		return res, nil
+
+	case ast.SumFunc, ast.AvgFunc:
+		var sum any = int64(0)
+		for _, v := range vals {
+			var ok bool
+			var err error
+			if sum, ok, err = arith(ast.AddOp, sum, v); err != nil {
+				return nil, x.errorf(e, "%v", err)
+			} else if !ok {
+				return nil, x.errorf(e, "cannot apply %v to %s", e.Func, typeName(v))
+			}
+		}
+		if e.Func == ast.SumFunc {
+			return sum, nil
+		}
+		f, _ := toFloat(sum)
+		return f / float64(len(vals)), nil
+
+	case ast.ArrayAggFunc:
+		return vals, nil
+
+	case ast.ListaggFunc:
+		var sep string
+		if e.Separator != nil {
+			v, err := x.eval(e.Separator, g)
+			if err != nil {
+				return nil, err
+			}
+			sep, _ = v.(string)
+		}
+		strs := make([]string, len(vals))
+		for i, v := range vals {
+			s, ok := v.(string)
+			if !ok {
+				return nil, x.errorf(e, "cannot apply LISTAGG to %s", typeName(v))
+			}
+			strs[i] = s
+		}
+		return strings.Join(strs, sep), nil
+
+	default:
+		return nil, x.errorf(e, "unsupported aggregate %v", e.Func)
+	}
+}
+
+// groupVars returns the variables of quantified path patterns used by
+// an aggregate, outside of nested aggregates and subqueries.
+func (x *executor) groupVars(e *ast.AggregateExpr) []*sema.Object {
+	if e.Arg == nil {
+		return nil
+	}
+
+	var objs []*sema.Object
+	seen := map[*sema.Object]bool{}
+	ast.Inspect(e.Arg, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if obj := x.info.ObjectOf(n); obj != nil && obj.Group && !seen[obj] {
+				seen[obj] = true
+				objs = append(objs, obj)
+			}
+		case *ast.AggregateExpr, *ast.SubqueryExpr:
+			return false
+		}
+		return true
+	})
+	return objs
+}
+
+// unnest returns one row per element of the lists bound to group
+// variables, with the variables bound to the elements.
+func (x *executor) unnest(e *ast.AggregateExpr, b bindings, objs []*sema.Object) ([]bindings, error) {
+	var rows []bindings
+	for i, obj := range objs {
+		l, ok := b[obj].([]any)
+		if !ok {
+			return nil, x.errorf(e, "variable %s is not bound", obj.Name)
+		}
+		if i == 0 {
+			rows = make([]bindings, len(l))
+			for j := range rows {
+				rows[j] = b
+			}
+		} else if len(l) != len(rows) {
+			return nil, x.errorf(e, "variables %s and %s have different numbers of elements", objs[0].Name, obj.Name)
+		}
+		for j, elem := range l {
+			rows[j] = rows[j].with(obj, elem)
+		}
+	}
+	return rows, nil
+}
+
+// scalar evaluates a scalar subquery, which must return at most one
+// row of one column. No row is NULL.
+func (x *executor) scalar(e *ast.SubqueryExpr, env *env) (any, error) {
+	cols, rows, err := x.query(e.Query, env.b)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(cols) != 1:
+		return nil, x.errorf(e, "scalar subquery returns %d columns", len(cols))
+	case len(rows) > 1:
+		return nil, x.errorf(e, "scalar subquery returns %d rows", len(rows))
+	case len(rows) == 0:
+		return nil, nil
+	}
+	return rows[0][0], nil
+}
+
+// labels evaluates LABEL, which requires an element with a single
+// label, and LABELS, which returns a list.
+func (x *executor) labels(e *ast.LabelExpr, env *env) (any, error) {
+	v, err := x.eval(e.Arg, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var ls []string
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case *graph.Vertex:
+		ls = v.Labels
+	case *graph.Edge:
+		ls = v.Labels
+	default:
+		return nil, x.errorf(e, "cannot get the labels of %s", typeName(v))
+	}
+
+	if !e.Plural {
+		if len(ls) != 1 {
+			return nil, x.errorf(e, "LABEL of an element with %d labels", len(ls))
+		}
+		return ls[0], nil
+	}
+	l := make([]any, len(ls))
+	for i, s := range ls {
+		l[i] = s
+	}
+	return l, nil
+}
@@ -0,0 +1,227 @@
+package exec
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/catalog"
+	"github.com/itergia/pgql-go/graph"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestEval(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name   string
+		Input  string
+		Params []any
+		Want   string
+	}{
+		{"and", "n.age > 30 AND x.age > 30", nil, "false"},
+		{"andNull", "x.age > 30 AND true", nil, "<nil>"},
+		{"andNullFalse", "x.age > 30 AND false", nil, "false"},
+		{"orNull", "x.age > 30 OR false", nil, "<nil>"},
+		{"orNullTrue", "x.age > 30 OR true", nil, "true"},
+		{"notNull", "NOT x.age > 30", nil, "<nil>"},
+		{"compareNull", "x.age = x.age", nil, "<nil>"},
+		{"isNotNull", "x.age IS NOT NULL", nil, "false"},
+		{"promotion", "n.age + 0.5", nil, "25.5"},
+		{"compareMixed", "n.age = 25.0", nil, "true"},
+		{"concatNull", "n.name || x.nick", nil, "<nil>"},
+
+		{"in", "n.age IN (1, 25, 3)", nil, "true"},
+		{"notIn", "n.age NOT IN (1, 2)", nil, "true"},
+		{"inNull", "n.age IN (1, x.age)", nil, "<nil>"},
+		{"inNullFound", "n.age IN (x.age, 25)", nil, "true"},
+		{"notInNull", "n.age NOT IN (1, x.age)", nil, "<nil>"},
+		{"inNullSubject", "x.age IN (1, 2)", nil, "<nil>"},
+		{"inBindVar", "n.name IN ?", []any{[]string{"Alice", "Bob"}}, "true"},
+
+		{"case", "CASE WHEN n.age < 20 THEN 'young' WHEN n.age < 30 THEN 'adult' ELSE 'old' END", nil, "adult"},
+		{"caseNoElse", "CASE WHEN n.age > 30 THEN 1 END", nil, "<nil>"},
+		{"caseNullCond", "CASE WHEN x.age > 30 THEN 1 ELSE 2 END", nil, "2"},
+		{"caseSimple", "CASE n.name WHEN 'Alice' THEN 1 WHEN 'Bob' THEN 2 END", nil, "2"},
+		{"caseSimpleNull", "CASE x.nick WHEN 'Alice' THEN 1 ELSE 0 END", nil, "0"},
+
+		{"castString", "CAST(n.age AS STRING) || '!'", nil, "25!"},
+		{"castDouble", "CAST(1.5 AS STRING)", nil, "1.5"},
+		{"castInt", "CAST('42' AS INTEGER) + 1", nil, "43"},
+		{"castLong", "CAST(2.9 AS LONG)", nil, "2"},
+		{"castFloat", "CAST(n.age AS DOUBLE) / 2", nil, "12.5"},
+		{"castBoolean", "CAST('TRUE' AS BOOLEAN)", nil, "true"},
+		{"castDate", "CAST('2000-01-02' AS DATE) = DATE '2000-01-02'", nil, "true"},
+		{"castTimestampDate", "CAST(CAST(TIMESTAMP '2000-01-02 10:30:00' AS DATE) AS STRING)", nil, "2000-01-02"},
+		{"castTime", "CAST(CAST(TIMESTAMP '2000-01-02 10:30:00' AS TIME) AS STRING)", nil, "10:30:00"},
+		{"castTimestampTZ", "CAST(CAST(TIMESTAMP '2000-01-02 10:30:00' AS TIMESTAMP WITH TIME ZONE) AS STRING)", nil, "2000-01-02 10:30:00Z"},
+		{"castNull", "CAST(x.age AS STRING)", nil, "<nil>"},
+
+		{"dateAddDay", "CAST(DATE '2000-02-28' + INTERVAL '2' DAY AS STRING)", nil, "2000-03-01"},
+		{"dateSubMonth", "CAST(DATE '2000-03-31' - INTERVAL '1' MONTH AS STRING)", nil, "2000-02-29"},
+		{"dateAddMonthEnd", "CAST(DATE '2000-01-31' + INTERVAL '1' MONTH AS STRING)", nil, "2000-02-29"},
+		{"dateAddMonths", "CAST(DATE '2000-01-31' + INTERVAL '14' MONTH AS STRING)", nil, "2001-03-31"},
+		{"dateSubMonths", "CAST(DATE '2000-01-31' - INTERVAL '11' MONTH AS STRING)", nil, "1999-02-28"},
+		{"dateAddYearLeap", "CAST(DATE '2000-02-29' + INTERVAL '1' YEAR AS STRING)", nil, "2001-02-28"},
+		{"timestampAddMonth", "CAST(TIMESTAMP '2001-01-31 10:30:00' + INTERVAL '1' MONTH AS STRING)", nil, "2001-02-28 10:30:00"},
+		{"intervalAdd", "CAST(INTERVAL '1' HOUR + TIMESTAMP '2000-01-02 23:30:00' AS STRING)", nil, "2000-01-03 00:30:00"},
+		{"timeAdd", "CAST(TIME '23:30:00' + INTERVAL '90' MINUTE AS STRING)", nil, "01:00:00"},
+		{"timeSub", "CAST(TIME '00:30:00' - INTERVAL '1' HOUR AS STRING)", nil, "23:30:00"},
+		{"dateAddHour", "CAST(DATE '2000-01-02' + INTERVAL '1' HOUR AS STRING)", nil, "2000-01-02 01:00:00"},
+		{"timeAddDay", "CAST(TIME '10:00:00' + INTERVAL '1' DAY AS STRING)", nil, "10:00:00"},
+		{"timestampMidnight", "CAST(TIMESTAMP '2000-01-02 00:00:00' AS STRING)", nil, "2000-01-02 00:00:00"},
+		{"timeZone", "CAST(TIME '10:00:00+01:00' AS STRING)", nil, "10:00:00+01:00"},
+		{"paramDate", "? = DATE '2000-01-02'", []any{time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)}, "true"},
+		{"paramTime", "CAST(? AS TIME) < TIME '10:00:00'", []any{time.Date(2000, 1, 2, 9, 0, 0, 0, time.UTC)}, "true"},
+		{"paramMidnight", "EXTRACT(HOUR FROM ?)", []any{time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)}, "0"},
+		{"paramMidnightAddHour", "CAST(? + INTERVAL '1' HOUR AS STRING)", []any{time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)}, "2000-01-02 01:00:00"},
+		{"paramCastDate", "CAST(CAST(? AS DATE) AS STRING)", []any{time.Date(2000, 1, 2, 10, 30, 0, 0, time.UTC)}, "2000-01-02"},
+		{"exportDate", "DATE '2000-01-02'", nil, "2000-01-02 00:00:00 +0000 UTC"},
+		{"dateEqTimestamp", "DATE '2000-01-02' = TIMESTAMP '2000-01-02 00:00:00'", nil, "true"},
+		{"dateCompare", "DATE '2000-01-02' < DATE '2000-01-02' + INTERVAL '1' SECOND", nil, "true"},
+
+		{"extract", "EXTRACT(YEAR FROM DATE '2000-01-02') * 100 + EXTRACT(MONTH FROM DATE '2000-01-02')", nil, "200001"},
+		{"extractSecond", "EXTRACT(SECOND FROM TIME '10:20:30.5')", nil, "30.5"},
+		{"extractZone", "EXTRACT(TIMEZONE_HOUR FROM TIMESTAMP '2000-01-02 10:00:00-05:30')", nil, "-5"},
+		{"extractZoneMinute", "EXTRACT(TIMEZONE_MINUTE FROM TIMESTAMP '2000-01-02 10:00:00-05:30')", nil, "-30"},
+
+		{"substring", "SUBSTRING(n.name FROM 2)", nil, "ob"},
+		{"substringFor", "SUBSTRING('héllo' FROM 2 FOR 3)", nil, "éll"},
+		{"substringBefore", "SUBSTRING('hello' FROM 0 FOR 2)", nil, "h"},
+		{"substringNull", "SUBSTRING(x.nick FROM 2)", nil, "<nil>"},
+
+		{"minLong", "-9223372036854775808", nil, "-9223372036854775808"},
+		{"minLongParen", "-(9223372036854775807) - 1", nil, "-9223372036854775808"},
+		{"negLiteral", "-n.age - -1", nil, "-24"},
+		{"abs", "ABS(-n.age)", nil, "25"},
+		{"round", "ROUND(2.5) + CEIL(0.1) + FLOOR(-0.1)", nil, "3"},
+		{"roundNegativeHalf", "ROUND(-2.5)", nil, "-2"},
+		{"roundNegative", "ROUND(-2.6)", nil, "-3"},
+		{"roundLarge", "ROUND(4503599627370497.0)", nil, "4.503599627370497e+15"},
+		{"upper", "UPPER(n.name) || LOWER(n.name)", nil, "BOBbob"},
+		{"regexp", "JAVA_REGEXP_LIKE(n.name, 'B.b')", nil, "true"},
+		{"regexpPartial", "java_regexp_like(n.name, 'o')", nil, "false"},
+		{"hasLabel", "HAS_LABEL(n, 'person')", nil, "true"},
+		{"degree", "IN_DEGREE(n) * 10 + OUT_DEGREE(n)", nil, "12"},
+		{"id", "ID(n)", nil, "2"},
+		{"allDifferent", "ALL_DIFFERENT(1, 2, 1.0)", nil, "false"},
+		{"callNull", "UPPER(x.nick)", nil, "<nil>"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := query(t, g, "SELECT "+tst.Input+" AS v FROM MATCH (n), MATCH (x) WHERE n.name = 'Bob' AND x.name = 'Acme'", tst.Params...)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if diff := cmp.Diff([]string{"v", tst.Want}, got); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvalSchema(t *testing.T) {
+	cat := catalog.New("")
+	stmt, err := parser.ParseStatement("CREATE PROPERTY GRAPH g VERTEX TABLES (person PROPERTIES (CAST(born AS DATE) AS born, CAST(wake AS TIME) AS wake, ts))")
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+	if err := cat.Exec(nil, stmt); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	g := graph.New(cat.LookupGraph("", "g"))
+	if _, err := g.AddVertex([]string{"person"}, map[string]any{
+		"born": time.Date(2000, 1, 2, 10, 30, 0, 0, time.UTC),
+		"wake": time.Date(2000, 1, 2, 7, 0, 0, 0, time.UTC),
+		"ts":   time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("AddVertex failed: %v", err)
+	}
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"date", "CAST(n.born AS STRING)", "2000-01-02"},
+		{"dateAddDay", "CAST(n.born + INTERVAL '1' DAY AS STRING)", "2000-01-03"},
+		{"time", "CAST(n.wake AS STRING)", "07:00:00"},
+		{"timeAddHour", "CAST(n.wake + INTERVAL '1' HOUR AS STRING)", "08:00:00"},
+		{"timestampMidnight", "CAST(n.ts AS STRING)", "2000-01-02 00:00:00"},
+		{"timestampMidnightHour", "EXTRACT(HOUR FROM n.ts)", "0"},
+		{"timestampMidnightAddHour", "CAST(n.ts + INTERVAL '1' HOUR AS STRING)", "2000-01-02 01:00:00"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := query(t, g, "SELECT "+tst.Input+" AS v FROM MATCH (n)")
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if diff := cmp.Diff([]string{"v", tst.Want}, got); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEvalError(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"castInt", "CAST('x' AS INTEGER)", `at 1:8: cannot cast "x" to INTEGER`},
+		{"castOverflow", "CAST(3000000000 AS INT)", "at 1:8: 3000000000 overflows INT"},
+		{"castVertex", "CAST(n AS STRING)", "at 1:8: cannot cast VERTEX to STRING"},
+		{"function", "FOO(1)", "at 1:8: unknown function FOO"},
+		{"functionQualified", "m.upper('a')", "at 1:8: unknown function m.upper"},
+		{"arguments", "UPPER('a', 'b')", "at 1:8: wrong number of arguments to UPPER"},
+		{"regexp", "JAVA_REGEXP_LIKE('a', '(')", "at 1:8: JAVA_REGEXP_LIKE: error parsing regexp: missing closing ): `^(?:()$`"},
+		{"substring", "SUBSTRING('a' FROM 1 FOR -1)", "at 1:33: negative SUBSTRING length -1"},
+		{"interval", "INTERVAL '1' DAY + INTERVAL '1' HOUR", "at 1:8: mismatched types INTERVAL and INTERVAL for +"},
+		{"intervalSameField", "INTERVAL '1' DAY - INTERVAL '2' DAY", "at 1:8: mismatched types INTERVAL and INTERVAL for -"},
+		{"compareDate", "DATE '2000-01-02' = true", "at 1:8: cannot compare DATE and BOOLEAN"},
+		{"compareDateTime", "DATE '2000-01-02' < TIME '10:00:00'", "at 1:8: cannot compare DATE and TIME"},
+		{"arithDate", "DATE '2000-01-02' * 2", "at 1:8: mismatched types DATE and LONG for *"},
+		{"castTimeDate", "CAST(TIME '10:00:00' AS DATE)", "at 1:8: cannot cast TIME to DATE"},
+		{"castDateTime", "CAST(DATE '2000-01-02' AS TIME WITH TIME ZONE)", "at 1:8: cannot cast DATE to TIME WITH TIME ZONE"},
+		{"extractTime", "EXTRACT(YEAR FROM TIME '10:00:00')", "at 1:8: cannot extract YEAR from TIME"},
+		{"extractDate", "EXTRACT(HOUR FROM DATE '2000-01-02')", "at 1:8: cannot extract HOUR from DATE"},
+		{"extractZone", "EXTRACT(TIMEZONE_HOUR FROM TIMESTAMP '2000-01-02 10:00:00')", "at 1:8: cannot extract TIMEZONE_HOUR from TIMESTAMP"},
+		{"bigInt", "9223372036854775808", "at 1:8: 9223372036854775808 overflows LONG"},
+		{"bigNegInt", "-9223372036854775809", "at 1:9: -9223372036854775809 overflows LONG"},
+		{"bigDecimal", "1" + strings.Repeat("0", 400) + ".5", "at 1:8: decimal literal overflows DOUBLE"},
+		{"overflowAdd", "9223372036854775807 + 1", "at 1:8: integer overflow"},
+		{"overflowSub", "-9223372036854775807 - 2", "at 1:8: integer overflow"},
+		{"overflowMul", "4611686018427387904 * 2", "at 1:8: integer overflow"},
+		{"overflowMulMin", "-1 * (-9223372036854775807 - 1)", "at 1:8: integer overflow"},
+		{"overflowDiv", "(-9223372036854775807 - 1) / -1", "at 1:9: integer overflow"},
+		{"overflowNeg", "-(-9223372036854775807 - 1)", "at 1:8: integer overflow"},
+		{"overflowAbs", "ABS(-9223372036854775807 - 1)", "at 1:8: ABS: integer overflow"},
+		{"overflowSum", "SUM(9223372036854775807)", "at 1:8: integer overflow"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := query(t, g, "SELECT "+tst.Input+" FROM MATCH (n) LIMIT 1")
+			if err == nil {
+				t.Fatalf("Query succeeded, want %q", tst.Want)
+			}
+			if diff := cmp.Diff(tst.Want, err.Error()); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,608 @@
+// Package exec runs PGQL queries against an in-memory graph.
+//
+// It is a reference implementation, favoring simplicity over speed,
+// to test queries end to end. Patterns are matched by backtracking,
+// with the homomorphism semantics of PGQL: different variables can be
+// bound to the same element. Quantified path patterns do not repeat
+// edges, so that they terminate on cyclic graphs, and those with an
+// ANY, SHORTEST or CHEAPEST goal are found by searches.
+//
+// Values are those of graph properties: nil for NULL, int64, float64,
+// bool, string, time.Time and ast.Interval, plus *graph.Vertex and
+// *graph.Edge for variables, and []any for lists. The type of a
+// time.Time property is the one declared by a CAST in the graph
+// schema. Otherwise, like for bind variables, it is a TIMESTAMP, with
+// a time zone if it is not in time.UTC, and a CAST gives a DATE or a
+// TIME.
+package exec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+	"github.com/itergia/pgql-go/printer"
+	"github.com/itergia/pgql-go/sema"
+	"github.com/itergia/pgql-go/types"
+)
+
+// DefaultMaxPathLength is the default of Options.MaxPathLength.
+const DefaultMaxPathLength = 100
+
+// Options configures Query. The zero value is valid.
+type Options struct {
+	// Params are the values of bind variables, by index.
+	Params []any
+
+	// MaxPathLength is the maximum number of repetitions of a
+	// quantified path pattern. Queries needing more fail, rather than
+	// enumerating an exponential number of paths. Zero means
+	// DefaultMaxPathLength.
+	MaxPathLength int
+}
+
+// Query runs a query against the graph. Graph names of MATCH clauses
+// are ignored. The file maps positions in errors to lines and
+// columns, and may be nil, like in sema.Check. Errors are
+// sema.ErrorLists for invalid queries, or *sema.Error for runtime
+// errors, like a missing bind variable.
+func Query(g *graph.Graph, file *ast.File, stmt *ast.SelectStmt, opts *Options) (*Rows, error) {
+	if file == nil {
+		file = ast.NewFile("")
+	}
+	info, err := sema.Check(file, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &executor{g: g, info: info, file: file}
+	if opts != nil {
+		x.opts = *opts
+	}
+	if err := x.checkGrouping(stmt); err != nil {
+		return nil, err
+	}
+	cols, rows, err := x.query(stmt, bindings{})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			row[i] = export(v)
+		}
+	}
+	return &Rows{cols: cols, rows: rows, cur: -1}, nil
+}
+
+// Rows is the result of a query.
+type Rows struct {
+	cols []string
+	rows [][]any
+	cur  int
+}
+
+// Columns returns the names of the columns.
+func (r *Rows) Columns() []string { return r.cols }
+
+// Next advances to the next row, and returns false if there is none.
+// It must be called before reading the first row.
+func (r *Rows) Next() bool {
+	if r.cur < len(r.rows) {
+		r.cur++
+	}
+	return r.cur < len(r.rows)
+}
+
+// Row returns the values of the current row.
+func (r *Rows) Row() []any { return r.rows[r.cur] }
+
+// bindings maps variables to their values. Bindings are copied before
+// being extended.
+type bindings map[*sema.Object]any
+
+func (b bindings) with(obj *sema.Object, v any) bindings {
+	nb := make(bindings, len(b)+1)
+	for k, v := range b {
+		nb[k] = v
+	}
+	nb[obj] = v
+	return nb
+}
+
+// An env is what expressions are evaluated in.
+type env struct {
+	b bindings
+
+	// group holds the rows of a group, when the query aggregates.
+	// Non-aggregated expressions are evaluated with the first row.
+	group   []bindings
+	grouped bool
+}
+
+type executor struct {
+	g    *graph.Graph
+	info *sema.Info
+	file *ast.File
+	opts Options
+}
+
+func (x *executor) errorf(n ast.Node, format string, args ...any) error {
+	return &sema.Error{Pos: x.file.Position(n.Pos()), Msg: fmt.Sprintf(format, args...)}
+}
+
+// A result is a row being built.
+type result struct {
+	vals []any
+	env  *env  // With SELECT aliases bound, for ORDER BY.
+	keys []any // ORDER BY values.
+}
+
+// query runs a query, or a subquery correlated with outer.
+func (x *executor) query(s *ast.SelectStmt, outer bindings) ([]string, [][]any, error) {
+	bs := []bindings{outer}
+	for _, mc := range s.From {
+		var err error
+		if bs, err = x.match(bs, mc); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var envs []*env
+	for _, b := range bs {
+		e := &env{b: b}
+		if ok, err := x.cond(s.Where, e); err != nil {
+			return nil, nil, err
+		} else if ok {
+			envs = append(envs, e)
+		}
+	}
+
+	envs, err := x.groupBy(s, outer, envs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.Having != nil {
+		var kept []*env
+		for _, e := range envs {
+			if ok, err := x.cond(s.Having, e); err != nil {
+				return nil, nil, err
+			} else if ok {
+				kept = append(kept, e)
+			}
+		}
+		envs = kept
+	}
+
+	cols, rs, err := x.project(s, envs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rs, err = x.orderBy(s, rs); err != nil {
+		return nil, nil, err
+	}
+	if s.Distinct {
+		rs = distinct(rs)
+	}
+	if rs, err = x.limit(s, rs); err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([][]any, len(rs))
+	for i, r := range rs {
+		rows[i] = r.vals
+	}
+	return cols, rows, nil
+}
+
+// cond evaluates an optional condition. NULL is false.
+func (x *executor) cond(e ast.Expr, env *env) (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+	v, err := x.eval(e, env)
+	if err != nil {
+		return false, err
+	}
+	switch v := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	default:
+		return false, x.errorf(e, "condition is %s, not BOOLEAN", typeName(v))
+	}
+}
+
+// groupBy groups the rows, if the query has GROUP BY or aggregates.
+// Without GROUP BY, all rows are a single group, even if there are
+// none.
+func (x *executor) groupBy(s *ast.SelectStmt, outer bindings, envs []*env) ([]*env, error) {
+	if len(s.GroupBy) == 0 {
+		if !x.hasAggregate(s) {
+			return envs, nil
+		}
+		g := &env{b: outer, grouped: true}
+		for _, e := range envs {
+			g.group = append(g.group, e.b)
+		}
+		if len(g.group) > 0 {
+			g.b = g.group[0]
+		}
+		return []*env{g}, nil
+	}
+
+	var groups []*env
+	byKey := map[string]*env{}
+	for _, e := range envs {
+		vals := make([]any, len(s.GroupBy))
+		for i, ne := range s.GroupBy {
+			v, err := x.eval(ne.Expr, e)
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = v
+		}
+
+		k := listKey(vals)
+		g := byKey[k]
+		if g == nil {
+			g = &env{b: e.b, grouped: true}
+			for i, ne := range s.GroupBy {
+				if ne.Name != nil {
+					g.b = g.b.with(x.info.Defs[ne.Name], vals[i])
+				}
+			}
+			byKey[k] = g
+			groups = append(groups, g)
+		}
+		g.group = append(g.group, e.b)
+	}
+	return groups, nil
+}
+
+// hasAggregate returns true if the SELECT, HAVING or ORDER BY clauses
+// of the query have aggregates, outside of subqueries. Aggregates over
+// the variables of quantified path patterns do not group rows.
+func (x *executor) hasAggregate(s *ast.SelectStmt) bool {
+	var nodes []ast.Node
+	for _, sel := range s.Sels {
+		if sel.Named != nil {
+			nodes = append(nodes, sel.Named.Expr)
+		}
+	}
+	if s.Having != nil {
+		nodes = append(nodes, s.Having)
+	}
+	for _, t := range s.OrderBy {
+		nodes = append(nodes, t.Expr)
+	}
+
+	found := false
+	for _, n := range nodes {
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AggregateExpr:
+				if len(x.groupVars(n)) > 0 {
+					return false
+				}
+				found = true
+			case *ast.SubqueryExpr:
+				return false
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// checkGrouping checks that the queries grouping rows, including
+// subqueries, only use their variables in aggregates, or in
+// expressions of GROUP BY.
+func (x *executor) checkGrouping(stmt *ast.SelectStmt) error {
+	var errs sema.ErrorList
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		s, ok := n.(*ast.SelectStmt)
+		if !ok || (len(s.GroupBy) == 0 && !x.hasAggregate(s)) {
+			return true
+		}
+
+		// Variables of outer queries are constant in the query.
+		local := map[*sema.Object]bool{}
+		for _, mc := range s.From {
+			ast.Inspect(mc, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && x.info.Defs[id] != nil {
+					local[x.info.Defs[id]] = true
+				}
+				return true
+			})
+		}
+		// Grouping by a variable makes its properties usable.
+		keys := map[string]bool{}
+		for _, ne := range s.GroupBy {
+			if id, ok := ne.Expr.(*ast.Ident); ok {
+				delete(local, x.info.ObjectOf(id))
+			}
+			keys[exprString(ne.Expr)] = true
+		}
+
+		check := func(id *ast.Ident) {
+			if local[x.info.ObjectOf(id)] {
+				errs = append(errs, &sema.Error{Pos: x.file.Position(id.Pos()), Msg: fmt.Sprintf("variable %s must be in GROUP BY or in an aggregate", id.Name)})
+			}
+		}
+		var nodes []ast.Node
+		for _, sel := range s.Sels {
+			if sel.AllOf != nil {
+				check(sel.AllOf)
+			} else {
+				nodes = append(nodes, sel.Named.Expr)
+			}
+		}
+		if s.Having != nil {
+			nodes = append(nodes, s.Having)
+		}
+		for _, t := range s.OrderBy {
+			nodes = append(nodes, t.Expr)
+		}
+		for _, n := range nodes {
+			ast.Inspect(n, func(n ast.Node) bool {
+				if e, ok := n.(ast.Expr); ok && keys[exprString(e)] {
+					return false
+				}
+				switch n := n.(type) {
+				case *ast.Ident:
+					check(n)
+				case *ast.QIdent:
+					check(n.Names[0])
+					return false
+				case *ast.AggregateExpr:
+					// Horizontal aggregates are evaluated per row.
+					if len(x.groupVars(n)) == 0 {
+						return false
+					}
+				case *ast.SubqueryExpr:
+					return false
+				}
+				return true
+			})
+		}
+		return true
+	})
+
+	if len(errs) > 0 {
+		errs.Sort()
+		return errs
+	}
+	return nil
+}
+
+// exprString returns the source of an expression, to compare it with
+// the expressions of GROUP BY.
+func exprString(e ast.Expr) string {
+	var sb strings.Builder
+	if err := printer.Fprint(&sb, e); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// project computes the SELECT elements of each row.
+func (x *executor) project(s *ast.SelectStmt, envs []*env) ([]string, []*result, error) {
+	if len(s.Sels) == 0 {
+		return x.projectAll(s, envs)
+	}
+
+	// Columns of "v.*" are the properties of all elements.
+	var cols []string
+	props := make([][]string, len(s.Sels))
+	for i, sel := range s.Sels {
+		if sel.AllOf == nil {
+			cols = append(cols, types.ColumnName(sel.Named))
+			continue
+		}
+
+		obj := x.info.ObjectOf(sel.AllOf)
+		seen := map[string]bool{}
+		for _, e := range envs {
+			for name := range elementProps(e.b[obj]) {
+				if !seen[name] {
+					seen[name] = true
+					props[i] = append(props[i], name)
+				}
+			}
+		}
+		sort.Strings(props[i])
+
+		var prefix string
+		if sel.Prefix != nil {
+			prefix = sel.Prefix.Text
+		}
+		for _, name := range props[i] {
+			cols = append(cols, prefix+name)
+		}
+	}
+
+	rs := make([]*result, len(envs))
+	for j, e := range envs {
+		r := &result{env: &env{b: e.b, group: e.group, grouped: e.grouped}}
+		for i, sel := range s.Sels {
+			if sel.AllOf != nil {
+				ps := elementProps(e.b[x.info.ObjectOf(sel.AllOf)])
+				for _, name := range props[i] {
+					r.vals = append(r.vals, ps[name])
+				}
+				continue
+			}
+
+			v, err := x.eval(sel.Named.Expr, e)
+			if err != nil {
+				return nil, nil, err
+			}
+			r.vals = append(r.vals, v)
+			if sel.Named.Name != nil {
+				r.env.b = r.env.b.with(x.info.Defs[sel.Named.Name], v)
+			}
+		}
+		rs[j] = r
+	}
+	return cols, rs, nil
+}
+
+// projectAll handles "SELECT *", which selects the vertex and edge
+// variables of the MATCH clauses.
+func (x *executor) projectAll(s *ast.SelectStmt, envs []*env) ([]string, []*result, error) {
+	var cols []string
+	var objs []*sema.Object
+	seen := map[*sema.Object]bool{}
+	for _, mc := range s.From {
+		for _, p := range mc.Patterns {
+			for _, id := range patternVars(p) {
+				if obj := x.info.ObjectOf(id); obj != nil && !seen[obj] {
+					seen[obj] = true
+					objs = append(objs, obj)
+					cols = append(cols, obj.Name)
+				}
+			}
+		}
+	}
+
+	rs := make([]*result, len(envs))
+	for i, e := range envs {
+		r := &result{env: e}
+		for _, obj := range objs {
+			r.vals = append(r.vals, e.b[obj])
+		}
+		rs[i] = r
+	}
+	return cols, rs, nil
+}
+
+// patternVars returns the named variables of a path pattern, in
+// order.
+func patternVars(p *ast.PathPattern) []*ast.Ident {
+	var ids []*ast.Ident
+	add := func(id *ast.Ident) {
+		if id != nil {
+			ids = append(ids, id)
+		}
+	}
+	for i, v := range p.Vs {
+		if i > 0 {
+			pp := p.Es[i-1]
+			for _, e := range pp.Es {
+				add(e.Name)
+			}
+		}
+		add(v.Name)
+	}
+	return ids
+}
+
+// elementProps returns the properties of a vertex or edge, or nil.
+func elementProps(v any) map[string]any {
+	switch v := v.(type) {
+	case *graph.Vertex:
+		return v.Props
+	case *graph.Edge:
+		return v.Props
+	}
+	return nil
+}
+
+// orderBy sorts the rows. NULLs come last in ascending order, and
+// first in descending order.
+func (x *executor) orderBy(s *ast.SelectStmt, rs []*result) ([]*result, error) {
+	if len(s.OrderBy) == 0 {
+		return rs, nil
+	}
+
+	for _, r := range rs {
+		for _, t := range s.OrderBy {
+			v, err := x.eval(t.Expr, r.env)
+			if err != nil {
+				return nil, err
+			}
+			r.keys = append(r.keys, v)
+		}
+	}
+
+	var err error
+	sort.SliceStable(rs, func(i, j int) bool {
+		for k, t := range s.OrderBy {
+			c, ok := compareOrder(rs[i].keys[k], rs[j].keys[k])
+			if !ok && err == nil {
+				err = x.errorf(t.Expr, "cannot order %s and %s", typeName(rs[i].keys[k]), typeName(rs[j].keys[k]))
+			}
+			if t.Order == ast.DescOrder {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return rs, err
+}
+
+// distinct removes duplicate rows, keeping the first ones.
+func distinct(rs []*result) []*result {
+	var kept []*result
+	seen := map[string]bool{}
+	for _, r := range rs {
+		k := listKey(r.vals)
+		if !seen[k] {
+			seen[k] = true
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// limit applies OFFSET and LIMIT.
+func (x *executor) limit(s *ast.SelectStmt, rs []*result) ([]*result, error) {
+	if s.Offset != nil {
+		n, err := x.count(s.Offset)
+		if err != nil {
+			return nil, err
+		}
+		if n > int64(len(rs)) {
+			n = int64(len(rs))
+		}
+		rs = rs[n:]
+	}
+	if s.Limit != nil {
+		n, err := x.count(s.Limit)
+		if err != nil {
+			return nil, err
+		}
+		if n < int64(len(rs)) {
+			rs = rs[:n]
+		}
+	}
+	return rs, nil
+}
+
+// count evaluates a LIMIT or OFFSET value.
+func (x *executor) count(e ast.Expr) (int64, error) {
+	v, err := x.eval(e, &env{})
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(int64)
+	if !ok || n < 0 {
+		return 0, x.errorf(e, "%v is not a valid count", v)
+	}
+	return n, nil
+}
+
+// listKey returns a string identifying a list of values.
+func listKey(vs []any) string {
+	ks := make([]string, len(vs))
+	for i, v := range vs {
+		ks[i] = valueKey(v)
+	}
+	return strings.Join(ks, ",")
+}
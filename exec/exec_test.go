@@ -0,0 +1,422 @@
+package exec
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+	"github.com/itergia/pgql-go/parser"
+)
+
+// newGraph returns a graph of people knowing each other, and working
+// for companies.
+func newGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+
+	g := graph.New(nil)
+	vertex := func(label, name string, age any) graph.ID {
+		props := map[string]any{"name": name}
+		if age != nil {
+			props["age"] = age
+		}
+		v, err := g.AddVertex([]string{label}, props)
+		if err != nil {
+			t.Fatalf("AddVertex failed: %v", err)
+		}
+		return v.ID
+	}
+	edge := func(src, dst graph.ID, label string, props map[string]any) {
+		if _, err := g.AddEdge(src, dst, []string{label}, props); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+
+	alice := vertex("person", "Alice", 30)
+	bob := vertex("person", "Bob", 25)
+	carol := vertex("person", "Carol", 35)
+	dave := vertex("person", "Dave", nil)
+	acme := vertex("company", "Acme", nil)
+	edge(alice, bob, "knows", map[string]any{"since": 2010})
+	edge(bob, carol, "knows", map[string]any{"since": 2015})
+	edge(carol, alice, "knows", map[string]any{"since": 2020})
+	edge(alice, acme, "worksAt", nil)
+	edge(bob, acme, "worksAt", nil)
+	edge(dave, dave, "knows", nil)
+	return g
+}
+
+// query runs a query, and formats its columns and rows, with vertices
+// and edges as their name or ID.
+func query(t *testing.T, g *graph.Graph, s string, params ...any) ([]string, error) {
+	t.Helper()
+
+	stmt, err := parser.ParseStatement(s)
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+	rows, err := Query(g, nil, stmt.(*ast.SelectStmt), &Options{Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	got := []string{strings.Join(rows.Columns(), " ")}
+	for rows.Next() {
+		var vals []string
+		for _, v := range rows.Row() {
+			switch v := v.(type) {
+			case *graph.Vertex:
+				vals = append(vals, fmt.Sprint(v.Props["name"]))
+			case *graph.Edge:
+				vals = append(vals, fmt.Sprintf("e%d", v.ID))
+			default:
+				vals = append(vals, fmt.Sprint(v))
+			}
+		}
+		got = append(got, strings.Join(vals, " "))
+	}
+	return got, nil
+}
+
+func TestQuery(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name   string
+		Input  string
+		Params []any
+		Want   []string
+	}{
+		{
+			"vertices",
+			"SELECT n.name FROM MATCH (n:person)",
+			nil,
+			[]string{"name", "Alice", "Bob", "Carol", "Dave"},
+		},
+		{
+			"labelAlts",
+			"SELECT n.name FROM MATCH (n:company|person) WHERE n.age IS NULL",
+			nil,
+			[]string{"name", "Dave", "Acme"},
+		},
+		{
+			"outgoing",
+			"SELECT a.name AS a, b.name AS b FROM MATCH (a) -[:knows]-> (b)",
+			nil,
+			[]string{"a b", "Alice Bob", "Bob Carol", "Carol Alice", "Dave Dave"},
+		},
+		{
+			"incoming",
+			"SELECT b.name FROM MATCH (a:person) <-[e:knows]- (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"name", "Carol"},
+		},
+		{
+			"anyDir",
+			"SELECT b.name, e.since FROM MATCH (a) -[e]- (b) WHERE a.name = 'Alice' OR a.name = 'Dave'",
+			nil,
+			[]string{"name since", "Bob 2010", "Acme <nil>", "Carol 2020", "Dave <nil>"},
+		},
+		{
+			"path",
+			"SELECT a, b, c FROM MATCH (a) -> (b) -> (c:company)",
+			nil,
+			[]string{"a b c", "Alice Bob Acme", "Carol Alice Acme"},
+		},
+		{
+			"repeated",
+			"SELECT a FROM MATCH (a) -> (b) -> (c) -> (a) WHERE a.age > 26 OR a.age IS NULL",
+			nil,
+			[]string{"a", "Alice", "Carol", "Dave"},
+		},
+		{
+			"matches",
+			"SELECT a, c.name FROM MATCH (a:person) -[:worksAt]-> (c), MATCH (a) -> (b:person) WHERE b.age < 30",
+			nil,
+			[]string{"a name", "Alice Acme"},
+		},
+		{
+			"star",
+			"SELECT * FROM MATCH (a) -[e:knows]-> (b) WHERE e.since > 2012",
+			nil,
+			[]string{"a e b", "Bob e7 Carol", "Carol e8 Alice"},
+		},
+		{
+			"allOf",
+			"SELECT e.*, b.* PREFIX 'b_' FROM MATCH (a) -[e:knows]-> (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"since b_age b_name", "2010 25 Bob"},
+		},
+		{
+			"groupBy",
+			"SELECT c.name AS company, COUNT(*) AS n, AVG(a.age) AS age FROM MATCH (a) -[:worksAt]-> (c) GROUP BY c",
+			nil,
+			[]string{"company n age", "Acme 2 27.5"},
+		},
+		{
+			"groupAlias",
+			"SELECT old, COUNT(*), MIN(n.name), MAX(n.name), SUM(n.age) FROM MATCH (n:person) GROUP BY n.age > 28 AS old ORDER BY old",
+			nil,
+			[]string{"old COUNT(*) MIN(n.name) MAX(n.name) SUM(n.age)", "false 1 Bob Bob 25", "true 2 Alice Carol 65", "<nil> 1 Dave Dave <nil>"},
+		},
+		{
+			"groupByExpr",
+			"SELECT n.age + 1 AS a, COUNT(*) AS c FROM MATCH (n) WHERE n.age > 26 GROUP BY n.age ORDER BY n.age",
+			nil,
+			[]string{"a c", "31 1", "36 1"},
+		},
+		{
+			"having",
+			"SELECT b.name, COUNT(DISTINCT a) AS n, LISTAGG(a.name, ',') AS l FROM MATCH (a) -> (b) GROUP BY b HAVING COUNT(*) > 1",
+			nil,
+			[]string{"name n l", "Acme 2 Alice,Bob"},
+		},
+		{
+			"aggregateNoRows",
+			"SELECT COUNT(*), SUM(n.age), ARRAY_AGG(n.name) FROM MATCH (n:nobody)",
+			nil,
+			[]string{"COUNT(*) SUM(n.age) ARRAY_AGG(n.name)", "0 <nil> <nil>"},
+		},
+		{
+			"orderBy",
+			"SELECT n.name, n.age FROM MATCH (n:person) ORDER BY n.age DESC, n.name",
+			nil,
+			[]string{"name age", "Dave <nil>", "Carol 35", "Alice 30", "Bob 25"},
+		},
+		{
+			"orderByAlias",
+			"SELECT n.name AS name, n.age + 1 AS next FROM MATCH (n:person) ORDER BY next LIMIT 2",
+			nil,
+			[]string{"name next", "Bob 26", "Alice 31"},
+		},
+		{
+			"limitOffset",
+			"SELECT n.name FROM MATCH (n) ORDER BY n.name LIMIT 2 OFFSET 1",
+			nil,
+			[]string{"name", "Alice", "Bob"},
+		},
+		{
+			"offsetPastEnd",
+			"SELECT n.name FROM MATCH (n) OFFSET 10",
+			nil,
+			[]string{"name"},
+		},
+		{
+			"distinct",
+			"SELECT DISTINCT LABEL(b) AS l FROM MATCH (a) -> (b) ORDER BY l DESC",
+			nil,
+			[]string{"l", "person", "company"},
+		},
+		{
+			"exists",
+			"SELECT a.name FROM MATCH (a:person) WHERE NOT EXISTS (SELECT * FROM MATCH (a) -[:worksAt]-> ())",
+			nil,
+			[]string{"name", "Carol", "Dave"},
+		},
+		{
+			"scalarSubquery",
+			"SELECT a.name, (SELECT COUNT(*) FROM MATCH (a) -[:knows]- (b)) AS n FROM MATCH (a:person) WHERE a.age >= 30",
+			nil,
+			[]string{"name n", "Alice 2", "Carol 2"},
+		},
+		{
+			"params",
+			"SELECT n.name FROM MATCH (n:person) WHERE n.age > ? AND n.name <> ? ORDER BY n.name LIMIT ?",
+			[]any{20, "Bob", 1},
+			[]string{"name", "Alice"},
+		},
+		{
+			"nulls",
+			"SELECT n.name, n.age > 26 OR true AS o, n.age > 26 AND true AS a, n.age * 2 AS d FROM MATCH (n) WHERE n.name = 'Dave'",
+			nil,
+			[]string{"name o a d", "Dave true <nil> <nil>"},
+		},
+		{
+			"arithmetic",
+			"SELECT 7 / 2 AS q, 7 % 2 AS r, 7 / 2.0 AS f, -n.age AS n, 'a' || n.name AS s FROM MATCH (n) WHERE n.name = 'Bob'",
+			nil,
+			[]string{"q r f n s", "3 1 3.5 -25 aBob"},
+		},
+		{
+			"labels",
+			"SELECT LABEL(e), LABELS(b) FROM MATCH () -[e]-> (b:company) LIMIT 1",
+			nil,
+			[]string{"LABEL(e) LABELS(b)", "worksAt [company]"},
+		},
+		{
+			"quantified",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ALL (a) -[e:knows]->{1,2} (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"name n", "Bob 1", "Carol 2"},
+		},
+		{
+			"quantifiedCycle",
+			"SELECT b.name, ARRAY_AGG(e.since) AS s FROM MATCH ALL (a) -[e:knows]->{,4} (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"name s", "Alice <nil>", "Bob [2010]", "Carol [2010 2015]", "Alice [2010 2015 2020]"},
+		},
+		{
+			"quantifiedAny",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ANY (a) -[e]->+ (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"name n", "Alice 3", "Bob 1", "Carol 2", "Acme 1"},
+		},
+		{
+			"quantifiedSelfLoop",
+			"SELECT COUNT(*) FROM MATCH ANY (a) -[:knows]->+ (b) WHERE a.name = 'Dave'",
+			nil,
+			[]string{"COUNT(*)", "1"},
+		},
+		{
+			"quantifiedOptional",
+			"SELECT a.name, c.name, COUNT(e) FROM MATCH ALL (a) -[e:worksAt]->? (c) WHERE a.name = 'Bob' OR a.name = 'Carol'",
+			nil,
+			[]string{"name name COUNT(e)", "Bob Bob 0", "Bob Acme 1", "Carol Carol 0"},
+		},
+		{
+			"quantifiedWhere",
+			"SELECT b.name, LISTAGG(x.name, ',') AS l FROM MATCH ANY (a) (-[e]-> (x) WHERE x.age > 20)+ (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"name l", "Alice Bob,Carol,Alice", "Bob Bob", "Carol Bob,Carol"},
+		},
+		{
+			"quantifiedList",
+			"SELECT e.since, SUM(e.since - 2000) AS s FROM MATCH ALL (a) -[e:knows]->{3} (b) WHERE a.name = 'Bob'",
+			nil,
+			[]string{"since s", "[2015 2020 2010] 45"},
+		},
+		{
+			"quantifiedGroupBy",
+			"SELECT a.name, COUNT(*) AS n, MAX(COUNT(e)) AS m FROM MATCH ALL (a:person) -[e:knows]->{1,3} (b) GROUP BY a ORDER BY a.name",
+			nil,
+			[]string{"name n m", "Alice 3 3", "Bob 3 3", "Carol 3 3", "Dave 1 1"},
+		},
+		{
+			"reachability",
+			"SELECT b.name FROM MATCH (a) -/:knows+/-> (b) WHERE a.name = 'Alice'",
+			nil,
+			[]string{"name", "Bob", "Carol", "Alice"},
+		},
+		{
+			"reachabilityHop",
+			"SELECT a.name FROM MATCH (a) -/:worksAt/-> (c) ORDER BY a.name",
+			nil,
+			[]string{"name", "Alice", "Bob"},
+		},
+		{
+			"reachabilityMacro",
+			"PATH older AS (x) -[:knows]-> (y) WHERE y.age > x.age SELECT b.name FROM MATCH (a) -/:older*/-> (b) WHERE a.name = 'Bob'",
+			nil,
+			[]string{"name", "Bob", "Carol"},
+		},
+		{
+			"reachabilityMacroIncoming",
+			"PATH older AS (x) -[:knows]-> (y) WHERE y.age > x.age SELECT b.name FROM MATCH (a) <-/:older+/- (b) WHERE a.name = 'Carol'",
+			nil,
+			[]string{"name", "Bob"},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := query(t, g, tst.Input, tst.Params...)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"undefined", "SELECT x FROM MATCH (n)", "at 1:8: undefined variable x"},
+		{"missingParam", "SELECT n FROM MATCH (n) WHERE n.age > ?", "at 1:39: missing value for bind variable 0"},
+		{"compare", "SELECT n FROM MATCH (n) WHERE n.age = n.name", "at 1:31: cannot compare LONG and STRING"},
+		{"division", "SELECT n.age / 0 FROM MATCH (n)", "at 1:8: division by zero"},
+		{"condition", "SELECT n FROM MATCH (n) WHERE n.name", "at 1:31: condition is STRING, not BOOLEAN"},
+		{"scalar", "SELECT (SELECT m FROM MATCH (m)) FROM MATCH (n)", "at 1:8: scalar subquery returns 5 rows"},
+		{"quantifier", "SELECT n FROM MATCH ALL (n) ->{3,1} (m)", "at 1:31: quantifier maximum 1 is less than its minimum 3"},
+		{"notGrouped", "SELECT n.name, COUNT(*) FROM MATCH (n)", "at 1:8: variable n must be in GROUP BY or in an aggregate"},
+		{"notGroupedHaving", "SELECT n.age FROM MATCH (n) GROUP BY n.age HAVING n.name = 'x'", "at 1:51: variable n must be in GROUP BY or in an aggregate"},
+		{"notGroupedOrderBy", "SELECT COUNT(*) FROM MATCH (n) GROUP BY n.age ORDER BY n.name", "at 1:56: variable n must be in GROUP BY or in an aggregate"},
+		{"notGroupedSubquery", "SELECT (SELECT m.name, COUNT(*) FROM MATCH (m)) FROM MATCH (n)", "at 1:16: variable m must be in GROUP BY or in an aggregate"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := query(t, g, tst.Input)
+			if err == nil {
+				t.Fatalf("Query succeeded, want %q", tst.Want)
+			}
+			if diff := cmp.Diff(tst.Want, err.Error()); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryPosition(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"check", "SELECT n.a\nFROM MATCH (n)\nWHERE m.a = 1;", "at 3:7: undefined variable m"},
+		{"runtime", "SELECT n.a\nFROM MATCH (n)\nWHERE n.age > ?;", "at 3:15: missing value for bind variable 0"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := parser.Parse(strings.NewReader(tst.Input))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			_, err = Query(g, stmts.File, stmts.Stmts[0].(*ast.SelectStmt), nil)
+			if err == nil {
+				t.Fatalf("Query succeeded, want %q", tst.Want)
+			}
+			if diff := cmp.Diff(tst.Want, err.Error()); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQueryMaxPathLength(t *testing.T) {
+	g := newGraph(t)
+
+	stmt, err := parser.ParseStatement("SELECT COUNT(e) FROM MATCH ANY (a) -[e:knows]->+ (b)")
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+
+	if _, err := Query(g, nil, stmt.(*ast.SelectStmt), &Options{MaxPathLength: 3}); err != nil {
+		t.Errorf("Query failed: %v", err)
+	}
+
+	_, err = Query(g, nil, stmt.(*ast.SelectStmt), &Options{MaxPathLength: 2})
+	want := "at 1:48: quantified path pattern exceeds 2 repetitions"
+	if err == nil || err.Error() != want {
+		t.Errorf("Query: got %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,448 @@
+package exec
+
+import (
+	"math"
+	"sort"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+	"github.com/itergia/pgql-go/sema"
+)
+
+// match extends each row with the matches of a MATCH clause. ANY
+// patterns have one match per pair of source and destination, found
+// like ANY SHORTEST ones, and SHORTEST and CHEAPEST patterns are found
+// by searches.
+func (x *executor) match(bs []bindings, mc *ast.MatchClause) ([]bindings, error) {
+	if mc.Rows != nil && mc.Rows.Kind != ast.DefaultMatchRows && mc.Rows.Kind != ast.OneRowPerMatch {
+		return nil, x.errorf(mc.Rows, "ONE ROW PER VERTEX and ONE ROW PER STEP are not supported")
+	}
+	for _, p := range mc.Patterns {
+		var out []bindings
+		collect := func(b bindings) error {
+			out = append(out, b)
+			return nil
+		}
+		for _, b := range bs {
+			for _, v := range x.candidates(b, p.Vs[0]) {
+				var err error
+				if p.Metric != ast.NoMetric || p.Cardinality == ast.AnyCardinality {
+					err = x.find(b, p, v, collect)
+				} else {
+					err = x.path(b, p, v, func(b bindings, _ *graph.Vertex) error {
+						return collect(b)
+					})
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		bs = out
+	}
+	return bs, nil
+}
+
+// path calls emit with the bindings, and the last vertex, of each
+// match of a path pattern starting at v, by backtracking along its
+// vertices and edges.
+func (x *executor) path(b bindings, p *ast.PathPattern, v *graph.Vertex, emit func(bindings, *graph.Vertex) error) error {
+	var step func(i int, b bindings, v *graph.Vertex) error
+	step = func(i int, b bindings, v *graph.Vertex) error {
+		b, ok := x.bindVertex(b, p.Vs[i], v)
+		if !ok {
+			return nil
+		}
+		if i == len(p.Es) {
+			return emit(b, v)
+		}
+
+		pp := p.Es[i]
+		next := func(b bindings, v *graph.Vertex) error { return step(i+1, b, v) }
+		switch {
+		case pp.Es[0].Reachability:
+			return x.reach(b, pp, v, next)
+		case pp.Quantity != nil:
+			return x.quantified(b, pp, v, next)
+		default:
+			return x.primary(b, pp, v, nil, func(b bindings, _ *graph.Edge, v *graph.Vertex) error {
+				return next(b, v)
+			})
+		}
+	}
+	return step(0, b, v)
+}
+
+// primary calls f with the bindings, edge and destination of each
+// match of a path pattern primary starting at v, ignoring its
+// quantifier. Edges in trail are skipped.
+func (x *executor) primary(b bindings, pp *ast.PathPatternPrimary, v *graph.Vertex, trail []*graph.Edge, f func(bindings, *graph.Edge, *graph.Vertex) error) error {
+	if src := vertexAt(pp.Vs, 0); src != nil {
+		var ok bool
+		if b, ok = x.bindVertex(b, src, v); !ok {
+			return nil
+		}
+	}
+
+	return x.edges(v, pp.Es[0].Dir, func(e *graph.Edge, next *graph.Vertex) error {
+		if hasEdge(trail, e) {
+			return nil
+		}
+		b, ok, err := x.step(b, pp, e, next)
+		if err != nil || !ok {
+			return err
+		}
+		return f(b, e, next)
+	})
+}
+
+// step binds the edge and destination of a path pattern primary, and
+// checks its condition.
+func (x *executor) step(b bindings, pp *ast.PathPatternPrimary, e *graph.Edge, dst *graph.Vertex) (bindings, bool, error) {
+	b, ok := x.bindEdge(b, pp.Es[0], e)
+	if !ok {
+		return nil, false, nil
+	}
+	if vp := vertexAt(pp.Vs, 1); vp != nil {
+		if b, ok = x.bindVertex(b, vp, dst); !ok {
+			return nil, false, nil
+		}
+	}
+	ok, err := x.cond(pp.Where, &env{b: b})
+	return b, ok, err
+}
+
+// quantified calls f with the bindings and destination of each match
+// of a quantified path pattern primary starting at v. Its variables
+// are bound to lists of elements, one per repetition. An edge is not
+// repeated within a match, so that cycles are only followed once.
+func (x *executor) quantified(b bindings, pp *ast.PathPatternPrimary, v *graph.Vertex, f func(bindings, *graph.Vertex) error) error {
+	min, max, err := x.bounds(pp.Quantity)
+	if err != nil {
+		return err
+	}
+	limit := x.opts.MaxPathLength
+	if limit <= 0 {
+		limit = DefaultMaxPathLength
+	}
+
+	objs := x.groupObjects(pp)
+	var rep func(n int, v *graph.Vertex, lists [][]any, trail []*graph.Edge) error
+	rep = func(n int, v *graph.Vertex, lists [][]any, trail []*graph.Edge) error {
+		if n >= min {
+			nb := b
+			for i, obj := range objs {
+				nb = nb.with(obj, lists[i])
+			}
+			if err := f(nb, v); err != nil {
+				return err
+			}
+		}
+		if n == max {
+			return nil
+		}
+
+		return x.primary(b, pp, v, trail, func(ib bindings, e *graph.Edge, next *graph.Vertex) error {
+			if n == limit {
+				return x.errorf(pp.Quantity, "quantified path pattern exceeds %d repetitions", limit)
+			}
+			nlists := make([][]any, len(objs))
+			for i, obj := range objs {
+				nlists[i] = append(lists[i][:len(lists[i]):len(lists[i])], ib[obj])
+			}
+			return rep(n+1, next, nlists, append(trail[:len(trail):len(trail)], e))
+		})
+	}
+
+	lists := make([][]any, len(objs))
+	for i := range lists {
+		lists[i] = []any{}
+	}
+	return rep(0, v, lists, nil)
+}
+
+// bounds returns the minimum and maximum repetitions of a quantifier.
+// The maximum is -1 if unbounded.
+func (x *executor) bounds(q *ast.Quantifier) (int, int, error) {
+	bound := func(lit *ast.BasicLit, def int) (int, error) {
+		if lit == nil {
+			return def, nil
+		}
+		v, err := lit.Value()
+		if err != nil {
+			return 0, x.errorf(lit, "%v", err)
+		}
+		if n, ok := v.(int64); ok && n <= math.MaxInt32 {
+			return int(n), nil
+		}
+		return 0, x.errorf(lit, "invalid quantifier bound %s", lit.S)
+	}
+
+	min, err := bound(q.Min, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := bound(q.Max, -1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if max >= 0 && max < min {
+		return 0, 0, x.errorf(q, "quantifier maximum %d is less than its minimum %d", max, min)
+	}
+	return min, max, nil
+}
+
+// groupObjects returns the variables of a quantified path pattern
+// primary.
+func (x *executor) groupObjects(pp *ast.PathPatternPrimary) []*sema.Object {
+	var objs []*sema.Object
+	add := func(id *ast.Ident) {
+		if id == nil {
+			return
+		}
+		obj := x.info.ObjectOf(id)
+		for _, o := range objs {
+			if o == obj {
+				return
+			}
+		}
+		objs = append(objs, obj)
+	}
+	for _, v := range pp.Vs {
+		if v != nil {
+			add(v.Name)
+		}
+	}
+	add(pp.Es[0].Name)
+	return objs
+}
+
+// reach calls f with each vertex reachable from v through a
+// reachability pattern, once. The pattern's labels are edge labels, or
+// path macros, of which every repetition must be a match.
+func (x *executor) reach(b bindings, pp *ast.PathPatternPrimary, v *graph.Vertex, f func(bindings, *graph.Vertex) error) error {
+	min, max := 1, 1
+	if pp.Quantity != nil {
+		var err error
+		if min, max, err = x.bounds(pp.Quantity); err != nil {
+			return err
+		}
+	}
+
+	// Breadth-first search of vertices with the number of
+	// repetitions so far, which no longer matters past the minimum.
+	type state struct {
+		v *graph.Vertex
+		n int
+	}
+	seen := map[state]bool{{v, 0}: true}
+	found := map[*graph.Vertex]bool{}
+	for n, layer := 0, []*graph.Vertex{v}; len(layer) > 0; n++ {
+		if n >= min {
+			for _, v := range layer {
+				if !found[v] {
+					found[v] = true
+					if err := f(b, v); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if n == max {
+			break
+		}
+
+		var next []*graph.Vertex
+		k := n + 1
+		if k > min {
+			k = min
+		}
+		for _, v := range layer {
+			err := x.hop(pp.Es[0], v, func(w *graph.Vertex) {
+				if s := (state{w, k}); !seen[s] {
+					seen[s] = true
+					next = append(next, w)
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+		layer = next
+	}
+	return nil
+}
+
+// hop calls f with the vertices one repetition of a reachability
+// pattern away from v.
+func (x *executor) hop(ep *ast.EdgePattern, v *graph.Vertex, f func(*graph.Vertex)) error {
+	var macros []*ast.PathMacroClause
+	var labels []*ast.Ident
+	for _, l := range ep.LabelAlts {
+		if m := x.info.Macros[l]; m != nil {
+			macros = append(macros, m)
+		} else {
+			labels = append(labels, l)
+		}
+	}
+
+	if len(labels) > 0 || len(macros) == 0 {
+		err := x.edges(v, ep.Dir, func(e *graph.Edge, next *graph.Vertex) error {
+			if hasAnyLabel(e.HasLabel, labels) {
+				f(next)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, m := range macros {
+		if ep.Dir != ast.Incoming {
+			if err := x.macro(m, v, f); err != nil {
+				return err
+			}
+		}
+		if ep.Dir != ast.Outgoing {
+			// Find the matches of the macro ending at v.
+			for _, w := range x.candidates(bindings{}, m.Pattern.Vs[0]) {
+				w := w
+				err := x.macro(m, w, func(end *graph.Vertex) {
+					if end == v {
+						f(w)
+					}
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// macro calls f with the last vertex of each match of a path macro
+// starting at v.
+func (x *executor) macro(m *ast.PathMacroClause, v *graph.Vertex, f func(*graph.Vertex)) error {
+	return x.path(bindings{}, m.Pattern, v, func(b bindings, end *graph.Vertex) error {
+		if ok, err := x.cond(m.Where, &env{b: b}); err != nil || !ok {
+			return err
+		}
+		f(end)
+		return nil
+	})
+}
+
+// candidates returns the vertices that may match a vertex pattern,
+// sorted by ID.
+func (x *executor) candidates(b bindings, vp *ast.VertexPattern) []*graph.Vertex {
+	if vp.Name != nil {
+		if v, ok := b[x.info.ObjectOf(vp.Name)].(*graph.Vertex); ok {
+			return []*graph.Vertex{v}
+		}
+	}
+	if len(vp.LabelAlts) == 0 {
+		return x.g.Vertices()
+	}
+
+	var vs []*graph.Vertex
+	seen := map[graph.ID]bool{}
+	for _, l := range vp.LabelAlts {
+		for _, v := range x.g.VerticesByLabel(l.Name) {
+			if !seen[v.ID] {
+				seen[v.ID] = true
+				vs = append(vs, v)
+			}
+		}
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].ID < vs[j].ID })
+	return vs
+}
+
+// edges calls f with the edges of a vertex in a direction, and the
+// vertex at their other end, until it fails. Self-loops are only
+// reported once for AnyDir.
+func (x *executor) edges(v *graph.Vertex, dir ast.Dir, f func(*graph.Edge, *graph.Vertex) error) error {
+	if dir != ast.Incoming {
+		for _, e := range x.g.OutEdges(v.ID) {
+			if err := f(e, x.g.Vertex(e.Dst)); err != nil {
+				return err
+			}
+		}
+	}
+	if dir != ast.Outgoing {
+		for _, e := range x.g.InEdges(v.ID) {
+			if dir == ast.AnyDir && e.Src == e.Dst {
+				continue
+			}
+			if err := f(e, x.g.Vertex(e.Src)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindVertex binds the variable of a vertex pattern, if the vertex
+// matches it.
+func (x *executor) bindVertex(b bindings, vp *ast.VertexPattern, v *graph.Vertex) (bindings, bool) {
+	if !hasAnyLabel(v.HasLabel, vp.LabelAlts) {
+		return nil, false
+	}
+	return x.bind(b, vp.Name, v)
+}
+
+// bindEdge binds the variable of an edge pattern, if the edge matches
+// it.
+func (x *executor) bindEdge(b bindings, ep *ast.EdgePattern, e *graph.Edge) (bindings, bool) {
+	if !hasAnyLabel(e.HasLabel, ep.LabelAlts) {
+		return nil, false
+	}
+	return x.bind(b, ep.Name, e)
+}
+
+// bind binds a variable, if any, to an element. A variable already
+// bound must be bound to the same element.
+func (x *executor) bind(b bindings, id *ast.Ident, elem any) (bindings, bool) {
+	if id == nil {
+		return b, true
+	}
+	obj := x.info.ObjectOf(id)
+	if old, ok := b[obj]; ok {
+		return b, old == elem
+	}
+	return b.with(obj, elem), true
+}
+
+// hasAnyLabel returns true if there are no label alternatives, or the
+// element has one of them.
+func hasAnyLabel(has func(string) bool, alts []*ast.Ident) bool {
+	if len(alts) == 0 {
+		return true
+	}
+	for _, l := range alts {
+		if has(l.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// vertexAt returns the i-th vertex pattern of a path pattern primary,
+// or nil if it is not parenthesized.
+func vertexAt(vs []*ast.VertexPattern, i int) *ast.VertexPattern {
+	if i < len(vs) {
+		return vs[i]
+	}
+	return nil
+}
+
+// hasEdge returns true if the edge is in es.
+func hasEdge(es []*graph.Edge, e *graph.Edge) bool {
+	for _, o := range es {
+		if o == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,657 @@
+package exec
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+)
+
+// find calls emit with the bindings of the paths starting at v of a
+// path pattern with an ANY, SHORTEST or CHEAPEST goal, for each
+// destination. ANY goals without a metric find shortest paths.
+// Paths do not repeat vertices, except cycles back to v, nor edges.
+// From v to itself, the empty path comes first if the quantifier
+// allows it, and is followed by the cycles through v for every goal.
+func (x *executor) find(b bindings, p *ast.PathPattern, v *graph.Vertex, emit func(bindings) error) error {
+	b, ok := x.bindVertex(b, p.Vs[0], v)
+	if !ok {
+		return nil
+	}
+	s, err := x.newSearch(b, p)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range x.candidates(b, p.Vs[1]) {
+		if _, ok := x.bindVertex(b, p.Vs[1], w); !ok {
+			continue
+		}
+		rs, err := s.routes(v, w)
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			if b, ok := x.bindVertex(s.bind(r), p.Vs[1], w); ok {
+				if err := emit(b); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// A search finds the paths of a path pattern with an ANY, SHORTEST or
+// CHEAPEST goal, from a source vertex bound in b.
+type search struct {
+	x        *executor
+	b        bindings
+	p        *ast.PathPattern
+	pp       *ast.PathPatternPrimary
+	min, max int // Repetitions. max is -1 if unbounded.
+	limit    int // Options.MaxPathLength.
+	k        int // Number of paths of TOP k.
+
+	fwd, bwd map[*graph.Vertex][]*step
+}
+
+// A step is an edge of a path, with the bindings of the variables of
+// the path pattern primary.
+type step struct {
+	b        bindings
+	e        *graph.Edge
+	from, to *graph.Vertex
+	cost     float64
+}
+
+// A route is a path found by a search.
+type route struct {
+	steps []*step
+	cost  float64
+}
+
+// then returns the route extended with st.
+func (r route) then(st *step) route {
+	return route{steps: append(r.steps[:len(r.steps):len(r.steps)], st), cost: r.cost + st.cost}
+}
+
+// visits returns true if a route starting at v goes through u.
+func (r route) visits(v, u *graph.Vertex) bool {
+	if u == v {
+		return true
+	}
+	for _, st := range r.steps {
+		if st.to == u {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns a string identifying the steps of a route.
+func (r route) key() string {
+	var sb strings.Builder
+	for _, st := range r.steps {
+		fmt.Fprintf(&sb, "%d:%d,", st.e.ID, st.from.ID)
+	}
+	return sb.String()
+}
+
+func (x *executor) newSearch(b bindings, p *ast.PathPattern) (*search, error) {
+	s := &search{
+		x:     x,
+		b:     b,
+		p:     p,
+		pp:    p.Es[0],
+		min:   1,
+		max:   1,
+		limit: x.opts.MaxPathLength,
+		fwd:   map[*graph.Vertex][]*step{},
+		bwd:   map[*graph.Vertex][]*step{},
+	}
+	if s.limit <= 0 {
+		s.limit = DefaultMaxPathLength
+	}
+	if s.pp.Quantity != nil {
+		var err error
+		if s.min, s.max, err = x.bounds(s.pp.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	if p.K != nil {
+		v, err := p.K.Value()
+		if err != nil {
+			return nil, x.errorf(p.K, "%v", err)
+		}
+		k, ok := v.(int64)
+		if !ok || k > math.MaxInt32 {
+			return nil, x.errorf(p.K, "invalid number of paths %s", p.K.S)
+		}
+		s.k = int(k)
+	}
+	return s, nil
+}
+
+// routes returns the paths from v to w satisfying the goal, in order.
+func (s *search) routes(v, w *graph.Vertex) ([]route, error) {
+	// Breadth-first searches do not find cycles.
+	switch {
+	case s.p.Metric != ast.CostMetric && s.p.Cardinality == ast.AnyCardinality && s.min <= 1 && v != w:
+		r, err := s.anyShortest(v, w)
+		if err != nil || r == nil {
+			return nil, err
+		}
+		return []route{*r}, nil
+
+	case s.p.Metric != ast.CostMetric && s.p.Cardinality == ast.AllCardinality && s.min <= 1 && v != w:
+		return s.allShortest(v, w)
+
+	case s.p.Metric == ast.CostMetric && s.p.Cardinality == ast.AnyCardinality && s.min == 0 && v == w:
+		return []route{{}}, nil
+
+	case s.p.Metric == ast.CostMetric && s.p.Cardinality == ast.AnyCardinality && s.min <= 1 && s.max < 0:
+		r, err := s.cheapest(v, w, 0, nil, nil, nil)
+		if err != nil || r == nil {
+			return nil, err
+		}
+		if err := s.checkLength(len(r.steps)); err != nil {
+			return nil, err
+		}
+		return []route{*r}, nil
+	}
+
+	// Other goals enumerate paths in order, keeping those satisfying
+	// the quantifier.
+	k := s.k
+	switch {
+	case s.p.Cardinality == ast.AnyCardinality:
+		k = 1
+	case s.p.Cardinality == ast.TopCardinality && k == 0:
+		return nil, nil
+	}
+	var rs []route
+	var lerr error
+	err := s.yen(v, w, func(r route) bool {
+		if s.p.Cardinality == ast.AllCardinality && len(rs) > 0 && r.cost > rs[0].cost {
+			return false
+		}
+		if len(r.steps) >= s.min {
+			if lerr = s.checkLength(len(r.steps)); lerr != nil {
+				return false
+			}
+			rs = append(rs, r)
+		}
+		return s.p.Cardinality == ast.AllCardinality || len(rs) < k
+	})
+	if err == nil {
+		err = lerr
+	}
+	return rs, err
+}
+
+// tooLong returns true if paths of n steps are not allowed by the
+// quantifier.
+func (s *search) tooLong(n int) bool {
+	return s.max >= 0 && n > s.max
+}
+
+// checkLength returns an error if a path of n steps, which the search
+// returns, is longer than Options.MaxPathLength. Longer paths are
+// only expanded by searches whose work is polynomial.
+func (s *search) checkLength(n int) error {
+	if n > s.limit {
+		return s.x.errorf(s.pp.Quantity, "quantified path pattern exceeds %d repetitions", s.limit)
+	}
+	return nil
+}
+
+// bind returns the bindings of a route. The variables of a quantified
+// path pattern primary are bound to lists.
+func (s *search) bind(r route) bindings {
+	if s.pp.Quantity == nil {
+		return r.steps[0].b
+	}
+
+	b := s.b
+	for _, obj := range s.x.groupObjects(s.pp) {
+		l := make([]any, len(r.steps))
+		for i, st := range r.steps {
+			l[i] = st.b[obj]
+		}
+		b = b.with(obj, l)
+	}
+	return b
+}
+
+// forward returns the steps leaving v.
+func (s *search) forward(v *graph.Vertex) ([]*step, error) {
+	if sts, ok := s.fwd[v]; ok {
+		return sts, nil
+	}
+	var sts []*step
+	err := s.x.edges(v, s.pp.Es[0].Dir, func(e *graph.Edge, w *graph.Vertex) error {
+		st, err := s.step(v, e, w)
+		if st != nil {
+			sts = append(sts, st)
+		}
+		return err
+	})
+	s.fwd[v] = sts
+	return sts, err
+}
+
+// backward returns the steps arriving at w.
+func (s *search) backward(w *graph.Vertex) ([]*step, error) {
+	if sts, ok := s.bwd[w]; ok {
+		return sts, nil
+	}
+	dir := s.pp.Es[0].Dir
+	switch dir {
+	case ast.Outgoing:
+		dir = ast.Incoming
+	case ast.Incoming:
+		dir = ast.Outgoing
+	}
+	var sts []*step
+	err := s.x.edges(w, dir, func(e *graph.Edge, v *graph.Vertex) error {
+		st, err := s.step(v, e, w)
+		if st != nil {
+			sts = append(sts, st)
+		}
+		return err
+	})
+	s.bwd[w] = sts
+	return sts, err
+}
+
+// step returns the step from v to w through e, or nil if it does not
+// match the path pattern primary.
+func (s *search) step(v *graph.Vertex, e *graph.Edge, w *graph.Vertex) (*step, error) {
+	x := s.x
+	b := s.b
+	if vp := vertexAt(s.pp.Vs, 0); vp != nil {
+		var ok bool
+		if b, ok = x.bindVertex(b, vp, v); !ok {
+			return nil, nil
+		}
+	}
+	b, ok, err := x.step(b, s.pp, e, w)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	st := &step{b: b, e: e, from: v, to: w, cost: 1}
+	if s.p.Metric == ast.CostMetric && s.pp.Cost != nil {
+		c, err := x.eval(s.pp.Cost, &env{b: b})
+		if err != nil {
+			return nil, err
+		}
+		f, ok := toFloat(c)
+		switch {
+		case !ok:
+			return nil, x.errorf(s.pp.Cost, "COST is %s, not a number", typeName(c))
+		case f < 0:
+			return nil, x.errorf(s.pp.Cost, "negative COST %v", c)
+		}
+		st.cost = f
+	}
+	return st, nil
+}
+
+// anyShortest returns a shortest path from v to another vertex w, or
+// nil, with a bidirectional breadth-first search.
+func (s *search) anyShortest(v, w *graph.Vertex) (*route, error) {
+	// The steps reaching each vertex from v, and leaving each vertex
+	// towards w, with nil for v and w.
+	fwd := map[*graph.Vertex]*step{v: nil}
+	bwd := map[*graph.Vertex]*step{w: nil}
+	var best *route
+	meet := func(st *step) {
+		var r route
+		for p := fwd[st.from]; p != nil; p = fwd[p.from] {
+			r.steps = append([]*step{p}, r.steps...)
+		}
+		r.steps = append(r.steps, st)
+		for p := bwd[st.to]; p != nil; p = bwd[p.to] {
+			r.steps = append(r.steps, p)
+		}
+		if best == nil || len(r.steps) < len(best.steps) {
+			best = &r
+		}
+	}
+
+	// Expand the smallest frontier, one layer at a time, until they
+	// meet.
+	ff, bf := []*graph.Vertex{v}, []*graph.Vertex{w}
+	for len(ff) > 0 && len(bf) > 0 && best == nil {
+		var next []*graph.Vertex
+		if len(ff) <= len(bf) {
+			for _, u := range ff {
+				sts, err := s.forward(u)
+				if err != nil {
+					return nil, err
+				}
+				for _, st := range sts {
+					if _, ok := bwd[st.to]; ok {
+						meet(st)
+					}
+					if _, ok := fwd[st.to]; !ok {
+						fwd[st.to] = st
+						next = append(next, st.to)
+					}
+				}
+			}
+			ff = next
+		} else {
+			for _, u := range bf {
+				sts, err := s.backward(u)
+				if err != nil {
+					return nil, err
+				}
+				for _, st := range sts {
+					if _, ok := fwd[st.from]; ok {
+						meet(st)
+					}
+					if _, ok := bwd[st.from]; !ok {
+						bwd[st.from] = st
+						next = append(next, st.from)
+					}
+				}
+			}
+			bf = next
+		}
+	}
+	if best == nil || s.tooLong(len(best.steps)) {
+		return nil, nil
+	}
+	if err := s.checkLength(len(best.steps)); err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// allShortest returns the shortest paths from v to another vertex w,
+// with a breadth-first search recording the predecessors of each
+// vertex.
+func (s *search) allShortest(v, w *graph.Vertex) ([]route, error) {
+	dist := map[*graph.Vertex]int{v: 0}
+	preds := map[*graph.Vertex][]*step{}
+	var ends []*step
+	layer := []*graph.Vertex{v}
+	d := 0
+	for ; len(layer) > 0 && len(ends) == 0 && !s.tooLong(d+1); d++ {
+		var next []*graph.Vertex
+		for _, u := range layer {
+			sts, err := s.forward(u)
+			if err != nil {
+				return nil, err
+			}
+			for _, st := range sts {
+				if st.to == w {
+					ends = append(ends, st)
+					continue
+				}
+				if dd, ok := dist[st.to]; !ok {
+					dist[st.to] = d + 1
+					preds[st.to] = []*step{st}
+					next = append(next, st.to)
+				} else if dd == d+1 {
+					preds[st.to] = append(preds[st.to], st)
+				}
+			}
+		}
+		layer = next
+	}
+	if len(ends) == 0 {
+		return nil, nil
+	}
+	// The paths have d steps.
+	if err := s.checkLength(d); err != nil {
+		return nil, err
+	}
+
+	var rs []route
+	var walk func(r route, st *step)
+	walk = func(r route, st *step) {
+		r.steps = append([]*step{st}, r.steps...)
+		r.cost += st.cost
+		if st.from == v {
+			rs = append(rs, r)
+			return
+		}
+		for _, p := range preds[st.from] {
+			walk(r, p)
+		}
+	}
+	for _, st := range ends {
+		walk(route{}, st)
+	}
+	return rs, nil
+}
+
+// cheapest returns a cheapest path from v to w, or nil, with
+// Dijkstra's algorithm. Paths do not go through removed vertices,
+// except w, nor removed edges, nor start with removed first edges.
+// They do not repeat vertices, except if v is w, when the path is a
+// cycle, never the empty path. The path is the end
+// of one with n more steps before v, whose length is bounded by the
+// quantifier. Callers check Options.MaxPathLength on the paths they
+// return.
+func (s *search) cheapest(v, w *graph.Vertex, n int, rmVertices map[*graph.Vertex]bool, rmEdges, rmFirst map[*graph.Edge]bool) (*route, error) {
+	if v == w {
+		return s.cycle(v, n, rmVertices, rmEdges, rmFirst)
+	}
+
+	// With a maximum length, a cheaper path to a vertex may be too
+	// long to be extended, so vertices are visited once per length.
+	type state struct {
+		v *graph.Vertex
+		n int
+	}
+	key := func(v *graph.Vertex, r route) state {
+		if s.max < 0 {
+			return state{v: v}
+		}
+		return state{v, len(r.steps)}
+	}
+
+	q := &queue{}
+	heap.Push(q, &item{v: v})
+	done := map[state]bool{}
+	for q.Len() > 0 {
+		it := heap.Pop(q).(*item)
+		if it.v == w {
+			return &it.r, nil
+		}
+		if done[key(it.v, it.r)] {
+			continue
+		}
+		done[key(it.v, it.r)] = true
+
+		sts, err := s.forward(it.v)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range sts {
+			if it.v == v && rmFirst[st.e] {
+				continue
+			}
+			if rmEdges[st.e] || (st.to != w && rmVertices[st.to]) || it.r.visits(v, st.to) {
+				continue
+			}
+			r := it.r.then(st)
+			if done[key(st.to, r)] {
+				continue
+			}
+			if s.tooLong(n + len(r.steps)) {
+				continue
+			}
+			heap.Push(q, &item{r: r, v: st.to})
+		}
+	}
+	return nil, nil
+}
+
+// cycle returns a cheapest cycle through v, or nil, as a first step
+// followed by a cheapest path back not using its edge. Like in
+// cheapest, n steps precede v.
+func (s *search) cycle(v *graph.Vertex, n int, rmVertices map[*graph.Vertex]bool, rmEdges, rmFirst map[*graph.Edge]bool) (*route, error) {
+	sts, err := s.forward(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *route
+	for _, st := range sts {
+		if rmEdges[st.e] || rmFirst[st.e] || (st.to != v && rmVertices[st.to]) {
+			continue
+		}
+		if s.tooLong(n + 1) {
+			continue
+		}
+		r := route{}.then(st)
+		if st.to != v {
+			rm := map[*graph.Edge]bool{st.e: true}
+			for e := range rmEdges {
+				rm[e] = true
+			}
+			back, err := s.cheapest(st.to, v, n+1, rmVertices, rm, nil)
+			if err != nil {
+				return nil, err
+			}
+			if back == nil {
+				continue
+			}
+			for _, p := range back.steps {
+				r = r.then(p)
+			}
+		}
+		if best == nil || r.cost < best.cost {
+			best = &r
+		}
+	}
+	return best, nil
+}
+
+// yen calls f with the paths from v to w without repeated vertices,
+// from the cheapest, until it returns false, with Yen's algorithm. If
+// v is w, the empty path comes first if the quantifier allows it.
+func (s *search) yen(v, w *graph.Vertex, f func(route) bool) error {
+	if v == w && s.min == 0 && !f(route{}) {
+		return nil
+	}
+	first, err := s.cheapest(v, w, 0, nil, nil, nil)
+	if err != nil || first == nil {
+		return err
+	}
+
+	found := []route{*first}
+	seen := map[string]bool{first.key(): true}
+	var cands []route
+	for f(found[len(found)-1]) {
+		// Deviate from the last path at each of its vertices.
+		last := found[len(found)-1]
+		u := v
+		for i, st := range last.steps {
+			root := route{steps: last.steps[:i:i]}
+			rmFirst := map[*graph.Edge]bool{}
+			for _, r := range found {
+				if len(r.steps) > i && sameSteps(r.steps[:i], root.steps) {
+					rmFirst[r.steps[i].e] = true
+				}
+			}
+			rmEdges := map[*graph.Edge]bool{}
+			rmVertices := map[*graph.Vertex]bool{}
+			for _, p := range root.steps {
+				rmVertices[p.from] = true
+				rmEdges[p.e] = true
+				root.cost += p.cost
+			}
+
+			spur, err := s.cheapest(u, w, i, rmVertices, rmEdges, rmFirst)
+			if err != nil {
+				return err
+			}
+			if spur != nil {
+				r := root
+				for _, p := range spur.steps {
+					r = r.then(p)
+				}
+				if k := r.key(); !seen[k] {
+					seen[k] = true
+					cands = append(cands, r)
+				}
+			}
+			u = st.to
+		}
+
+		if len(cands) == 0 {
+			return nil
+		}
+		next := 0
+		for i, r := range cands {
+			if r.cost < cands[next].cost || (r.cost == cands[next].cost && len(r.steps) < len(cands[next].steps)) {
+				next = i
+			}
+		}
+		found = append(found, cands[next])
+		cands = append(cands[:next], cands[next+1:]...)
+	}
+	return nil
+}
+
+// sameSteps returns true if a and b have the same steps.
+func sameSteps(a, b []*step) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// An item is a path in a queue.
+type item struct {
+	r   route
+	v   *graph.Vertex // Last vertex of r.
+	seq int
+}
+
+// A queue is a priority queue of paths, cheapest first, then in
+// insertion order.
+type queue struct {
+	items []*item
+	seq   int
+}
+
+func (q *queue) Len() int { return len(q.items) }
+
+func (q *queue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if a.r.cost != b.r.cost {
+		return a.r.cost < b.r.cost
+	}
+	return a.seq < b.seq
+}
+
+func (q *queue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *queue) Push(x any) {
+	it := x.(*item)
+	it.seq = q.seq
+	q.seq++
+	q.items = append(q.items, it)
+}
+
+func (q *queue) Pop() any {
+	it := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return it
+}
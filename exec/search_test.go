@@ -0,0 +1,299 @@
+package exec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestSearch(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{
+			"anyShortest",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ANY SHORTEST (a) -[e]->* (b) WHERE a.name = 'Alice' ORDER BY n, b.name",
+			[]string{"name n", "Alice 0", "Acme 1", "Bob 1", "Carol 2"},
+		},
+		{
+			"anyShortestCycle",
+			"SELECT a.name, COUNT(e) AS n FROM MATCH ANY SHORTEST (a) -[e:knows]->+ (a)",
+			[]string{"name n", "Alice 3", "Bob 3", "Carol 3", "Dave 1"},
+		},
+		{
+			"anyShortestWhere",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ANY SHORTEST (a) (-[e]-> (x) WHERE e.since > 2012)* (b) WHERE a.name = 'Bob'",
+			[]string{"name n", "Alice 2", "Bob 0", "Carol 1"},
+		},
+		{
+			"anyShortestMin",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ANY SHORTEST (a) -[e:knows]->{2,} (b) WHERE a.name = 'Alice'",
+			[]string{"name n", "Alice 3", "Carol 2"},
+		},
+		{
+			"anyShortestMax",
+			"SELECT b.name FROM MATCH ANY SHORTEST (a) -[e:knows]->{,1} (b) WHERE a.name = 'Alice'",
+			[]string{"name", "Alice", "Bob"},
+		},
+		{
+			"anyShortestSingle",
+			"SELECT e FROM MATCH ANY SHORTEST (a) -[e:worksAt]-> (b) WHERE a.name = 'Bob'",
+			[]string{"e", "e10"},
+		},
+		{
+			"allShortest",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH ALL SHORTEST (a) (-[e]- (x))+ (b) WHERE a.name = 'Acme' AND b.name = 'Carol'",
+			[]string{"p", "Alice,Carol", "Bob,Carol"},
+		},
+		{
+			"allShortestCycle",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH ALL SHORTEST (a) (-[e:knows]- (x))+ (a) WHERE a.name = 'Alice'",
+			[]string{"p", "Bob,Carol,Alice", "Carol,Bob,Alice"},
+		},
+		{
+			"topShortest",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH TOP 3 SHORTEST (a) (-[e]- (x))* (b) WHERE a.name = 'Acme' AND b.name = 'Carol'",
+			[]string{"p", "Alice,Carol", "Bob,Carol", "Alice,Bob,Carol"},
+		},
+		{
+			"topShortestCycle",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH TOP 3 SHORTEST (a) (-[e:knows]- (x))+ (a) WHERE a.name = 'Alice'",
+			[]string{"p", "Bob,Carol,Alice", "Carol,Bob,Alice"},
+		},
+		{
+			"topShortestEmptyCycle",
+			"SELECT COUNT(e) AS n, LISTAGG(x.name, ',') AS p FROM MATCH TOP 3 SHORTEST (a) (-[e:knows]- (x))* (a) WHERE a.name = 'Alice'",
+			[]string{"n p", "0 <nil>", "3 Bob,Carol,Alice", "3 Carol,Bob,Alice"},
+		},
+		{
+			"anyCheapestEmptyCycle",
+			"SELECT COUNT(e) AS n FROM MATCH ANY CHEAPEST (a) (-[e:knows]- (x))* (a) WHERE a.name = 'Alice'",
+			[]string{"n", "0"},
+		},
+		{
+			"topZero",
+			"SELECT a FROM MATCH TOP 0 SHORTEST (a) ->* (b)",
+			[]string{"a"},
+		},
+		{
+			"anyCheapest",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH ANY CHEAPEST (a) (-[e:knows]- (x) COST CASE WHEN e.since = 2020 THEN 100 ELSE 1 END)* (b) WHERE a.name = 'Alice' AND b.name = 'Carol'",
+			[]string{"p", "Bob,Carol"},
+		},
+		{
+			"topCheapest",
+			"SELECT LISTAGG(x.name, ',') AS p, SUM(e.since) AS s FROM MATCH TOP 2 CHEAPEST (a) (-[e:knows]- (x) COST CASE WHEN e.since = 2020 THEN 100 ELSE 1 END)* (b) WHERE a.name = 'Alice' AND b.name = 'Carol'",
+			[]string{"p s", "Bob,Carol 4025", "Carol 2020"},
+		},
+		{
+			"anyCheapestMax",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH ANY CHEAPEST (a) (-[e:knows]- (x) COST CASE WHEN e.since = 2020 THEN 100 ELSE 1 END){1,1} (b) WHERE a.name = 'Alice' AND b.name = 'Carol'",
+			[]string{"p", "Carol"},
+		},
+		{
+			"topCheapestMax",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH TOP 2 CHEAPEST (a) (-[e:knows]- (x) COST CASE WHEN e.since = 2020 THEN 100 ELSE 1 END){,1} (b) WHERE a.name = 'Alice' AND b.name = 'Carol'",
+			[]string{"p", "Carol"},
+		},
+		{
+			"topCheapestMin",
+			"SELECT LISTAGG(x.name, ',') AS p FROM MATCH TOP 5 CHEAPEST (a) (-[e:knows]- (x) WHERE e.since IS NOT NULL COST e.since - 2000){2,} (b) WHERE a.name = 'Alice'",
+			[]string{"p", "Bob,Carol,Alice", "Carol,Bob,Alice", "Carol,Bob", "Bob,Carol"},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := query(t, g, tst.Input)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearchError(t *testing.T) {
+	g := newGraph(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"negativeCost", "SELECT a FROM MATCH ANY CHEAPEST (a) (-[e]-> COST -1)* (b)", "at 1:51: negative COST -1"},
+		{"nullCost", "SELECT a FROM MATCH ANY CHEAPEST (a) (-[e]-> COST e.since)* (b)", "at 1:51: COST is NULL, not a number"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := query(t, g, tst.Input)
+			if err == nil {
+				t.Fatalf("Query succeeded, want %q", tst.Want)
+			}
+			if diff := cmp.Diff(tst.Want, err.Error()); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearchMaxPathLength(t *testing.T) {
+	g := newGraph(t)
+
+	stmt, err := parser.ParseStatement("SELECT LISTAGG(x.name, ',') AS p FROM MATCH ANY CHEAPEST (a) (-[e:knows]- (x) COST CASE WHEN e.since = 2020 THEN 100 ELSE 1 END)* (b) WHERE a.name = 'Alice' AND b.name = 'Carol'")
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+
+	if _, err := Query(g, nil, stmt.(*ast.SelectStmt), &Options{MaxPathLength: 2}); err != nil {
+		t.Errorf("Query failed: %v", err)
+	}
+
+	_, err = Query(g, nil, stmt.(*ast.SelectStmt), &Options{MaxPathLength: 1})
+	want := "at 1:129: quantified path pattern exceeds 1 repetitions"
+	if err == nil || err.Error() != want {
+		t.Errorf("Query: got %v, want %q", err, want)
+	}
+}
+
+// newChainGraph returns a graph with an edge from a to b, costing 100,
+// and a chain of n edges from a, costing nothing.
+func newChainGraph(t *testing.T, n int) *graph.Graph {
+	t.Helper()
+
+	g := graph.New(nil)
+	vertex := func(label, name string) graph.ID {
+		v, err := g.AddVertex([]string{label}, map[string]any{"name": name})
+		if err != nil {
+			t.Fatalf("AddVertex failed: %v", err)
+		}
+		return v.ID
+	}
+	edge := func(src, dst graph.ID, cost int) {
+		if _, err := g.AddEdge(src, dst, []string{"edge"}, map[string]any{"c": cost}); err != nil {
+			t.Fatalf("AddEdge failed: %v", err)
+		}
+	}
+
+	a := vertex("origin", "a")
+	edge(a, vertex("target", "b"), 100)
+	u := a
+	for i := 0; i < n; i++ {
+		w := vertex("chain", fmt.Sprint("c", i))
+		edge(u, w, 0)
+		u = w
+	}
+	return g
+}
+
+func TestSearchLongChain(t *testing.T) {
+	g := newChainGraph(t, 150)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{
+			"anyCheapest",
+			"SELECT b.name, SUM(e.c) AS c FROM MATCH ANY CHEAPEST (a:origin) (-[e]-> COST e.c)* (b:target)",
+			[]string{"name c", "b 100"},
+		},
+		{
+			"topCheapest",
+			"SELECT b.name, SUM(e.c) AS c FROM MATCH TOP 2 CHEAPEST (a:origin) (-[e]-> COST e.c)* (b:target)",
+			[]string{"name c", "b 100"},
+		},
+		{
+			"anyShortest",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ANY SHORTEST (a:origin) -[e]->* (b:target)",
+			[]string{"name n", "b 1"},
+		},
+		{
+			"allShortest",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ALL SHORTEST (a:origin) -[e]->* (b:target)",
+			[]string{"name n", "b 1"},
+		},
+		{
+			"any",
+			"SELECT b.name, COUNT(e) AS n FROM MATCH ANY (a:origin) -[e]->* (b:target)",
+			[]string{"name n", "b 1"},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := query(t, g, tst.Input)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearchComplete(t *testing.T) {
+	// A complete directed graph has an exponential number of trails.
+	g := graph.New(nil)
+	var ids []graph.ID
+	for i := 0; i < 7; i++ {
+		v, err := g.AddVertex(nil, nil)
+		if err != nil {
+			t.Fatalf("AddVertex failed: %v", err)
+		}
+		ids = append(ids, v.ID)
+	}
+	for _, src := range ids {
+		for _, dst := range ids {
+			if src == dst {
+				continue
+			}
+			if _, err := g.AddEdge(src, dst, nil, nil); err != nil {
+				t.Fatalf("AddEdge failed: %v", err)
+			}
+		}
+	}
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{"count", "SELECT COUNT(*) AS n FROM MATCH ANY (a) -[e]->* (b)", []string{"n", "49"}},
+		{"longest", "SELECT COUNT(e) AS n FROM MATCH ANY (a) -[e]->* (b) ORDER BY n DESC LIMIT 1", []string{"n", "1"}},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := query(t, g, tst.Input)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Query: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/graph"
+)
+
+// normalize returns a bind variable or property value with Go integer
+// and float types widened to int64 and float64, and time.Time as a
+// datetime of the type, or a TIMESTAMP if the type is unknown.
+func normalize(v any, dt ast.DataType) any {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case time.Time:
+		return newDatetime(v, dt)
+	}
+	return v
+}
+
+// export returns a value as seen by users of Rows, with datetimes as
+// time.Time.
+func export(v any) any {
+	switch v := v.(type) {
+	case datetime:
+		return v.t
+	case []any:
+		l := make([]any, len(v))
+		for i, e := range v {
+			l[i] = export(e)
+		}
+		return l
+	}
+	return v
+}
+
+// A datetime is a value of a temporal type. The time is represented
+// like literals: DATEs at midnight UTC, TIMEs on January 1 of year 0,
+// and values without a time zone in time.UTC.
+type datetime struct {
+	t   time.Time
+	typ ast.DataType // DateType, TimeType, TimeTZType, TimestampType or TimestampTZType.
+}
+
+// newDatetime returns the datetime of a graph property or bind
+// variable. Unless the type is temporal, it is a TIMESTAMP, with a
+// time zone if the time is not in time.UTC.
+func newDatetime(t time.Time, dt ast.DataType) datetime {
+	switch dt {
+	case ast.DateType, ast.TimeType, ast.TimeTZType, ast.TimestampType, ast.TimestampTZType:
+	default:
+		dt = ast.TimestampType
+		if t.Location() != time.UTC {
+			dt = ast.TimestampTZType
+		}
+	}
+	return castTime(t, dt)
+}
+
+// literal returns the datetime of a temporal literal.
+func literal(kind ast.LitKind, t time.Time) datetime {
+	zoned := t.Location() != time.UTC
+	switch {
+	case kind == ast.DateKind:
+		return datetime{t, ast.DateType}
+	case kind == ast.TimeKind && zoned:
+		return datetime{t, ast.TimeTZType}
+	case kind == ast.TimeKind:
+		return datetime{t, ast.TimeType}
+	case zoned:
+		return datetime{t, ast.TimestampTZType}
+	default:
+		return datetime{t, ast.TimestampType}
+	}
+}
+
+// isTime returns true for times of day, with or without a time zone.
+func (d datetime) isTime() bool {
+	return d.typ == ast.TimeType || d.typ == ast.TimeTZType
+}
+
+// isZoned returns true for types with a time zone.
+func (d datetime) isZoned() bool {
+	return d.typ == ast.TimeTZType || d.typ == ast.TimestampTZType
+}
+
+// compare returns -1, 0 or 1 if a is less than, equal to or greater
+// than b, and false if they cannot be compared. Integers and floats
+// are compared by value, and vertices and edges by ID.
+func compare(a, b any) (int, bool) {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return cmpOrdered(a, b), true
+		case float64:
+			return cmpOrdered(float64(a), b), true
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return cmpOrdered(a, float64(b)), true
+		case float64:
+			return cmpOrdered(a, b), true
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b), true
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0, true
+			case b:
+				return -1, true
+			default:
+				return 1, true
+			}
+		}
+	case datetime:
+		// Dates and timestamps are comparable, but not with times.
+		if b, ok := b.(datetime); ok && a.isTime() == b.isTime() {
+			switch {
+			case a.t.Before(b.t):
+				return -1, true
+			case a.t.After(b.t):
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	case *graph.Vertex:
+		if b, ok := b.(*graph.Vertex); ok {
+			return cmpOrdered(a.ID, b.ID), true
+		}
+	case *graph.Edge:
+		if b, ok := b.(*graph.Edge); ok {
+			return cmpOrdered(a.ID, b.ID), true
+		}
+	}
+	return 0, false
+}
+
+func cmpOrdered[T int64 | float64 | graph.ID](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareOrder compares values like compare, with NULL greater than
+// any value.
+func compareOrder(a, b any) (int, bool) {
+	switch {
+	case a == nil && b == nil:
+		return 0, true
+	case a == nil:
+		return 1, true
+	case b == nil:
+		return -1, true
+	}
+	return compare(a, b)
+}
+
+// valueKey returns a string identifying a value, for DISTINCT and
+// GROUP BY. Equal numbers and instants have the same key.
+func valueKey(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case int64:
+		return fmt.Sprintf("n:%d", v)
+	case float64:
+		if v >= math.MinInt64 && v < math.MaxInt64 && v == math.Trunc(v) {
+			return fmt.Sprintf("n:%d", int64(v))
+		}
+		return fmt.Sprintf("n:%v", v)
+	case string:
+		return fmt.Sprintf("s:%q", v)
+	case bool:
+		return fmt.Sprintf("b:%t", v)
+	case time.Time:
+		return "t:" + v.UTC().Format(time.RFC3339Nano)
+	case datetime:
+		return "t:" + v.t.UTC().Format(time.RFC3339Nano)
+	case ast.Interval:
+		return fmt.Sprintf("i:%d:%d", v.N, v.Field)
+	case *graph.Vertex:
+		return fmt.Sprintf("v:%d", v.ID)
+	case *graph.Edge:
+		return fmt.Sprintf("e:%d", v.ID)
+	case []any:
+		return "[" + listKey(v) + "]"
+	default:
+		return fmt.Sprintf("%T:%v", v, v)
+	}
+}
+
+// arith applies an arithmetic operator to numbers. Integers are
+// promoted to floats if the other operand is a float. It returns false
+// if the operands are not numbers.
+func arith(op ast.Op, a, b any) (any, bool, error) {
+	if a, ok := a.(int64); ok {
+		if b, ok := b.(int64); ok {
+			return arithInt(op, a, b)
+		}
+	}
+
+	fa, ok := toFloat(a)
+	if !ok {
+		return nil, false, nil
+	}
+	fb, ok := toFloat(b)
+	if !ok {
+		return nil, false, nil
+	}
+	switch op {
+	case ast.AddOp:
+		return fa + fb, true, nil
+	case ast.SubOp:
+		return fa - fb, true, nil
+	case ast.MulOp:
+		return fa * fb, true, nil
+	case ast.DivOp:
+		return fa / fb, true, nil
+	default:
+		return math.Mod(fa, fb), true, nil
+	}
+}
+
+// errOverflow is returned when the result of integer arithmetic does
+// not fit in an int64.
+var errOverflow = errors.New("integer overflow")
+
+func arithInt(op ast.Op, a, b int64) (any, bool, error) {
+	switch op {
+	case ast.AddOp:
+		if b > 0 && a > math.MaxInt64-b || b < 0 && a < math.MinInt64-b {
+			return nil, true, errOverflow
+		}
+		return a + b, true, nil
+	case ast.SubOp:
+		if b < 0 && a > math.MaxInt64+b || b > 0 && a < math.MinInt64+b {
+			return nil, true, errOverflow
+		}
+		return a - b, true, nil
+	case ast.MulOp:
+		c := a * b
+		if a != 0 && (c/a != b || a == -1 && b == math.MinInt64 || b == -1 && a == math.MinInt64) {
+			return nil, true, errOverflow
+		}
+		return c, true, nil
+	}
+
+	if b == 0 {
+		return nil, true, fmt.Errorf("division by zero")
+	}
+	if op == ast.DivOp {
+		if a == math.MinInt64 && b == -1 {
+			return nil, true, errOverflow
+		}
+		return a / b, true, nil
+	}
+	return a % b, true, nil
+}
+
+// negInt returns -i, or errOverflow.
+func negInt(i int64) (int64, error) {
+	if i == math.MinInt64 {
+		return 0, errOverflow
+	}
+	return -i, nil
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// typeName returns the PGQL name of the type of a value, for errors.
+func typeName(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return "LONG"
+	case float64:
+		return "DOUBLE"
+	case string:
+		return "STRING"
+	case bool:
+		return "BOOLEAN"
+	case datetime:
+		return v.typ.String()
+	case ast.Interval:
+		return "INTERVAL"
+	case *graph.Vertex:
+		return "VERTEX"
+	case *graph.Edge:
+		return "EDGE"
+	case []any:
+		return "LIST"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
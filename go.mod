@@ -2,7 +2,6 @@ module github.com/itergia/pgql-go
 
 go 1.18
 
-require (
-	github.com/google/go-cmp v0.5.9 // indirect
-	golang.org/x/tools v0.3.0 // indirect
-)
+require github.com/google/go-cmp v0.5.9
+
+require golang.org/x/tools v0.3.0 // indirect
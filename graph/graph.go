@@ -0,0 +1,356 @@
+// Package graph is an in-memory property graph, to run PGQL queries
+// against in tests.
+//
+// Vertices and edges have any number of labels, and properties holding
+// the values of PGQL literals, as returned by ast.BasicLit.Value.
+// Edges are directed. Every element has an ID which is never reused.
+// Elements are indexed by label, and by property value.
+//
+// A Graph can be given the schema of a graph declared by CREATE
+// PROPERTY GRAPH, in which case labels must be declared for the kind
+// of element, and properties with a CAST type are converted to it.
+//
+// A Graph is not safe for concurrent use, except for reading.
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/itergia/pgql-go/catalog"
+)
+
+// An ID identifies a vertex or an edge. IDs are unique across both
+// kinds, and increase with the insertion order.
+type ID int64
+
+// A Vertex is a vertex of a Graph. It must not be modified directly.
+type Vertex struct {
+	ID     ID
+	Labels []string // Sorted.
+	Props  map[string]any
+}
+
+// HasLabel returns true if the vertex has the label.
+func (v *Vertex) HasLabel(label string) bool { return hasLabel(v.Labels, label) }
+
+// An Edge is a directed edge of a Graph. It must not be modified
+// directly.
+type Edge struct {
+	ID     ID
+	Src    ID
+	Dst    ID
+	Labels []string // Sorted.
+	Props  map[string]any
+}
+
+// HasLabel returns true if the edge has the label.
+func (e *Edge) HasLabel(label string) bool { return hasLabel(e.Labels, label) }
+
+func hasLabel(labels []string, label string) bool {
+	i := sort.SearchStrings(labels, label)
+	return i < len(labels) && labels[i] == label
+}
+
+// A Graph is an in-memory property graph.
+type Graph struct {
+	schema *catalog.Graph
+	lastID ID
+
+	vertices map[ID]*Vertex
+	edges    map[ID]*Edge
+	out, in  map[ID][]ID // Edges by source and destination vertex.
+
+	labels map[string]idSet         // Elements by label.
+	props  map[string]map[any]idSet // Elements by property value.
+}
+
+// An idSet is a set of element IDs.
+type idSet map[ID]struct{}
+
+// New returns an empty graph. The schema may be nil.
+func New(schema *catalog.Graph) *Graph {
+	return &Graph{
+		schema:   schema,
+		vertices: map[ID]*Vertex{},
+		edges:    map[ID]*Edge{},
+		out:      map[ID][]ID{},
+		in:       map[ID][]ID{},
+		labels:   map[string]idSet{},
+		props:    map[string]map[any]idSet{},
+	}
+}
+
+// Schema returns the schema given to New, or nil.
+func (g *Graph) Schema() *catalog.Graph { return g.schema }
+
+// NumVertices returns the number of vertices.
+func (g *Graph) NumVertices() int { return len(g.vertices) }
+
+// NumEdges returns the number of edges.
+func (g *Graph) NumEdges() int { return len(g.edges) }
+
+// Vertex returns the vertex with the ID, or nil.
+func (g *Graph) Vertex(id ID) *Vertex { return g.vertices[id] }
+
+// Edge returns the edge with the ID, or nil.
+func (g *Graph) Edge(id ID) *Edge { return g.edges[id] }
+
+// Vertices returns all vertices, sorted by ID.
+func (g *Graph) Vertices() []*Vertex {
+	vs := make([]*Vertex, 0, len(g.vertices))
+	for _, v := range g.vertices {
+		vs = append(vs, v)
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].ID < vs[j].ID })
+	return vs
+}
+
+// Edges returns all edges, sorted by ID.
+func (g *Graph) Edges() []*Edge {
+	es := make([]*Edge, 0, len(g.edges))
+	for _, e := range g.edges {
+		es = append(es, e)
+	}
+	sort.Slice(es, func(i, j int) bool { return es[i].ID < es[j].ID })
+	return es
+}
+
+// OutEdges returns the edges leaving the vertex, in insertion order.
+func (g *Graph) OutEdges(id ID) []*Edge { return g.edgeList(g.out[id]) }
+
+// InEdges returns the edges entering the vertex, in insertion order.
+func (g *Graph) InEdges(id ID) []*Edge { return g.edgeList(g.in[id]) }
+
+func (g *Graph) edgeList(ids []ID) []*Edge {
+	es := make([]*Edge, len(ids))
+	for i, id := range ids {
+		es[i] = g.edges[id]
+	}
+	return es
+}
+
+// VerticesByLabel returns the vertices with the label, sorted by ID.
+func (g *Graph) VerticesByLabel(label string) []*Vertex {
+	return g.vertexSet(g.labels[label])
+}
+
+// EdgesByLabel returns the edges with the label, sorted by ID.
+func (g *Graph) EdgesByLabel(label string) []*Edge {
+	return g.edgeSet(g.labels[label])
+}
+
+// VerticesByProperty returns the vertices whose property equals the
+// value, sorted by ID. Numbers are equal if they have the same value,
+// and times if they are the same instant.
+func (g *Graph) VerticesByProperty(name string, value any) []*Vertex {
+	v, err := normalize(value)
+	if err != nil {
+		return nil
+	}
+	return g.vertexSet(g.props[name][indexKey(v)])
+}
+
+// EdgesByProperty returns the edges whose property equals the value,
+// sorted by ID, like VerticesByProperty.
+func (g *Graph) EdgesByProperty(name string, value any) []*Edge {
+	v, err := normalize(value)
+	if err != nil {
+		return nil
+	}
+	return g.edgeSet(g.props[name][indexKey(v)])
+}
+
+// vertexSet returns the vertices of the set, sorted by ID.
+func (g *Graph) vertexSet(ids idSet) []*Vertex {
+	var vs []*Vertex
+	for _, id := range sortedIDs(ids) {
+		if v := g.vertices[id]; v != nil {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// edgeSet returns the edges of the set, sorted by ID.
+func (g *Graph) edgeSet(ids idSet) []*Edge {
+	var es []*Edge
+	for _, id := range sortedIDs(ids) {
+		if e := g.edges[id]; e != nil {
+			es = append(es, e)
+		}
+	}
+	return es
+}
+
+func sortedIDs(ids idSet) []ID {
+	l := make([]ID, 0, len(ids))
+	for id := range ids {
+		l = append(l, id)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+	return l
+}
+
+// AddVertex adds a vertex. The labels and properties are copied.
+func (g *Graph) AddVertex(labels []string, props map[string]any) (*Vertex, error) {
+	ls, ps, err := g.element(catalog.VertexTable, labels, props)
+	if err != nil {
+		return nil, err
+	}
+
+	g.lastID++
+	v := &Vertex{ID: g.lastID, Labels: ls, Props: ps}
+	g.vertices[v.ID] = v
+	g.index(v.ID, v.Labels, v.Props)
+	return v, nil
+}
+
+// AddEdge adds an edge between existing vertices. The labels and
+// properties are copied.
+func (g *Graph) AddEdge(src, dst ID, labels []string, props map[string]any) (*Edge, error) {
+	for _, id := range []ID{src, dst} {
+		if g.vertices[id] == nil {
+			return nil, fmt.Errorf("vertex %d does not exist", id)
+		}
+	}
+	ls, ps, err := g.element(catalog.EdgeTable, labels, props)
+	if err != nil {
+		return nil, err
+	}
+
+	g.lastID++
+	e := &Edge{ID: g.lastID, Src: src, Dst: dst, Labels: ls, Props: ps}
+	g.edges[e.ID] = e
+	g.out[src] = append(g.out[src], e.ID)
+	g.in[dst] = append(g.in[dst], e.ID)
+	g.index(e.ID, e.Labels, e.Props)
+	return e, nil
+}
+
+// SetProperty sets a property of a vertex or edge. A nil value removes
+// the property.
+func (g *Graph) SetProperty(id ID, name string, value any) error {
+	var labels []string
+	var props map[string]any
+	var kind catalog.TableKind
+	switch {
+	case g.vertices[id] != nil:
+		labels, props, kind = g.vertices[id].Labels, g.vertices[id].Props, catalog.VertexTable
+	case g.edges[id] != nil:
+		labels, props, kind = g.edges[id].Labels, g.edges[id].Props, catalog.EdgeTable
+	default:
+		return fmt.Errorf("element %d does not exist", id)
+	}
+
+	if value != nil {
+		var err error
+		if value, err = g.property(kind, labels, name, value); err != nil {
+			return err
+		}
+	}
+
+	if old, ok := props[name]; ok {
+		g.unindexProp(id, name, old)
+		delete(props, name)
+	}
+	if value != nil {
+		props[name] = value
+		g.indexProp(id, name, value)
+	}
+	return nil
+}
+
+// RemoveVertex removes a vertex, and its edges.
+func (g *Graph) RemoveVertex(id ID) error {
+	v := g.vertices[id]
+	if v == nil {
+		return fmt.Errorf("vertex %d does not exist", id)
+	}
+
+	for _, eids := range [][]ID{g.out[id], g.in[id]} {
+		for _, eid := range append([]ID(nil), eids...) {
+			if g.edges[eid] != nil {
+				g.RemoveEdge(eid)
+			}
+		}
+	}
+	delete(g.out, id)
+	delete(g.in, id)
+	g.unindex(id, v.Labels, v.Props)
+	delete(g.vertices, id)
+	return nil
+}
+
+// RemoveEdge removes an edge.
+func (g *Graph) RemoveEdge(id ID) error {
+	e := g.edges[id]
+	if e == nil {
+		return fmt.Errorf("edge %d does not exist", id)
+	}
+
+	g.out[e.Src] = removeID(g.out[e.Src], id)
+	g.in[e.Dst] = removeID(g.in[e.Dst], id)
+	g.unindex(id, e.Labels, e.Props)
+	delete(g.edges, id)
+	return nil
+}
+
+func removeID(ids []ID, id ID) []ID {
+	for i, x := range ids {
+		if x == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+func (g *Graph) index(id ID, labels []string, props map[string]any) {
+	for _, l := range labels {
+		s := g.labels[l]
+		if s == nil {
+			s = idSet{}
+			g.labels[l] = s
+		}
+		s[id] = struct{}{}
+	}
+	for name, v := range props {
+		g.indexProp(id, name, v)
+	}
+}
+
+func (g *Graph) unindex(id ID, labels []string, props map[string]any) {
+	for _, l := range labels {
+		delete(g.labels[l], id)
+	}
+	for name, v := range props {
+		g.unindexProp(id, name, v)
+	}
+}
+
+// indexProp adds an element to the index of a property. The value has
+// been normalized by property.
+func (g *Graph) indexProp(id ID, name string, value any) {
+	k := indexKey(value)
+	m := g.props[name]
+	if m == nil {
+		m = map[any]idSet{}
+		g.props[name] = m
+	}
+	s := m[k]
+	if s == nil {
+		s = idSet{}
+		m[k] = s
+	}
+	s[id] = struct{}{}
+}
+
+func (g *Graph) unindexProp(id ID, name string, value any) {
+	k := indexKey(value)
+	if s := g.props[name][k]; s != nil {
+		delete(s, id)
+		if len(s) == 0 {
+			delete(g.props[name], k)
+		}
+	}
+}
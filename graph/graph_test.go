@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/catalog"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func vertexIDs(vs []*Vertex) []ID {
+	var ids []ID
+	for _, v := range vs {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func edgeIDs(es []*Edge) []ID {
+	var ids []ID
+	for _, e := range es {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestGraph(t *testing.T) {
+	g := New(nil)
+	a, err := g.AddVertex([]string{"Person", "Employee", "Person"}, map[string]any{"name": "a", "age": 30})
+	if err != nil {
+		t.Fatalf("AddVertex failed: %v", err)
+	}
+	b, _ := g.AddVertex([]string{"Person"}, map[string]any{"name": "b", "age": 30.0})
+	c, _ := g.AddVertex([]string{"Company"}, nil)
+	ab, err := g.AddEdge(a.ID, b.ID, []string{"knows"}, map[string]any{"since": time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("AddEdge failed: %v", err)
+	}
+	ac, _ := g.AddEdge(a.ID, c.ID, []string{"worksAt"}, nil)
+
+	if diff := cmp.Diff([]string{"Employee", "Person"}, a.Labels); diff != "" {
+		t.Errorf("Labels: +got, -want:\n%s", diff)
+	}
+	if got := a.Props["age"]; got != int64(30) {
+		t.Errorf("Props[age]: got %#v, want int64(30)", got)
+	}
+	if diff := cmp.Diff([]ID{a.ID, b.ID}, vertexIDs(g.VerticesByLabel("Person"))); diff != "" {
+		t.Errorf("VerticesByLabel: +got, -want:\n%s", diff)
+	}
+	if diff := cmp.Diff([]ID{a.ID, b.ID}, vertexIDs(g.VerticesByProperty("age", 30))); diff != "" {
+		t.Errorf("VerticesByProperty: +got, -want:\n%s", diff)
+	}
+	since := time.Date(2000, 1, 2, 1, 0, 0, 0, time.FixedZone("", 3600))
+	if diff := cmp.Diff([]ID{ab.ID}, edgeIDs(g.EdgesByProperty("since", since))); diff != "" {
+		t.Errorf("EdgesByProperty: +got, -want:\n%s", diff)
+	}
+	if diff := cmp.Diff([]ID{ab.ID, ac.ID}, edgeIDs(g.OutEdges(a.ID))); diff != "" {
+		t.Errorf("OutEdges: +got, -want:\n%s", diff)
+	}
+
+	if err := g.SetProperty(b.ID, "age", 31); err != nil {
+		t.Fatalf("SetProperty failed: %v", err)
+	}
+	if diff := cmp.Diff([]ID{a.ID}, vertexIDs(g.VerticesByProperty("age", 30))); diff != "" {
+		t.Errorf("VerticesByProperty after SetProperty: +got, -want:\n%s", diff)
+	}
+
+	if err := g.RemoveVertex(a.ID); err != nil {
+		t.Fatalf("RemoveVertex failed: %v", err)
+	}
+	if got, want := g.NumEdges(), 0; got != want {
+		t.Errorf("NumEdges: got %d, want %d", got, want)
+	}
+	if got := g.InEdges(b.ID); len(got) != 0 {
+		t.Errorf("InEdges: got %v, want none", got)
+	}
+	if got := g.VerticesByLabel("Employee"); len(got) != 0 {
+		t.Errorf("VerticesByLabel(Employee): got %v, want none", got)
+	}
+
+	d, _ := g.AddVertex(nil, nil)
+	if d.ID <= ac.ID {
+		t.Errorf("AddVertex: got ID %d, want more than %d", d.ID, ac.ID)
+	}
+	if _, err := g.AddEdge(a.ID, b.ID, nil, nil); err == nil {
+		t.Errorf("AddEdge from a removed vertex succeeded")
+	}
+}
+
+func TestGraphSchema(t *testing.T) {
+	cat := catalog.New("")
+	stmt, err := parser.ParseStatement("CREATE PROPERTY GRAPH g VERTEX TABLES (person PROPERTIES (name, CAST(age AS INT) AS age, CAST(score AS DOUBLE) AS score, CAST(born AS DATE) AS born), city) EDGE TABLES (knows SOURCE person DESTINATION person NO PROPERTIES)")
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+	if err := cat.Exec(nil, stmt); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	g := New(cat.LookupGraph("", "g"))
+
+	v, err := g.AddVertex([]string{"person"}, map[string]any{"name": "a", "age": 3, "score": 2})
+	if err != nil {
+		t.Fatalf("AddVertex failed: %v", err)
+	}
+	if got := v.Props["score"]; got != 2.0 {
+		t.Errorf("Props[score]: got %#v, want 2.0", got)
+	}
+	day := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	if err := g.SetProperty(v.ID, "born", time.Date(2000, 1, 2, 10, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("SetProperty(born) failed: %v", err)
+	}
+	if got := v.Props["born"]; got != day {
+		t.Errorf("Props[born]: got %v, want %v", got, day)
+	}
+	if diff := cmp.Diff([]ID{v.ID}, vertexIDs(g.VerticesByProperty("born", day))); diff != "" {
+		t.Errorf("VerticesByProperty(born): +got, -want:\n%s", diff)
+	}
+	if _, err := g.AddVertex([]string{"city"}, map[string]any{"anything": true}); err != nil {
+		t.Errorf("AddVertex(city) failed: %v", err)
+	}
+	if got := g.PropertyType(catalog.VertexTable, v.Labels, "born"); got != ast.DateType {
+		t.Errorf("PropertyType(born): got %v, want DATE", got)
+	}
+	if got := g.PropertyType(catalog.VertexTable, v.Labels, "name"); got != ast.UnknownDataType {
+		t.Errorf("PropertyType(name): got %v, want unknown", got)
+	}
+
+	tsts := []struct {
+		Name string
+		Err  error
+		Want string
+	}{
+		{"edgeLabel", errOf(g.AddVertex([]string{"knows"}, nil)), "vertex label knows does not exist in graph g"},
+		{"undeclared", errOf(g.AddVertex([]string{"person"}, map[string]any{"x": 1})), "property x is not defined by labels [person]"},
+		{"type", errOf(g.AddVertex([]string{"person"}, map[string]any{"age": "x"})), "property age: cannot use string as INT"},
+		{"overflow", g.SetProperty(v.ID, "age", int64(1)<<40), "property age: 1099511627776 overflows INT"},
+		{"unsupported", g.SetProperty(v.ID, "name", []int{1}), "property name: unsupported value type []int"},
+	}
+	for _, tst := range tsts {
+		if tst.Err == nil {
+			t.Errorf("%s: succeeded, want %q", tst.Name, tst.Want)
+		} else if got := tst.Err.Error(); got != tst.Want {
+			t.Errorf("%s: got %q, want %q", tst.Name, got, tst.Want)
+		}
+	}
+}
+
+func errOf(_ *Vertex, err error) error { return err }
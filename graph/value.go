@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/catalog"
+)
+
+// element checks the labels and properties of a new element, and
+// returns copies of them.
+func (g *Graph) element(kind catalog.TableKind, labels []string, props map[string]any) ([]string, map[string]any, error) {
+	var ls []string
+	seen := map[string]bool{}
+	for _, l := range labels {
+		if seen[l] {
+			continue
+		}
+		seen[l] = true
+		if g.schema != nil {
+			ts := g.schema.LabelTables(l)
+			if len(ts) == 0 || ts[0].Kind != kind {
+				return nil, nil, fmt.Errorf("%s label %s does not exist in graph %s", kind, l, g.schema)
+			}
+		}
+		ls = append(ls, l)
+	}
+	sort.Strings(ls)
+
+	ps := make(map[string]any, len(props))
+	for name, v := range props {
+		if v == nil {
+			continue
+		}
+		v, err := g.property(kind, ls, name, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		ps[name] = v
+	}
+	return ls, ps, nil
+}
+
+// property returns the normalized value of a property of an element
+// with the labels.
+func (g *Graph) property(kind catalog.TableKind, labels []string, name string, value any) (any, error) {
+	v, err := normalize(value)
+	if err != nil {
+		return nil, fmt.Errorf("property %s: %w", name, err)
+	}
+	if g.schema == nil || len(labels) == 0 {
+		return v, nil
+	}
+
+	// The property must be declared by a table of some label.
+	p, ok := g.declaredProperty(kind, labels, name)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("property %s is not defined by labels %v", name, labels)
+	case p == nil:
+		return v, nil
+	default:
+		return convert(v, p.Type, name)
+	}
+}
+
+// PropertyType returns the type of a property of an element with the
+// labels, as declared by a CAST in the schema, or UnknownDataType.
+func (g *Graph) PropertyType(kind catalog.TableKind, labels []string, name string) ast.DataType {
+	if g.schema == nil {
+		return ast.UnknownDataType
+	}
+	if p, _ := g.declaredProperty(kind, labels, name); p != nil {
+		return p.Type
+	}
+	return ast.UnknownDataType
+}
+
+// declaredProperty returns the first declaration of a property by a
+// table of some label. The property is nil, and true is returned, if
+// the table has PROPERTIES ARE ALL COLUMNS.
+func (g *Graph) declaredProperty(kind catalog.TableKind, labels []string, name string) (*catalog.Property, bool) {
+	for _, l := range labels {
+		for _, t := range g.schema.LabelTables(l) {
+			if t.Kind != kind {
+				continue
+			}
+			if t.AllColumns {
+				return nil, true
+			}
+			if p := t.Property(name); p != nil {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// normalize returns the value with Go integer and float types widened
+// to int64 and float64. Other values must be of the types returned by
+// ast.BasicLit.Value.
+func normalize(v any) (any, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case float32:
+		return float64(v), nil
+	case int64, float64, bool, string, time.Time, ast.Interval, *big.Int, *big.Rat:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// convert converts a normalized value to the type of a CAST.
+func convert(v any, dt ast.DataType, name string) (any, error) {
+	switch dt {
+	case ast.UnknownDataType:
+		return v, nil
+
+	case ast.StringType:
+		if _, ok := v.(string); ok {
+			return v, nil
+		}
+
+	case ast.BooleanType:
+		if _, ok := v.(bool); ok {
+			return v, nil
+		}
+
+	case ast.IntegerType, ast.IntType, ast.LongType:
+		if i, ok := v.(int64); ok {
+			if dt != ast.LongType && (i < math.MinInt32 || i > math.MaxInt32) {
+				return nil, fmt.Errorf("property %s: %d overflows %s", name, i, dt)
+			}
+			return v, nil
+		}
+
+	case ast.FloatType, ast.DoubleType:
+		switch x := v.(type) {
+		case float64:
+			return x, nil
+		case int64:
+			return float64(x), nil
+		}
+
+	case ast.DateType:
+		if t, ok := v.(time.Time); ok {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+
+	case ast.TimeType, ast.TimeTZType:
+		if t, ok := v.(time.Time); ok {
+			return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()), nil
+		}
+
+	case ast.TimestampType, ast.TimestampTZType:
+		if _, ok := v.(time.Time); ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("property %s: cannot use %T as %s", name, v, dt)
+}
+
+// indexKey returns the key of a normalized value in the property
+// index. Integral floats are integers, and times are in UTC.
+func indexKey(v any) any {
+	switch x := v.(type) {
+	case float64:
+		if x >= math.MinInt64 && x < math.MaxInt64 && x == math.Trunc(x) {
+			return int64(x)
+		}
+	case time.Time:
+		return x.UTC()
+	case *big.Int:
+		return "int:" + x.String()
+	case *big.Rat:
+		return "rat:" + x.String()
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"io"
+	"unicode/utf8"
+
+	"github.com/itergia/pgql-go/parser/internal/parser"
+)
+
+// A Decoder parses statements one at a time, as they are read.
+type Decoder = parser.Decoder
+
+// NewDecoder returns a Decoder reading UTF-8 statements from r. Next
+// returns each statement as soon as its semicolon has been read, and
+// r is not read any further, so the rest of r can be used by the
+// caller. If r is an io.RuneReader, like a *bufio.Reader, it is used
+// directly. Otherwise, r is read one byte at a time.
+func NewDecoder(r io.Reader) *Decoder {
+	if rr, ok := r.(parser.RuneReader); ok {
+		return parser.NewDecoder(rr)
+	}
+
+	return parser.NewDecoder(&byteRuneReader{r: r})
+}
+
+// byteRuneReader decodes runes from a reader without reading ahead.
+type byteRuneReader struct {
+	r   io.Reader
+	buf [utf8.UTFMax]byte
+}
+
+func (br *byteRuneReader) ReadRune() (rune, int, error) {
+	n := 0
+	for n == 0 || n < len(br.buf) && !utf8.FullRune(br.buf[:n]) {
+		if _, err := io.ReadFull(br.r, br.buf[n:n+1]); err != nil {
+			if n > 0 && err == io.EOF {
+				// A truncated encoding, returned as utf8.RuneError.
+				break
+			}
+			return 0, 0, err
+		}
+		n++
+	}
+
+	r, _ := utf8.DecodeRune(br.buf[:n])
+	return r, n, nil
+}
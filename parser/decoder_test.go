@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecoder(t *testing.T) {
+	tsts := []struct {
+		Name string
+		R    func(string) io.Reader
+	}{
+		{"runeReader", func(s string) io.Reader { return strings.NewReader(s) }},
+		{"reader", func(s string) io.Reader { return struct{ io.Reader }{strings.NewReader(s)} }},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			r := tst.R("SELECT a FROM MATCH (a);\nSELECT b c;\n/* é */ DROP PROPERTY GRAPH g;rest")
+			d := NewDecoder(r)
+
+			var got []string
+			for i := 0; i < 3; i++ {
+				stmt, err := d.Next()
+				var errs ErrorList
+				if errors.As(err, &errs) {
+					got = append(got, fmt.Sprintf("%T %s", stmt, errs))
+				} else if err != nil {
+					t.Fatalf("Next failed: %v", err)
+				} else {
+					got = append(got, fmt.Sprintf("%T %s", stmt, d.File().Position(stmt.Pos())))
+				}
+			}
+
+			want := []string{
+				"*ast.SelectStmt 1:1",
+				"<nil> at 2:10: syntax error: unexpected UNQUOTED_IDENTIFIER, expecting FROM",
+				"*ast.DropStmt 3:9",
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Next: +got, -want:\n%s", diff)
+			}
+
+			rest, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if got, want := string(rest), "rest"; got != want {
+				t.Errorf("ReadAll: got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDecoderEOF(t *testing.T) {
+	d := NewDecoder(strings.NewReader("DROP PROPERTY GRAPH g; /* trailing */\n"))
+
+	if _, err := d.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if stmt, err := d.Next(); err != io.EOF {
+			t.Errorf("Next: got %v, %v, want io.EOF", stmt, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"io"
+
+	"github.com/itergia/pgql-go/ast"
+)
+
+// A Decoder parses statements one at a time.
+type Decoder struct {
+	pc parserContext
+}
+
+// NewDecoder returns a Decoder reading from r. It reads no further
+// than the semicolon terminating each statement.
+func NewDecoder(r RuneReader) *Decoder {
+	return &Decoder{pc: parserContext{scanner: newScanner(r)}}
+}
+
+// Next parses the next statement, and returns io.EOF if there are no
+// more statements. On syntax errors, it returns the partial statement,
+// if any, and an ErrorList. Next can then be called again to parse
+// the following statement.
+func (d *Decoder) Next() (ast.Stmt, error) {
+	pc := &d.pc
+	if pc.atEOF || pc.peek() == eof {
+		pc.atEOF = true
+		if errs := pc.takeErrors(); len(errs) > 0 {
+			return nil, errs
+		}
+		return nil, io.EOF
+	}
+
+	pc.parseStmt()
+
+	var stmt ast.Stmt
+	if len(pc.stmts) > 0 {
+		stmt = pc.stmts[0]
+	}
+	// Only the current statement is kept.
+	pc.stmts = nil
+	pc.anchors = nil
+	pc.commented = nil
+
+	if errs := pc.takeErrors(); len(errs) > 0 {
+		return stmt, errs
+	}
+	return stmt, nil
+}
+
+// File maps the positions of statements to lines and columns. The
+// positions are relative to the start of the reader.
+func (d *Decoder) File() *ast.File {
+	return d.pc.file
+}
+
+// takeErrors returns the parser and scanner errors, sorted, and
+// clears them.
+func (pc *parserContext) takeErrors() ErrorList {
+	errs := append(pc.errs, pc.scanner.errs...)
+	pc.errs = nil
+	pc.scanner.errs = nil
+	errs.sort()
+	return errs
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// An Error is a syntax error at a position in the input.
+type Error struct {
+	// Pos is the start of the offending token.
+	Pos Position
+
+	// Msg is the full message, without the position.
+	Msg string
+
+	// Unexpected is the name of the offending token, like "FROM",
+	// "';'" or "$end", if known.
+	Unexpected string
+
+	// Expected lists the names of the tokens that would have been
+	// valid instead. It is empty if there are too many to list.
+	Expected []string
+}
+
+// Error returns the message prefixed with the position.
+func (e *Error) Error() string {
+	return fmt.Sprintf("at %s: %s", e.Pos, e.Msg)
+}
+
+// newSyntaxError returns an Error for a message from goyacc, which
+// looks like "syntax error: unexpected X, expecting Y or Z".
+// Messages in other formats are used as-is.
+func newSyntaxError(pos Position, msg string) *Error {
+	e := &Error{Pos: pos, Msg: msg}
+
+	s := strings.TrimPrefix(msg, "syntax error: unexpected ")
+	if s == msg {
+		return e
+	}
+	e.Unexpected, s, _ = strings.Cut(s, ", expecting ")
+	if s != "" {
+		e.Expected = strings.Split(s, " or ")
+	}
+	return e
+}
+
+// ErrorList is a list of Errors, sorted by position. It is the error
+// returned by Parse.
+type ErrorList []*Error
+
+// Error returns the first error, and the number of others.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// As sets target to the first error if it is an **Error, so that
+// errors.As can find an *Error. Go 1.20 would use Unwrap() []error,
+// but the module supports Go 1.18.
+func (l ErrorList) As(target any) bool {
+	p, ok := target.(**Error)
+	if !ok || len(l) == 0 {
+		return false
+	}
+	*p = l[0]
+	return true
+}
+
+// sort sorts the list by position, keeping the order of errors at
+// the same position.
+func (l ErrorList) sort() {
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Pos.Offset < l[j].Pos.Offset })
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"github.com/itergia/pgql-go/ast"
+)
+
+// ParseExpr parses a single value expression, like the condition of a
+// WHERE clause.
+func ParseExpr(r RuneReader) (ast.Expr, error) {
+	pc := parserContext{scanner: newScanner(r)}
+	if err := pc.parseFragment(START_EXPR); err != nil {
+		return nil, err
+	}
+	return pc.expr, nil
+}
+
+// ParsePathPattern parses a single path pattern, as found in a MATCH
+// clause.
+func ParsePathPattern(r RuneReader) (*ast.PathPattern, error) {
+	pc := parserContext{scanner: newScanner(r)}
+	if err := pc.parseFragment(START_PATH); err != nil {
+		return nil, err
+	}
+	return pc.path, nil
+}
+
+// ParseStatement parses a single statement. The terminating semicolon
+// is optional. On errors, the partial statement is returned, if any.
+func ParseStatement(r RuneReader) (ast.Stmt, error) {
+	pc := parserContext{scanner: newScanner(r)}
+	err := pc.parseFragment(START_STMT)
+	var stmt ast.Stmt
+	if len(pc.stmts) > 0 {
+		stmt = pc.stmts[0]
+	}
+	return stmt, err
+}
+
+// parseFragment parses the fragment selected by the start token, up
+// to EOF.
+func (pc *parserContext) parseFragment(start int) error {
+	pc.fragment = true
+	pc.start = start
+
+	var yy yyParserImpl
+	yy.Parse(pc)
+
+	if errs := pc.takeErrors(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
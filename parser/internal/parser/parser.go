@@ -4,25 +4,14 @@
 package parser
 
 import (
-	"errors"
-	"fmt"
-	"strings"
+	"sort"
 
 	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
 )
 
 // Position indicates a scanner position in the input stream.
-type Position struct {
-	// Offset is the zero-based UTF-8 codepoint offset from the start
-	// of the stream.
-	Offset int
-
-	// Line is the zero-based line number, as separated by NL.
-	Line int
-
-	// Column is the zero-based column number on the current line.
-	Column int
-}
+type Position = token.Position
 
 const (
 	// eof is the token value for EOF in goyacc.
@@ -44,61 +33,255 @@ func init() {
 
 type Statements struct {
 	Stmts []ast.Stmt
+
+	// Comments maps nodes to the comments preceding them. Comments
+	// after the last node are associated with the Statements.
+	Comments ast.CommentMap
+
+	// File maps the positions of nodes to lines and columns.
+	File *ast.File
+}
+
+// Pos returns the position of the first statement.
+func (ss *Statements) Pos() ast.Pos {
+	if len(ss.Stmts) == 0 {
+		return ast.NoPos
+	}
+	return ss.Stmts[0].Pos()
 }
 
+// End returns the position after the last statement, excluding the
+// semicolon.
+func (ss *Statements) End() ast.Pos {
+	if len(ss.Stmts) == 0 {
+		return ast.NoPos
+	}
+	return ss.Stmts[len(ss.Stmts)-1].End()
+}
+
+// Parse parses a list of statements, each terminated by a semicolon.
+// Statements are parsed one at a time, and a syntax error skips to
+// the next semicolon. Within a statement, the parser resynchronizes
+// at clause keywords like FROM and WHERE. On errors, the returned
+// Statements holds what could be parsed, including partial
+// statements, and the error is an ErrorList.
 func Parse(r RuneReader) (*Statements, error) {
 	pc := parserContext{scanner: newScanner(r)}
-	var yy yyParserImpl
-	if yy.Parse(&pc) != 0 || len(pc.errs) > 0 {
-		// For yy.lval.P to work for reporting the faulty token, there
-		// cannot be any error-recovery terms.
-		errs := append(pc.errs, pc.Errors()...)
-		if len(errs) == 0 {
-			errs = []error{errors.New("parsing failed without further information")}
+	for {
+		// An empty input is reported as an error by the parser.
+		if pc.peek() == eof && (len(pc.stmts) > 0 || len(pc.errs) > 0) {
+			break
+		}
+		pc.parseStmt()
+		if pc.atEOF {
+			break
 		}
-		return nil, parseError{errs: errs, pos: yy.lval.L.P}
 	}
 
-	return &Statements{Stmts: pc.stmts}, nil
+	stmts := &Statements{Stmts: pc.stmts, File: pc.file}
+	stmts.Comments = pc.commentMap(stmts)
+
+	if errs := pc.takeErrors(); len(errs) > 0 {
+		return stmts, errs
+	}
+	return stmts, nil
 }
 
 type parserContext struct {
 	*scanner
-	errs  []error
+	errs  ErrorList
 	stmts []ast.Stmt
+
+	// anchors maps the first token of nodes to the outermost node.
+	anchors map[*lexValue]ast.Node
+
+	// peeked is a token read by Parse, to be returned by Lex.
+	peeked *lexToken
+
+	// lastTok and last are the token last returned by Lex.
+	lastTok int
+	last    *lexValue
+
+	// sync is true after a syntax error. Lex then skips to the next
+	// clause keyword and returns SYNC before it.
+	sync bool
+
+	// endStmt is true once Lex has returned the semicolon ending the
+	// statement. Lex then returns EOF, so goyacc accepts the
+	// statement.
+	endStmt bool
+
+	// atEOF is true once the scanner has returned EOF.
+	atEOF bool
+
+	// nbindVars is the number of bind variables in the statement.
+	nbindVars int
+
+	// fragment is true when parsing a fragment, which ends at EOF
+	// rather than at a semicolon. start is the token Lex returns
+	// first, if not zero, to select the fragment.
+	fragment bool
+	start    int
+
+	// expr and path are the fragments parsed.
+	expr ast.Expr
+	path *ast.PathPattern
+}
+
+type lexToken struct {
+	tok int
+	l   *lexValue
+}
+
+// peek reads the first token of the next statement, and returns it.
+func (pc *parserContext) peek() int {
+	if pc.peeked == nil {
+		var lval yySymType
+		tok := pc.scanner.Lex(&lval)
+		pc.peeked = &lexToken{tok: tok, l: lval.L}
+	}
+	return pc.peeked.tok
 }
 
+// parseStmt parses the next statement, ending at a semicolon. On
+// errors, it skips to the next semicolon.
+func (pc *parserContext) parseStmt() {
+	pc.endStmt = false
+	pc.nbindVars = 0
+
+	var yy yyParserImpl
+	if yy.Parse(pc) != 0 {
+		// Skip the rest of the statement.
+		pc.sync = false
+		var lval yySymType
+		for !pc.endStmt && !pc.atEOF {
+			pc.Lex(&lval)
+		}
+	}
+}
+
+// Lex implements yyLexer. It returns EOF after a semicolon.
+func (pc *parserContext) Lex(lval *yySymType) int {
+	var tok int
+	switch {
+	case pc.start != 0:
+		tok = pc.start
+		pc.start = 0
+		lval.L = &lexValue{P: pc.pos, E: pc.pos}
+		return tok
+
+	case pc.sync:
+		// goyacc has discarded the offending token, and is waiting
+		// for SYNC. If the offending token is a synchronization
+		// point, it is returned again.
+		pc.sync = false
+		if isSyncToken(pc.lastTok) {
+			pc.peeked = &lexToken{tok: pc.lastTok, l: pc.last}
+			pc.endStmt = false
+		} else {
+			for pc.peeked == nil || !isSyncToken(pc.peeked.tok) {
+				pc.peeked = &lexToken{}
+				var lv yySymType
+				pc.peeked.tok = pc.scanner.Lex(&lv)
+				pc.peeked.l = lv.L
+			}
+		}
+		tok = SYNC
+		lval.L = &lexValue{P: pc.peeked.l.P, E: pc.peeked.l.P}
+
+	case pc.endStmt || pc.atEOF:
+		tok = eof
+		lval.L = &lexValue{P: pc.pos, E: pc.pos}
+
+	case pc.peeked != nil:
+		tok = pc.peeked.tok
+		lval.L = pc.peeked.l
+		pc.peeked = nil
+
+	default:
+		tok = pc.scanner.Lex(lval)
+	}
+	switch {
+	case tok == ';' && !pc.fragment:
+		pc.endStmt = true
+	case tok == eof && !pc.endStmt:
+		pc.atEOF = true
+	}
+
+	pc.lastTok, pc.last = tok, lval.L
+	return tok
+}
+
+// Error implements yyLexer. The error is positioned at the last
+// token.
 func (pc *parserContext) Error(e string) {
-	pc.errs = append(pc.errs, errors.New(e))
+	pc.sync = true
+	if pc.lastTok == bad {
+		// The scanner has already reported it.
+		return
+	}
+
+	pc.addError(e)
+}
+
+// addError records an error at the last token.
+func (pc *parserContext) addError(e string) {
+	var p Position
+	if pc.last != nil {
+		p = pc.last.P
+	}
+	pc.errs = append(pc.errs, newSyntaxError(p, e))
+}
+
+// isSyncToken returns true for the tokens where the error
+// productions resume parsing.
+func isSyncToken(tok int) bool {
+	switch tok {
+	case FROM, WHERE, GROUP, HAVING, ORDER, LIMIT, OFFSET, ';', eof:
+		return true
+	default:
+		return false
+	}
 }
 
 func (pc *parserContext) Stmts(ss []ast.Stmt) {
-	pc.stmts = ss
+	pc.stmts = append(pc.stmts, ss...)
 }
 
-type parseError struct {
-	errs []error
-	pos  Position
+func (pc *parserContext) Expr(e ast.Expr) {
+	pc.expr = e
 }
 
-func (e parseError) Error() string {
-	var sb strings.Builder
-	if e.pos != (Position{}) {
-		fmt.Fprintf(&sb, "at %d:%d: ", e.pos.Line+1, e.pos.Column+1)
+func (pc *parserContext) PathPattern(p *ast.PathPattern) {
+	pc.path = p
+}
+
+// commentMap associates each comment group with the node starting at
+// the token following it. If no node starts there, the next node is
+// used, or root if there is none. Returns nil if there are no
+// comments.
+func (pc *parserContext) commentMap(root ast.Node) ast.CommentMap {
+	if len(pc.commented) == 0 {
+		return nil
 	}
-	if len(e.errs) == 0 {
-		return "<nil>"
-	} else if len(e.errs) == 1 {
-		return sb.String() + e.errs[0].Error()
+
+	ls := make([]*lexValue, 0, len(pc.anchors))
+	for l := range pc.anchors {
+		ls = append(ls, l)
 	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].P.Offset < ls[j].P.Offset })
 
-	fmt.Fprintf(&sb, "%d errors", len(e.errs))
-	for _, err := range e.errs {
-		sb.WriteString("; ")
-		sb.WriteString(err.Error())
+	cmap := ast.CommentMap{}
+	for _, l := range pc.commented {
+		i := sort.Search(len(ls), func(i int) bool { return ls[i].P.Offset >= l.P.Offset })
+		n := root
+		if i < len(ls) {
+			n = pc.anchors[ls[i]]
+		}
+		cmap[n] = append(cmap[n], l.Comments...)
 	}
 
-	return sb.String()
+	return cmap
 }
 
 func indexOr[T any](s []T, i int, def T) T {
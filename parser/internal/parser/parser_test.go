@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/itergia/pgql-go/ast"
 )
@@ -145,7 +146,7 @@ func TestParse(t *testing.T) {
 		{
 			"createWithPropertyCast",
 			testToks(kw(CREATE), kw(PROPERTY), kw(GRAPH), id("mygraph"), kw(VERTEX), kw(TABLES), kw('('), id("atbl"), kw(PROPERTIES), kw('('), kw(CAST), kw('('), id("acol"), kw(AS), kw(STRING), kw(')'), kw(')'), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.CreateStmt{GraphName: &ast.QIdent{Names: []*ast.Ident{{Name: "mygraph"}}}, VertexTables: []*ast.VertexTableDecl{{TableName: &ast.QIdent{Names: []*ast.Ident{{Name: "atbl"}}}, Props: &ast.PropsClause{Exprs: []*ast.PropExpr{{CastAs: &ast.CastExpr{Arg: &ast.Ident{Name: "acol"}, TypeKind: STRING}}}}}}}},
+			[]ast.Stmt{&ast.CreateStmt{GraphName: &ast.QIdent{Names: []*ast.Ident{{Name: "mygraph"}}}, VertexTables: []*ast.VertexTableDecl{{TableName: &ast.QIdent{Names: []*ast.Ident{{Name: "atbl"}}}, Props: &ast.PropsClause{Exprs: []*ast.PropExpr{{CastAs: &ast.CastExpr{Arg: &ast.Ident{Name: "acol"}, TypeKind: ast.StringType}}}}}}}},
 		},
 
 		{
@@ -448,82 +449,82 @@ func TestParse(t *testing.T) {
 		{
 			"selectAggCount",
 			testToks(kw(SELECT), kw(COUNT), kw('('), kw('*'), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: COUNT}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.CountFunc, Star: true}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggCountExpr",
 			testToks(kw(SELECT), kw(COUNT), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: COUNT, Args: []ast.Expr{boolLit(false), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.CountFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggCountDistinct",
 			testToks(kw(SELECT), kw(COUNT), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: COUNT, Args: []ast.Expr{boolLit(true), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.CountFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggMinExpr",
 			testToks(kw(SELECT), kw(MIN), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: MIN, Args: []ast.Expr{boolLit(false), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.MinFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggMinDistinct",
 			testToks(kw(SELECT), kw(MIN), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: MIN, Args: []ast.Expr{boolLit(true), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.MinFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggMaxExpr",
 			testToks(kw(SELECT), kw(MAX), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: MAX, Args: []ast.Expr{boolLit(false), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.MaxFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggMaxDistinct",
 			testToks(kw(SELECT), kw(MAX), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: MAX, Args: []ast.Expr{boolLit(true), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.MaxFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggAvgExpr",
 			testToks(kw(SELECT), kw(AVG), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: AVG, Args: []ast.Expr{boolLit(false), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.AvgFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggAvgDistinct",
 			testToks(kw(SELECT), kw(AVG), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: AVG, Args: []ast.Expr{boolLit(true), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.AvgFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggSumExpr",
 			testToks(kw(SELECT), kw(SUM), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: SUM, Args: []ast.Expr{boolLit(false), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.SumFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggSumDistinct",
 			testToks(kw(SELECT), kw(SUM), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: SUM, Args: []ast.Expr{boolLit(true), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.SumFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggArrayAggExpr",
 			testToks(kw(SELECT), kw(ARRAY_AGG), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: ARRAY_AGG, Args: []ast.Expr{boolLit(false), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.ArrayAggFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggArrayAggDistinct",
 			testToks(kw(SELECT), kw(ARRAY_AGG), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: ARRAY_AGG, Args: []ast.Expr{boolLit(true), uiLit(2)}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.ArrayAggFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggListaggExpr",
 			testToks(kw(SELECT), kw(LISTAGG), kw('('), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: LISTAGG, Args: []ast.Expr{boolLit(false), uiLit(2), nil}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.ListaggFunc, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggListaggDistinct",
 			testToks(kw(SELECT), kw(LISTAGG), kw('('), kw(DISTINCT), ui(2), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: LISTAGG, Args: []ast.Expr{boolLit(true), uiLit(2), nil}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.ListaggFunc, Distinct: true, Arg: uiLit(2)}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectAggListaggSep",
 			testToks(kw(SELECT), kw(LISTAGG), kw('('), ui(2), kw(','), str("asep"), kw(')'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.OpExpr{Op: LISTAGG, Args: []ast.Expr{boolLit(false), uiLit(2), strLit("asep")}}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.AggregateExpr{Func: ast.ListaggFunc, Arg: uiLit(2), Separator: strLit("asep")}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
 		},
 		{
 			"selectHaving",
@@ -618,23 +619,23 @@ func TestParse(t *testing.T) {
 		},
 		{
 			"exprDateLiteral",
-			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(DATE), str("adate"), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: lit(ast.DateKind, "'adate'")}},
+			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(DATE), str("2000-01-02"), kw(';')),
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: quotedLit(ast.DateKind, "2000-01-02", "")}},
 		},
 		{
 			"exprTimeLiteral",
-			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(TIME), str("atime"), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: lit(ast.TimeKind, "'atime'")}},
+			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(TIME), str("12:34:56"), kw(';')),
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: quotedLit(ast.TimeKind, "12:34:56", "")}},
 		},
 		{
 			"exprTimestampLiteral",
-			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(TIMESTAMP), str("atimestamp"), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: lit(ast.TimestampKind, "'atimestamp'")}},
+			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(TIMESTAMP), str("2000-01-02 12:34:56"), kw(';')),
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: quotedLit(ast.TimestampKind, "2000-01-02 12:34:56", "")}},
 		},
 		{
 			"exprIntervalLiteral",
-			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(INTERVAL), str("aninterval"), kw(HOUR), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: lit(ast.IntervalKind, "'aninterval' HOUR")}},
+			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(INTERVAL), str("3"), kw(HOUR), kw(';')),
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: quotedLit(ast.IntervalKind, "3", " HOUR")}},
 		},
 		{
 			"exprBindVariable",
@@ -644,107 +645,107 @@ func TestParse(t *testing.T) {
 		{
 			"exprUnaryMinus",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw('-'), ui(2), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '-', Args: []ast.Expr{uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.NegOp, Args: []ast.Expr{uiLit(2)}}}},
 		},
 		{
 			"exprStringConcat",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(DPIPE), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: DPIPE, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.ConcatOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprMultiplication",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('*'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '*', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.MulOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprDivision",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('/'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '/', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.DivOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprModulo",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('%'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '%', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.ModOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprAddition",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('+'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '+', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.AddOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprSubtraction",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('-'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '-', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.SubOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprEqual",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('-'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '-', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.SubOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprNotEqual",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(LTGT), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: LTGT, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.NeOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprGreater",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('>'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '>', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.GtOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprLess",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('<'), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '<', Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.LtOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprGreaterOrEqual",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(GTEQ), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: GTEQ, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.GeOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprLessOrEqual",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(LTEQ), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: LTEQ, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.LeOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprNot",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(NOT), ui(2), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: NOT, Args: []ast.Expr{uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.NotOp, Args: []ast.Expr{uiLit(2)}}}},
 		},
 		{
 			"exprAnd",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(AND), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: AND, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.AndOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprOr",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(OR), ui(3), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: OR, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.OrOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
 		},
 		{
 			"exprIsNull",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(IS), kw(NULL), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: NULL, Args: []ast.Expr{uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.IsNullExpr{Arg: uiLit(2)}}},
 		},
 		{
 			"exprIsNotNull",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(IS), kw(NOT), kw(NULL), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: NOT_NULL, Args: []ast.Expr{uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.IsNullExpr{Arg: uiLit(2), Not: true}}},
 		},
 		{
 			"exprSubstring",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(SUBSTRING), kw('('), ui(2), kw(FROM), ui(3), kw(FOR), ui(4), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: SUBSTRING, Args: []ast.Expr{uiLit(2), uiLit(3), uiLit(4)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.SubstringExpr{Arg: uiLit(2), Start: uiLit(3), Length: uiLit(4)}}},
 		},
 		{
 			"exprSubstringNoFor",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(SUBSTRING), kw('('), ui(2), kw(FROM), ui(3), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: SUBSTRING, Args: []ast.Expr{uiLit(2), uiLit(3)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.SubstringExpr{Arg: uiLit(2), Start: uiLit(3)}}},
 		},
 		{
 			"exprExtract",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(EXTRACT), kw('('), kw(MINUTE), kw(FROM), ui(2), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: EXTRACT, Args: []ast.Expr{&ast.Ident{Name: "MINUTE"}, uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.ExtractExpr{Field: ast.MinuteField, Arg: uiLit(2)}}},
 		},
 		{
 			"exprFunctionInvocation",
@@ -759,12 +760,12 @@ func TestParse(t *testing.T) {
 		{
 			"exprLabel",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(LABEL), kw('('), ui(2), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: LABEL, Args: []ast.Expr{uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.LabelExpr{Arg: uiLit(2)}}},
 		},
 		{
 			"exprLabels",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(LABELS), kw('('), ui(2), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: LABELS, Args: []ast.Expr{uiLit(2)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.LabelExpr{Arg: uiLit(2), Plural: true}}},
 		},
 		{
 			"exprFunctionInvocationArg",
@@ -779,7 +780,7 @@ func TestParse(t *testing.T) {
 		{
 			"exprCastExpression",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(CAST), kw('('), ui(2), kw(AS), kw(STRING), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.CastExpr{Arg: uiLit(2), TypeKind: STRING}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.CastExpr{Arg: uiLit(2), TypeKind: ast.StringType}}},
 		},
 		{
 			"exprSimpleCase",
@@ -824,18 +825,18 @@ func TestParse(t *testing.T) {
 		{
 			"exprInBindVar",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw(IN), kw('?'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.InExpr{Subject: uiLit(2)}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.InExpr{Subject: uiLit(2), BindVar: &ast.BindVar{}}}},
 		},
 
 		{
 			"precMultiplicationAddition",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('*'), ui(3), kw('+'), ui(4), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '+', Args: []ast.Expr{&ast.OpExpr{Op: '*', Args: []ast.Expr{uiLit(2), uiLit(3)}}, uiLit(4)}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.AddOp, Args: []ast.Expr{&ast.OpExpr{Op: ast.MulOp, Args: []ast.Expr{uiLit(2), uiLit(3)}}, uiLit(4)}}}},
 		},
 		{
 			"precAdditionMultiplication",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), ui(2), kw('+'), ui(3), kw('*'), ui(4), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: '+', Args: []ast.Expr{uiLit(2), &ast.OpExpr{Op: '*', Args: []ast.Expr{uiLit(3), uiLit(4)}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: ast.AddOp, Args: []ast.Expr{uiLit(2), &ast.OpExpr{Op: ast.MulOp, Args: []ast.Expr{uiLit(3), uiLit(4)}}}}}},
 		},
 
 		// Subqueries
@@ -843,7 +844,7 @@ func TestParse(t *testing.T) {
 		{
 			"exprExistsSubquery",
 			testToks(kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(WHERE), kw(EXISTS), kw('('), kw(SELECT), kw('*'), kw(FROM), kw(MATCH), kw('('), kw(')'), kw(')'), kw(';')),
-			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.OpExpr{Op: EXISTS, Args: []ast.Expr{&ast.SubqueryExpr{Query: &ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}}}}}},
+			[]ast.Stmt{&ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}, Where: &ast.ExistsExpr{Query: &ast.SubqueryExpr{Query: &ast.SelectStmt{From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}}}}},
 		},
 
 		// Graph Modification
@@ -915,6 +916,11 @@ func TestParse(t *testing.T) {
 			testToks(kw(DROP), kw(PROPERTY), kw(GRAPH), qid(`"my""graph"`), kw(';')),
 			[]ast.Stmt{&ast.DropStmt{GraphName: &ast.QIdent{Names: []*ast.Ident{{Name: `my"graph`}}}}},
 		},
+		{
+			"nonReservedKeyword",
+			testToks(kw(SELECT), testToken{FOR, yySymType{L: &lexValue{S: "for"}}}, kw(AS), testToken{SUBSTRING, yySymType{L: &lexValue{S: "substring"}}}, kw(FROM), kw(MATCH), kw('('), kw(')'), kw(';')),
+			[]ast.Stmt{&ast.SelectStmt{Sels: []*ast.SelectElem{{Named: &ast.NamedExpr{Expr: &ast.Ident{Name: "for"}, Name: &ast.Ident{Name: "substring"}}}}, From: []*ast.MatchClause{{Patterns: []*ast.PathPattern{{Vs: []*ast.VertexPattern{{}}}}}}}},
+		},
 	}
 	for _, tst := range tsts {
 		tst := tst
@@ -928,7 +934,8 @@ func TestParse(t *testing.T) {
 				t.Fatalf("yyParse failed: %d, %s", ret, l.errs)
 			}
 
-			if diff := cmp.Diff(tst.Want, l.stmts); diff != "" {
+			// Positions are tested with real input in the parent package.
+			if diff := cmp.Diff(tst.Want, l.stmts, cmpopts.IgnoreTypes(ast.Pos(0))); diff != "" {
 				t.Errorf("yyParse: +got, -want:\n%s", diff)
 			}
 		})
@@ -972,7 +979,13 @@ func boolLit(b bool) *ast.BasicLit {
 }
 
 func strLit(s string) *ast.BasicLit {
-	return &ast.BasicLit{S: "'" + s + "'", Kind: ast.StringKind}
+	return &ast.BasicLit{S: "'" + s + "'", Text: s, Kind: ast.StringKind}
+}
+
+// quotedLit returns a literal of the kind, with the quoted string s,
+// followed by suffix.
+func quotedLit(kind ast.LitKind, s, suffix string) *ast.BasicLit {
+	return &ast.BasicLit{S: "'" + s + "'" + suffix, Text: s, Kind: kind}
 }
 
 func uiLit(i uint) *ast.BasicLit {
@@ -1013,6 +1026,10 @@ func (l *sliceLexer) Stmts(ss []ast.Stmt) {
 	l.stmts = ss
 }
 
+func (l *sliceLexer) Expr(ast.Expr) {}
+
+func (l *sliceLexer) PathPattern(*ast.PathPattern) {}
+
 type testToken struct {
 	Tok  int
 	LVal yySymType
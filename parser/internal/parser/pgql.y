@@ -3,8 +3,6 @@
 package parser
 
 import (
-	"fmt"
-
 	"github.com/itergia/pgql-go/ast"
 	"github.com/itergia/pgql-go/token"
 )
@@ -12,12 +10,18 @@ import (
 type yyParam interface {
 	yyLexer
 	Stmts([]ast.Stmt)
+	Expr(ast.Expr)
+	PathPattern(*ast.PathPattern)
 }
 
 type lexValue struct {
 	P Position
+	E Position // The position after the token.
 	PreWS []rune
 	S string
+
+	// Comments holds the comments in PreWS.
+	Comments []*ast.CommentGroup
 }
 %}
 
@@ -114,10 +118,20 @@ type lexValue struct {
 %right IS
 %right UMINUS
 
-// Virtual tokens.
+// SUBSTRING followed by '(' is a CharacterSubstring, not a function
+// named substring.
+%nonassoc SUBSTRING
+%nonassoc '('
+
+// SYNC is returned by the lexer after a syntax error, before the
+// token where parsing resumes. See parserContext.Lex.
 
-%token NOT_NULL
-%token TIME_TZ TIMESTAMP_TZ
+%token SYNC
+
+// START_EXPR, START_PATH and START_STMT are returned by the lexer as
+// the first token when parsing a fragment. See parseFragment.
+
+%token START_EXPR START_PATH START_STMT
 
 // Literals.
 
@@ -126,7 +140,7 @@ type lexValue struct {
 
 // Productions.
 
-%type <Stmts> PgqlStatements PgqlStatement CreatePropertyGraph DropPropertyGraph Query SelectQuery ModifyQuery ModifyQueryFull
+%type <Stmts> PgqlStatement CreatePropertyGraph DropPropertyGraph Query SelectQuery ModifyQuery ModifyQueryFull
 %type <VTables> VertexTables VertexTableList VertexTable LabelAndPropertiesClause
 %type <ETables> EdgeTables OptEdgeTables EdgeTableList EdgeTable
 %type <VTableRef> SourceVertexTable DestinationVertexTable
@@ -135,7 +149,7 @@ type lexValue struct {
 %type <SelStmt> SelectClause
 %type <PathMacros> OptPathPatternMacros PathPatternMacro
 %type <SelElems> SelectElementList SelectElement AllProperties
-%type <Matches> OptFromClause FromClause MatchClauseList MatchClause
+%type <Matches> SelectFromClause OptFromClause FromClause MatchClauseList MatchClause
 %type <PPats> MatchPattern GraphPattern PathPatternList PathPattern SimplePathPattern AnyPathPattern AnyShortestPathPattern AllShortestPathPattern TopKShortestPathPattern AnyCheapestPathPattern TopKCheapestPathPattern AllPathPattern
 %type <PPPats> PathPrimary QuantifiedPathPatternPrimary PathPatternPrimary ParenthesizedPathPatternExpression ReachabilityPathExpression OutgoingPathPattern IncomingPathPattern PathSpecification PathPredicate
 %type <VPats> OptVertexPattern VertexPattern VariableSpecification SourceVertexPattern DestinationVertexPattern
@@ -147,20 +161,24 @@ type lexValue struct {
 %type <Updates> GraphElementUpdateList GraphElementUpdate
 %type <PropAss> OptPropertiesSpecification PropertiesSpecification PropertyAssignmentList PropertyAssignment
 %type <NExprs> ExpAsVarList ExpAsVar OptGroupByClause GroupByClause
-%type <Exprs> OptLimitOffsetClauses LimitOffsetClauses OptArgumentList ArgumentList InValueList ValueExpressionList
-%type <Expr> OptWhereClause WhereClause Aggregation CountAggregation MinAggregation MaxAggregation AvgAggregation SumAggregation ArrayAggregation ListaggAggregation OptListaggSeparator ListaggSeparator OptHavingClause HavingClause LimitClause OffsetClause LimitOffsetValue ValueExpression OptCostClause CostClause BracketedValueExpression BindVariable ArithmeticExpression UnaryMinus Multiplication Division Modulo Addition Subtraction RelationalExpression Equal NotEqual Greater Less GreaterOrEqual LessOrEqual LogicalExpression Not And Or StringConcat IsNullPredicate IsNotNullPredicate CharacterSubstring StartPosition StringLength ExtractFunction FunctionInvocation CastSpecification CaseExpression SimpleCase SearchedCase OptElseClause ElseClause InPredicate NotInPredicate ExistsPredicate Subquery ScalarSubquery
+%type <Exprs> OptLimitOffsetClauses LimitOffsetClauses OptArgumentList ArgumentList ValueExpressionList
+%type <Expr> OptWhereClause WhereClause Aggregation CountAggregation MinAggregation MaxAggregation AvgAggregation SumAggregation ArrayAggregation ListaggAggregation OptHavingClause HavingClause LimitClause OffsetClause LimitOffsetValue ValueExpression OptCostClause CostClause BracketedValueExpression BindVariable ArithmeticExpression UnaryMinus Multiplication Division Modulo Addition Subtraction RelationalExpression Equal NotEqual Greater Less GreaterOrEqual LessOrEqual LogicalExpression Not And Or StringConcat IsNullPredicate IsNotNullPredicate CharacterSubstring StartPosition StringLength ExtractFunction FunctionInvocation CastSpecification CaseExpression SimpleCase SearchedCase OptElseClause ElseClause InPredicate NotInPredicate InValueList ExistsPredicate Subquery ScalarSubquery
 %type <Whens> WhenClauseList WhenClause
 %type <Quant> OptGraphPatternQuantifier GraphPatternQuantifier ZeroOrMore OneOrMore Optional ExactlyN NOrMore BetweenNAndM BetweenZeroAndM
 %type <QIdent> GraphName TableName SchemaQualifiedName SchemaIdentifierPart OptOnClause OnClause PropertyAccess OptIntoClause IntoClause
-%type <Ident> Identifier TableAlias OptTableAlias ColumnName LabelClause OptLabelClause Label ColumnReference PropertyName ColumnReference OptVariableName VariableName VertexVariable VertexVariable1 VertexVariable2 EdgeVariable VariableReference ExtractField FunctionName VertexReference
+%type <Ident> Identifier TableAlias OptTableAlias ColumnName LabelClause OptLabelClause Label ColumnReference PropertyName ColumnReference OptVariableName VariableName VertexVariable VertexVariable1 VertexVariable2 EdgeVariable VariableReference FunctionName VertexReference NonReservedKeyword
 %type <Idents> OptKeyClause KeyClause ColumnNameList OptExceptColumns ExceptColumns ColumnReferenceList OptLabelPredicate LabelPredicate LabelList LabelAlt OptLabelSpecification LabelSpecification VariableReferenceList
-%type <BLit> AllPropertiesPrefix KValue Literal StringLiteral NumericLiteral BooleanLiteral DateLiteral TimeLiteral TimestampLiteral IntervalLiteral DateTimeField
+%type <BLit> OptListaggSeparator ListaggSeparator AllPropertiesPrefix KValue Literal StringLiteral NumericLiteral BooleanLiteral DateLiteral TimeLiteral TimestampLiteral IntervalLiteral DateTimeField
 %type <B> OptDistinct
-%type <I> DataType
+%type <DataType> DataType
+%type <Field> ExtractField
 
 %union {
   L *lexValue
 
+  // Pos is the position of the closing token of some productions.
+  Pos ast.Pos
+
   Stmts []ast.Stmt
   VTables []*ast.VertexTableDecl
   ETables []*ast.EdgeTableDecl
@@ -191,7 +209,8 @@ type lexValue struct {
   Idents []*ast.Ident
   BLit *ast.BasicLit
   B bool
-  I int
+  DataType ast.DataType
+  Field ast.DateTimeField
 }
 
 %start start
@@ -200,13 +219,18 @@ type lexValue struct {
 
 // Not part of the specification.
 
-start: PgqlStatements  { yylex.(yyParam).Stmts($1) }
+// The parser is invoked once per statement. Parse resynchronizes at
+// the next semicolon if a statement cannot be parsed. The error
+// productions below resynchronize at clause keywords. The START_
+// tokens select a fragment to parse instead, until EOF.
+start: PgqlStatement ';'                { yylex.(yyParam).Stmts($1) }
+     | PgqlStatement error              { yylex.(yyParam).Stmts($1) }
+     | START_EXPR ValueExpression       { yylex.(yyParam).Expr($2) }
+     | START_PATH PathPattern           { yylex.(yyParam).PathPattern($2[0]) }
+     | START_STMT PgqlStatement         { yylex.(yyParam).Stmts($2) }
+     | START_STMT PgqlStatement ';'     { yylex.(yyParam).Stmts($2) }
      ;
 
-PgqlStatements: PgqlStatement ';'
-              | PgqlStatements PgqlStatement ';'  { $$ = append($1, $2[0]) }
-              ;
-
 // Main Query Structure
 
 PgqlStatement: CreatePropertyGraph
@@ -216,20 +240,20 @@ PgqlStatement: CreatePropertyGraph
 
 // Creating a Property Graph
 
-CreatePropertyGraph: CREATE PROPERTY GRAPH GraphName VertexTables OptEdgeTables  { $$ = []ast.Stmt{&ast.CreateStmt{GraphName: $4, VertexTables: $5, EdgeTables: $6}} }
+CreatePropertyGraph: CREATE PROPERTY GRAPH GraphName VertexTables OptEdgeTables  { stmt := &ast.CreateStmt{GraphName: $4, VertexTables: $5, EdgeTables: $6, Create: pos($<L>1), Rparen: $<Pos>5}; if $6 != nil { stmt.Rparen = $<Pos>6 }; $$ = []ast.Stmt{leading(yylex, stmt, $<L>1)} }
                    ;
 
 GraphName: SchemaQualifiedName
          ;
 
-SchemaQualifiedName: Identifier                       { $$ = &ast.QIdent{Names: []*ast.Ident{$1}} }
-                   | SchemaIdentifierPart Identifier  { $$ = &ast.QIdent{Names: append($1.Names, $2)} }
+SchemaQualifiedName: Identifier                       { $$ = leading(yylex, &ast.QIdent{Names: []*ast.Ident{$1}}, $<L>1) }
+                   | SchemaIdentifierPart Identifier  { $$ = leading(yylex, &ast.QIdent{Names: append($1.Names, $2)}, $<L>1) }
                    ;
 
 SchemaIdentifierPart: Identifier '.'  { $$ = &ast.QIdent{Names: []*ast.Ident{$1}} }
                     ;
 
-VertexTables: VERTEX TABLES '(' VertexTableList ')'  { $$ = $4 }
+VertexTables: VERTEX TABLES '(' VertexTableList ')'  { $$ = $4; $<Pos>$ = pos($<L>5) }
             ;
 
 VertexTableList: VertexTable
@@ -240,14 +264,14 @@ OptEdgeTables: /* empty */  { $$ = nil }
              | EdgeTables
              ;
 
-EdgeTables: EDGE TABLES '(' EdgeTableList ')'  { $$ = $4 }
+EdgeTables: EDGE TABLES '(' EdgeTableList ')'  { $$ = $4; $<Pos>$ = pos($<L>5) }
           ;
 
 EdgeTableList: EdgeTable
              | EdgeTableList ',' EdgeTable  { $$ = append($1, $3[0]) }
              ;
 
-VertexTable: TableName OptTableAlias OptKeyClause LabelAndPropertiesClause  { $$ = []*ast.VertexTableDecl{{TableName: $1, TableAlias: $2, Label: $4[0].Label, Props: $4[0].Props, Keys: $3}} }
+VertexTable: TableName OptTableAlias OptKeyClause LabelAndPropertiesClause  { $$ = []*ast.VertexTableDecl{leading(yylex, &ast.VertexTableDecl{TableName: $1, TableAlias: $2, Label: $4[0].Label, Props: $4[0].Props, Keys: $3, KeyRparen: $<Pos>3}, $<L>1)} }
            ;
 
 LabelAndPropertiesClause: OptLabelClause OptPropertiesClause  { $$ = []*ast.VertexTableDecl{{Label: $1, Props: $2}} }
@@ -256,17 +280,17 @@ LabelAndPropertiesClause: OptLabelClause OptPropertiesClause  { $$ = []*ast.Vert
 TableName: SchemaQualifiedName
          ;
 
-EdgeTable: TableName OptTableAlias OptKeyClause SourceVertexTable DestinationVertexTable LabelAndPropertiesClause  { $$ = []*ast.EdgeTableDecl{{TableName: $1, TableAlias: $2, Source: $4, Dest: $5, Label: $6[0].Label, Props: $6[0].Props, Keys: $3}} }
+EdgeTable: TableName OptTableAlias OptKeyClause SourceVertexTable DestinationVertexTable LabelAndPropertiesClause  { $$ = []*ast.EdgeTableDecl{leading(yylex, &ast.EdgeTableDecl{TableName: $1, TableAlias: $2, Source: $4, Dest: $5, Label: $6[0].Label, Props: $6[0].Props, Keys: $3, KeyRparen: $<Pos>3}, $<L>1)} }
          ;
 
 // In the 1.5 spec, KEY and the referenced columns are missing.
-SourceVertexTable: SOURCE KEY '(' ColumnNameList ')' REFERENCES TableName '(' ColumnNameList ')'  { $$ = &ast.VertexTableRef{Keys: $4, TableName: $7, Columns: $9} }
-                 | SOURCE TableName                                                               { $$ = &ast.VertexTableRef{TableName: $2} }
+SourceVertexTable: SOURCE KEY '(' ColumnNameList ')' REFERENCES TableName '(' ColumnNameList ')'  { $$ = &ast.VertexTableRef{Keys: $4, TableName: $7, Columns: $9, Keyword: pos($<L>1), Rparen: pos($<L>10)} }
+                 | SOURCE TableName                                                               { $$ = &ast.VertexTableRef{TableName: $2, Keyword: pos($<L>1)} }
                  ;
 
 // In the 1.5 spec, KEY and the referenced columns are missing.
-DestinationVertexTable: DESTINATION KEY '(' ColumnNameList ')' REFERENCES TableName '(' ColumnNameList ')'  { $$ = &ast.VertexTableRef{Keys: $4, TableName: $7, Columns: $9} }
-                      | DESTINATION TableName                                                               { $$ = &ast.VertexTableRef{TableName: $2} }
+DestinationVertexTable: DESTINATION KEY '(' ColumnNameList ')' REFERENCES TableName '(' ColumnNameList ')'  { $$ = &ast.VertexTableRef{Keys: $4, TableName: $7, Columns: $9, Keyword: pos($<L>1), Rparen: pos($<L>10)} }
+                      | DESTINATION TableName                                                               { $$ = &ast.VertexTableRef{TableName: $2, Keyword: pos($<L>1)} }
                       ;
 
 OptTableAlias: /* empty */  { $$ = nil }
@@ -277,11 +301,11 @@ TableAlias: AS Identifier  { $$ = $2 }
           | Identifier
           ;
 
-OptKeyClause: /* empty */  { $$ = nil }
+OptKeyClause: /* empty */  { $$ = nil; $<Pos>$ = ast.NoPos }
             | KeyClause
             ;
 
-KeyClause: KEY '(' ColumnNameList ')'  { $$ = $3 }
+KeyClause: KEY '(' ColumnNameList ')'  { $$ = $3; $<Pos>$ = pos($<L>4) }
          ;
 
 ColumnNameList: ColumnName                     { $$ = []*ast.Ident{$1} }
@@ -314,7 +338,7 @@ PropertiesClause: PropertiesAreAllColumns
                 | NoProperties
                 ;
 
-PropertiesAreAllColumns: PROPERTIES OptAre ALL COLUMNS OptExceptColumns  { $$ = &ast.PropsClause{Except: $5} }
+PropertiesAreAllColumns: PROPERTIES OptAre ALL COLUMNS OptExceptColumns  { $$ = &ast.PropsClause{Except: $5, Properties: pos($<L>1), EndPos: end($<L>4)}; if $5 != nil { $$.EndPos = $<Pos>5 + 1 } }
                        ;
 
 OptAre: /* empty */
@@ -325,14 +349,14 @@ OptExceptColumns: /* empty */    { $$ = nil }
                 | ExceptColumns
                 ;
 
-ExceptColumns: EXCEPT '(' ColumnReferenceList ')'  { $$ = $3 }
+ExceptColumns: EXCEPT '(' ColumnReferenceList ')'  { $$ = $3; $<Pos>$ = pos($<L>4) }
              ;
 
 ColumnReferenceList: ColumnReference                          { $$ = []*ast.Ident{$1} }
                    | ColumnReferenceList ',' ColumnReference  { $$ = append($1, $3) }
                    ;
 
-PropertyExpressions: PROPERTIES '(' PropertyExpressionList ')'  { $$ = &ast.PropsClause{Exprs: $3} }
+PropertyExpressions: PROPERTIES '(' PropertyExpressionList ')'  { $$ = &ast.PropsClause{Exprs: $3, Properties: pos($<L>1), EndPos: end($<L>4)} }
                    ;
 
 PropertyExpressionList: PropertyExpression
@@ -353,10 +377,10 @@ PropertyName: Identifier
 ColumnReference: Identifier
                ;
 
-NoProperties: NO PROPERTIES  { $$ = &ast.PropsClause{None: true} }
+NoProperties: NO PROPERTIES  { $$ = &ast.PropsClause{None: true, Properties: pos($<L>1), EndPos: end($<L>2)} }
             ;
 
-DropPropertyGraph: DROP PROPERTY GRAPH GraphName  { $$ = []ast.Stmt{&ast.DropStmt{GraphName: $4}} }
+DropPropertyGraph: DROP PROPERTY GRAPH GraphName  { $$ = []ast.Stmt{leading(yylex, &ast.DropStmt{GraphName: $4, Drop: pos($<L>1)}, $<L>1)} }
                  ;
 
 // Graph Pattern Matching
@@ -365,11 +389,12 @@ Query: SelectQuery
      | ModifyQuery
      ;
 
-SelectQuery: OptPathPatternMacros SelectClause FromClause OptWhereClause OptGroupByClause OptHavingClause OptOrderByClause OptLimitOffsetClauses  { $$ = []ast.Stmt{&ast.SelectStmt{PathMacros: $1, Distinct: $2.Distinct, Sels: $2.Sels, From: $3, Where: $4, GroupBy: $5, Having: $6, OrderBy: $7, Limit: $8[0], Offset: $8[1]}} }
+SelectQuery: OptPathPatternMacros SelectClause SelectFromClause OptWhereClause OptGroupByClause OptHavingClause OptOrderByClause OptLimitOffsetClauses  { stmt := &ast.SelectStmt{PathMacros: $1, Distinct: $2.Distinct, Sels: $2.Sels, Select: $2.Select, From: $3, Where: $4, GroupBy: $5, Having: $6, OrderBy: $7, Limit: $8[0], Offset: $8[1]}; $$ = []ast.Stmt{leading(yylex, leading(yylex, stmt, $<L>2), $<L>1)} }
            ;
 
-SelectClause: SELECT OptDistinct SelectElementList  { $$ = &ast.SelectStmt{Distinct: $2, Sels: $3} }
-            | SELECT '*'                            { $$ = &ast.SelectStmt{} }
+SelectClause: SELECT OptDistinct SelectElementList  { $$ = &ast.SelectStmt{Distinct: $2, Sels: $3, Select: pos($<L>1)} }
+            | SELECT '*'                            { $$ = &ast.SelectStmt{Select: pos($<L>1)} }
+            | SELECT error SYNC                     { $$ = &ast.SelectStmt{Select: pos($<L>1)} }
             ;
 
 OptDistinct: /* empty */  { $$ = false }
@@ -380,7 +405,7 @@ SelectElementList: SelectElement
                  | SelectElementList ',' SelectElement  { $$ = append($1, $3[0]) }
                  ;
 
-SelectElement: ExpAsVar       { $$ = []*ast.SelectElem{{Named: $1[0]}} }
+SelectElement: ExpAsVar       { $$ = []*ast.SelectElem{leading(yylex, &ast.SelectElem{Named: $1[0]}, $<L>1)} }
              | AllProperties
              ;
 
@@ -388,13 +413,13 @@ ExpAsVarList: ExpAsVar
             | ExpAsVarList ',' ExpAsVar  { $$ = append($1, $3[0]) }
             ;
 
-ExpAsVar: ValueExpression AS VariableName  { $$ = []*ast.NamedExpr{{Expr: $1, Name: $3}} }
-        | ValueExpression                  { $$ = []*ast.NamedExpr{{Expr: $1}} }
+ExpAsVar: ValueExpression AS VariableName  { $$ = []*ast.NamedExpr{leading(yylex, &ast.NamedExpr{Expr: $1, Name: $3}, $<L>1)} }
+        | ValueExpression                  { $$ = []*ast.NamedExpr{leading(yylex, &ast.NamedExpr{Expr: $1}, $<L>1)} }
         ;
 
 // The 1.5 spec says '.*' as one token, but we ignore space for symmetry.
-AllProperties: Identifier '.' '*' AllPropertiesPrefix  { $$ = []*ast.SelectElem{{AllOf: $1, Prefix: $4}} }
-             | Identifier '.' '*'                      { $$ = []*ast.SelectElem{{AllOf: $1}} }
+AllProperties: Identifier '.' '*' AllPropertiesPrefix  { $$ = []*ast.SelectElem{leading(yylex, &ast.SelectElem{AllOf: $1, Prefix: $4, Star: pos($<L>3)}, $<L>1)} }
+             | Identifier '.' '*'                      { $$ = []*ast.SelectElem{leading(yylex, &ast.SelectElem{AllOf: $1, Star: pos($<L>3)}, $<L>1)} }
              ;
 
 AllPropertiesPrefix: PREFIX StringLiteral  { $$ = $2 }
@@ -404,21 +429,29 @@ OptFromClause: /* empty */  { $$ = nil }
              | FromClause
              ;
 
-FromClause: FROM MatchClauseList  { $$ = $2 }
+// Not part of the specification. Resynchronizes at FROM after an
+// error in the SELECT clause.
+SelectFromClause: FromClause
+                | error SYNC FromClause  { $$ = $3 }
+                ;
+
+FromClause: FROM MatchClauseList  { $$ = $2; leading(yylex, $2[0], $<L>1) }
+          | FROM error SYNC       { $$ = nil }
           ;
 
 MatchClauseList: MatchClause
                | MatchClauseList ',' MatchClause  { $$ = append($1, $3[0]) }
                ;
 
-MatchClause: MATCH MatchPattern OptOnClause OptRowsPerMatch  { $$ = []*ast.MatchClause{{Patterns: $2, On: $3, Rows: $4}} }
+MatchClause: MATCH MatchPattern OptOnClause OptRowsPerMatch  { m := &ast.MatchClause{Patterns: $2, On: $3, Rows: $4, Match: pos($<L>1)}; if $<Pos>2.IsValid() { m.Lparen, m.Rparen = pos($<L>2), $<Pos>2 }; $$ = []*ast.MatchClause{leading(yylex, m, $<L>1)} }
            ;
 
-MatchPattern: PathPattern
+// The position of the closing parenthesis of a GraphPattern.
+MatchPattern: PathPattern   { $<Pos>$ = ast.NoPos }
             | GraphPattern
             ;
 
-GraphPattern: '(' PathPatternList ')'  { $$ = $2 }
+GraphPattern: '(' PathPatternList ')'  { $$ = $2; $<Pos>$ = pos($<L>3) }
             ;
 
 PathPatternList: PathPattern
@@ -435,7 +468,7 @@ PathPattern: SimplePathPattern
            | AllPathPattern
            ;
 
-SimplePathPattern: VertexPattern                                { $$ = []*ast.PathPattern{{Vs: $1}} }
+SimplePathPattern: VertexPattern                                { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: $1}, $<L>1)} }
                  | SimplePathPattern PathPrimary VertexPattern  { $$ = $1; p := $$[len($$)-1]; p.Vs = append(p.Vs, $3[0]); p.Es = append(p.Es, $2[0]) }
                  ;
 
@@ -443,10 +476,10 @@ OptVertexPattern: /* empty */    { $$ = nil }
                 | VertexPattern
                 ;
 
-VertexPattern: '(' VariableSpecification ')'  { $$ = $2 }
+VertexPattern: '(' VariableSpecification ')'  { $$ = $2; $$[0].Lparen, $$[0].Rparen = pos($<L>1), pos($<L>3); leading(yylex, $2[0], $<L>1) }
              ;
 
-PathPrimary: EdgePattern                 { $$ = []*ast.PathPatternPrimary{{Es: $1}} }
+PathPrimary: EdgePattern                 { $$ = []*ast.PathPatternPrimary{leading(yylex, &ast.PathPatternPrimary{Es: $1}, $<L>1)} }
            | ReachabilityPathExpression
            ;
 
@@ -455,16 +488,16 @@ EdgePattern: OutgoingEdgePattern
            | AnyDirectedEdgePattern
            ;
 
-OutgoingEdgePattern: RARROW                                            { $$ = []*ast.EdgePattern{{Dir: ast.Outgoing}} }
-                   | LDASHBRACKET VariableSpecification RBRACKETARROW  { $$ = []*ast.EdgePattern{{Name: $2[0].Name, LabelAlts: $2[0].LabelAlts, Dir: ast.Outgoing}} }
+OutgoingEdgePattern: RARROW                                            { $$ = []*ast.EdgePattern{leading(yylex, &ast.EdgePattern{Dir: ast.Outgoing, EdgePos: pos($<L>1), EndPos: end($<L>1)}, $<L>1)} }
+                   | LDASHBRACKET VariableSpecification RBRACKETARROW  { $$ = []*ast.EdgePattern{leading(yylex, &ast.EdgePattern{Name: $2[0].Name, LabelAlts: $2[0].LabelAlts, Dir: ast.Outgoing, EdgePos: pos($<L>1), EndPos: end($<L>3)}, $<L>1)} }
                    ;
 
-IncomingEdgePattern: LARROW                                            { $$ = []*ast.EdgePattern{{Dir: ast.Incoming}} }
-                   | LARROWBRACKET VariableSpecification RBRACKETDASH  { $$ = []*ast.EdgePattern{{Name: $2[0].Name, LabelAlts: $2[0].LabelAlts, Dir: ast.Incoming}} }
+IncomingEdgePattern: LARROW                                            { $$ = []*ast.EdgePattern{leading(yylex, &ast.EdgePattern{Dir: ast.Incoming, EdgePos: pos($<L>1), EndPos: end($<L>1)}, $<L>1)} }
+                   | LARROWBRACKET VariableSpecification RBRACKETDASH  { $$ = []*ast.EdgePattern{leading(yylex, &ast.EdgePattern{Name: $2[0].Name, LabelAlts: $2[0].LabelAlts, Dir: ast.Incoming, EdgePos: pos($<L>1), EndPos: end($<L>3)}, $<L>1)} }
                    ;
 
-AnyDirectedEdgePattern: '-'                                              { $$ = []*ast.EdgePattern{{Dir: ast.AnyDir}} }
-                      | LDASHBRACKET VariableSpecification RBRACKETDASH  { $$ = []*ast.EdgePattern{{Name: $2[0].Name, LabelAlts: $2[0].LabelAlts, Dir: ast.AnyDir}} }
+AnyDirectedEdgePattern: '-'                                              { $$ = []*ast.EdgePattern{leading(yylex, &ast.EdgePattern{Dir: ast.AnyDir, EdgePos: pos($<L>1), EndPos: end($<L>1)}, $<L>1)} }
+                      | LDASHBRACKET VariableSpecification RBRACKETDASH  { $$ = []*ast.EdgePattern{leading(yylex, &ast.EdgePattern{Name: $2[0].Name, LabelAlts: $2[0].LabelAlts, Dir: ast.AnyDir, EdgePos: pos($<L>1), EndPos: end($<L>3)}, $<L>1)} }
                       ;
 
 VariableSpecification: OptVariableName OptLabelPredicate  { $$ = []*ast.VertexPattern{{Name: $1, LabelAlts: $2}} }
@@ -481,7 +514,7 @@ OptOnClause: /* empty */  { $$ = nil }
            | OnClause
            ;
 
-OnClause: ON GraphName  { $$ = $2 }
+OnClause: ON GraphName  { $$ = leading(yylex, $2, $<L>1) }
         ;
 
 OptLabelPredicate: /* empty */     { $$ = nil }
@@ -500,7 +533,8 @@ OptWhereClause: /* empty */  { $$ = nil }
               | WhereClause
               ;
 
-WhereClause: WHERE ValueExpression  { $$ = $2 }
+WhereClause: WHERE ValueExpression  { $$ = leading(yylex, $2, $<L>1) }
+           | WHERE error SYNC       { $$ = nil }
            ;
 
 // Variable-Length Paths
@@ -518,29 +552,29 @@ GraphPatternQuantifier: ZeroOrMore
                       | BetweenZeroAndM
                       ;
 
-ZeroOrMore: '*'  { $$ = &ast.Quantifier{Min: nil, Max: nil, Group: true} }
+ZeroOrMore: '*'  { $$ = &ast.Quantifier{Min: nil, Max: nil, Group: true, Lbrace: pos($<L>1), Rbrace: pos($<L>1)} }
           ;
 
-OneOrMore: '+'  { $$ = &ast.Quantifier{Min: &ast.BasicLit{S: "1", Kind: ast.UIntKind}, Max: nil, Group: true} }
+OneOrMore: '+'  { $$ = &ast.Quantifier{Min: &ast.BasicLit{S: "1", Kind: ast.UIntKind}, Max: nil, Group: true, Lbrace: pos($<L>1), Rbrace: pos($<L>1)} }
          ;
 
-Optional: '?'  { $$ = &ast.Quantifier{Min: nil, Max: &ast.BasicLit{S: "1", Kind: ast.UIntKind}} }
+Optional: '?'  { $$ = &ast.Quantifier{Min: nil, Max: &ast.BasicLit{S: "1", Kind: ast.UIntKind}, Lbrace: pos($<L>1), Rbrace: pos($<L>1)} }
         ;
 
-ExactlyN: '{' UNSIGNED_INTEGER '}'  { $$ = &ast.Quantifier{Min: &ast.BasicLit{S: $2.S, Kind: ast.UIntKind}, Max: &ast.BasicLit{S: $2.S, Kind: ast.UIntKind}, Group: true} }
+ExactlyN: '{' UNSIGNED_INTEGER '}'  { $$ = &ast.Quantifier{Min: uintLit($2), Max: uintLit($2), Group: true, Lbrace: pos($<L>1), Rbrace: pos($<L>3)} }
         ;
 
-NOrMore: '{' UNSIGNED_INTEGER ',' '}'  { $$ = &ast.Quantifier{Min: &ast.BasicLit{S: $2.S, Kind: ast.UIntKind}, Max: nil, Group: true} }
+NOrMore: '{' UNSIGNED_INTEGER ',' '}'  { $$ = &ast.Quantifier{Min: uintLit($2), Max: nil, Group: true, Lbrace: pos($<L>1), Rbrace: pos($<L>4)} }
        ;
 
-BetweenNAndM: '{' UNSIGNED_INTEGER ',' UNSIGNED_INTEGER '}'  { $$ = &ast.Quantifier{Min: &ast.BasicLit{S: $2.S, Kind: ast.UIntKind}, Max: &ast.BasicLit{S: $4.S, Kind: ast.UIntKind}, Group: true} }
+BetweenNAndM: '{' UNSIGNED_INTEGER ',' UNSIGNED_INTEGER '}'  { $$ = &ast.Quantifier{Min: uintLit($2), Max: uintLit($4), Group: true, Lbrace: pos($<L>1), Rbrace: pos($<L>5)} }
             ;
 
-BetweenZeroAndM: '{' ',' UNSIGNED_INTEGER '}'  { $$ = &ast.Quantifier{Min: nil, Max: &ast.BasicLit{S: $3.S, Kind: ast.UIntKind}, Group: true} }
+BetweenZeroAndM: '{' ',' UNSIGNED_INTEGER '}'  { $$ = &ast.Quantifier{Min: nil, Max: uintLit($3), Group: true, Lbrace: pos($<L>1), Rbrace: pos($<L>4)} }
                ;
 
-AnyPathPattern: ANY SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($2, $4[0]), Es: $3, Cardinality: ast.AnyCardinality}} }
-              | ANY '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AnyCardinality}} }
+AnyPathPattern: ANY SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($2, $4[0]), Es: $3, Cardinality: ast.AnyCardinality, CardPos: pos($<L>1)}, $<L>1)} }
+              | ANY '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AnyCardinality, CardPos: pos($<L>1), Rparen: pos($<L>6)}, $<L>1)} }
               ;
 
 SourceVertexPattern: VertexPattern
@@ -552,21 +586,21 @@ DestinationVertexPattern: VertexPattern
 QuantifiedPathPatternPrimary: PathPatternPrimary OptGraphPatternQuantifier  { $$ = $1; $$[0].Quantity = $2 }
                             ;
 
-PathPatternPrimary: EdgePattern                         { $$ = []*ast.PathPatternPrimary{{Es: $1}} }
+PathPatternPrimary: EdgePattern                         { $$ = []*ast.PathPatternPrimary{leading(yylex, &ast.PathPatternPrimary{Es: $1}, $<L>1)} }
                   | ParenthesizedPathPatternExpression
                   ;
 
-ParenthesizedPathPatternExpression: '(' OptVertexPattern EdgePattern OptVertexPattern OptWhereClause OptCostClause ')'  { $$ = []*ast.PathPatternPrimary{{Vs: []*ast.VertexPattern{indexOr($2, 0, nil), indexOr($4, 0, nil)}, Es: $3, Where: $5, Cost: $6}} }
+ParenthesizedPathPatternExpression: '(' OptVertexPattern EdgePattern OptVertexPattern OptWhereClause OptCostClause ')'  { $$ = []*ast.PathPatternPrimary{leading(yylex, &ast.PathPatternPrimary{Vs: []*ast.VertexPattern{indexOr($2, 0, nil), indexOr($4, 0, nil)}, Es: $3, Where: $5, Cost: $6, Lparen: pos($<L>1), Rparen: pos($<L>7)}, $<L>1)} }
                                   ;
 
 ReachabilityPathExpression: OutgoingPathPattern
                           | IncomingPathPattern
                           ;
 
-OutgoingPathPattern: LDASHSLASH PathSpecification RSLASHARROW  { $$ = $2; $$[0].Es[0].Dir = ast.Outgoing }
+OutgoingPathPattern: LDASHSLASH PathSpecification RSLASHARROW  { $$ = $2; e := $$[0].Es[0]; e.Dir, e.EdgePos, e.EndPos = ast.Outgoing, pos($<L>1), end($<L>3); leading(yylex, $$[0], $<L>1) }
                    ;
 
-IncomingPathPattern: LARROWSLASH PathSpecification RSLASHDASH  { $$ = $2; $$[0].Es[0].Dir = ast.Incoming }
+IncomingPathPattern: LARROWSLASH PathSpecification RSLASHDASH  { $$ = $2; e := $$[0].Es[0]; e.Dir, e.EdgePos, e.EndPos = ast.Incoming, pos($<L>1), end($<L>3); leading(yylex, $$[0], $<L>1) }
                    ;
 
 PathSpecification: LabelPredicate  { $$ = []*ast.PathPatternPrimary{{Es: []*ast.EdgePattern{{LabelAlts: $1, Reachability: true}}}} }
@@ -577,48 +611,50 @@ PathSpecification: LabelPredicate  { $$ = []*ast.PathPatternPrimary{{Es: []*ast.
 PathPredicate: ':' Label GraphPatternQuantifier  { $$ = []*ast.PathPatternPrimary{{Es: []*ast.EdgePattern{{LabelAlts: []*ast.Ident{$2}, Reachability: true}}, Quantity: $3}} }
              ;
 
-OptPathPatternMacros: /* empty */                            { $$ = nil }
-                    | OptPathPatternMacros PathPatternMacro  { $$ = append($1, $2[0]) }
+// The first token of the macros is needed to associate comments,
+// but an empty production has no token.
+OptPathPatternMacros: /* empty */                            { $$ = nil; $<L>$ = nil }
+                    | OptPathPatternMacros PathPatternMacro  { $$ = append($1, $2[0]); if len($1) == 0 { $<L>$ = $<L>2 } }
                     ;
 
-PathPatternMacro: PATH Identifier AS PathPattern OptWhereClause  { $$ = []*ast.PathMacroClause{{Name: $2, Pattern: $4[0], Where: $5}} }
+PathPatternMacro: PATH Identifier AS PathPattern OptWhereClause  { $$ = []*ast.PathMacroClause{leading(yylex, &ast.PathMacroClause{Name: $2, Pattern: $4[0], Where: $5, Path: pos($<L>1)}, $<L>1)} }
                 ;
 
-AnyShortestPathPattern: ANY SHORTEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AnyCardinality, Metric: ast.LengthMetric}} }
-                      | ANY SHORTEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.AnyCardinality, Metric: ast.LengthMetric}} }
+AnyShortestPathPattern: ANY SHORTEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AnyCardinality, Metric: ast.LengthMetric, CardPos: pos($<L>1)}, $<L>1)} }
+                      | ANY SHORTEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.AnyCardinality, Metric: ast.LengthMetric, CardPos: pos($<L>1), Rparen: pos($<L>7)}, $<L>1)} }
                       ;
 
-AllShortestPathPattern: ALL SHORTEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AllCardinality, Metric: ast.LengthMetric}} }
-                      | ALL SHORTEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.AllCardinality, Metric: ast.LengthMetric}} }
+AllShortestPathPattern: ALL SHORTEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AllCardinality, Metric: ast.LengthMetric, CardPos: pos($<L>1)}, $<L>1)} }
+                      | ALL SHORTEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.AllCardinality, Metric: ast.LengthMetric, CardPos: pos($<L>1), Rparen: pos($<L>7)}, $<L>1)} }
                       ;
 
 // The 1.5 spec is missing the SHORTEST keyword.
-TopKShortestPathPattern: TOP KValue SHORTEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.TopCardinality, K: $2, Metric: ast.LengthMetric}} }
-                       | TOP KValue SHORTEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($5, $7[0]), Es: $6, Cardinality: ast.TopCardinality, K: $2, Metric: ast.LengthMetric}} }
+TopKShortestPathPattern: TOP KValue SHORTEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.TopCardinality, K: $2, Metric: ast.LengthMetric, CardPos: pos($<L>1)}, $<L>1)} }
+                       | TOP KValue SHORTEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($5, $7[0]), Es: $6, Cardinality: ast.TopCardinality, K: $2, Metric: ast.LengthMetric, CardPos: pos($<L>1), Rparen: pos($<L>8)}, $<L>1)} }
                        ;
 
-KValue: UNSIGNED_INTEGER  { $$ = &ast.BasicLit{S: $1.S, Kind: ast.UIntKind} }
+KValue: UNSIGNED_INTEGER  { $$ = leading(yylex, uintLit($1), $<L>1) }
       ;
 
-AnyCheapestPathPattern: ANY CHEAPEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AnyCardinality, Metric: ast.CostMetric}} }
-                      | ANY CHEAPEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.AnyCardinality, Metric: ast.CostMetric}} }
+AnyCheapestPathPattern: ANY CHEAPEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AnyCardinality, Metric: ast.CostMetric, CardPos: pos($<L>1)}, $<L>1)} }
+                      | ANY CHEAPEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.AnyCardinality, Metric: ast.CostMetric, CardPos: pos($<L>1), Rparen: pos($<L>7)}, $<L>1)} }
                       ;
 
 OptCostClause: /* empty */  { $$ = nil }
              | CostClause
              ;
 
-CostClause: COST ValueExpression  { $$ = $2 }
+CostClause: COST ValueExpression  { $$ = leading(yylex, $2, $<L>1) }
           ;
 
 // The 1.5 spec is missing the CHEAPEST keyword.
-TopKCheapestPathPattern: TOP KValue CHEAPEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.TopCardinality, K: $2, Metric: ast.CostMetric}} }
-                       | TOP KValue CHEAPEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($5, $7[0]), Es: $6, Cardinality: ast.TopCardinality, K: $2, Metric: ast.CostMetric}} }
+TopKCheapestPathPattern: TOP KValue CHEAPEST SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($4, $6[0]), Es: $5, Cardinality: ast.TopCardinality, K: $2, Metric: ast.CostMetric, CardPos: pos($<L>1)}, $<L>1)} }
+                       | TOP KValue CHEAPEST '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($5, $7[0]), Es: $6, Cardinality: ast.TopCardinality, K: $2, Metric: ast.CostMetric, CardPos: pos($<L>1), Rparen: pos($<L>8)}, $<L>1)} }
                        ;
 
 // Quantifier must have an upper bound.
-AllPathPattern: ALL SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{{Vs: append($2, $4[0]), Es: $3, Cardinality: ast.AllCardinality}}; reportError(yylex, checkPathPattern($$)) }
-              | ALL '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AllCardinality}}; reportError(yylex, checkPathPattern($$)) }
+AllPathPattern: ALL SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern          { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($2, $4[0]), Es: $3, Cardinality: ast.AllCardinality, CardPos: pos($<L>1)}, $<L>1)}; reportError(yylex, checkPathPattern($$)) }
+              | ALL '(' SourceVertexPattern QuantifiedPathPatternPrimary DestinationVertexPattern ')'  { $$ = []*ast.PathPattern{leading(yylex, &ast.PathPattern{Vs: append($3, $5[0]), Es: $4, Cardinality: ast.AllCardinality, CardPos: pos($<L>1), Rparen: pos($<L>6)}, $<L>1)}; reportError(yylex, checkPathPattern($$)) }
               ;
 
 // Number of Rows Per Match
@@ -632,16 +668,16 @@ RowsPerMatch: OneRowPerMatch
             | OneRowPerStep
             ;
 
-OneRowPerMatch: ONE ROW PER MATCH  { $$ = &ast.MatchRows{Kind: ast.OneRowPerMatch} }
+OneRowPerMatch: ONE ROW PER MATCH  { $$ = &ast.MatchRows{Kind: ast.OneRowPerMatch, One: pos($<L>1), EndPos: end($<L>4)} }
               ;
 
-OneRowPerVertex: ONE ROW PER VERTEX '(' VertexVariable ')'  { $$ = &ast.MatchRows{Kind: ast.OneRowPerVertex, Vars: []*ast.Ident{$6}} }
+OneRowPerVertex: ONE ROW PER VERTEX '(' VertexVariable ')'  { $$ = &ast.MatchRows{Kind: ast.OneRowPerVertex, Vars: []*ast.Ident{$6}, One: pos($<L>1), EndPos: end($<L>7)} }
                ;
 
 VertexVariable: VariableName
               ;
 
-OneRowPerStep: ONE ROW PER STEP '(' VertexVariable1 ',' EdgeVariable ',' VertexVariable2 ')'  { $$ = &ast.MatchRows{Kind: ast.OneRowPerStep, Vars: []*ast.Ident{$6, $8, $10}} }
+OneRowPerStep: ONE ROW PER STEP '(' VertexVariable1 ',' EdgeVariable ',' VertexVariable2 ')'  { $$ = &ast.MatchRows{Kind: ast.OneRowPerStep, Vars: []*ast.Ident{$6, $8, $10}, One: pos($<L>1), EndPos: end($<L>11)} }
              ;
 
 VertexVariable1: VariableName
@@ -659,7 +695,8 @@ OptGroupByClause: /* empty */    { $$ = nil }
                 | GroupByClause
                 ;
 
-GroupByClause: GROUP BY ExpAsVarList  { $$ = $3 }
+GroupByClause: GROUP BY ExpAsVarList  { $$ = $3; leading(yylex, $3[0], $<L>1) }
+             | GROUP BY error SYNC    { $$ = nil }
              ;
 
 Aggregation: CountAggregation
@@ -671,26 +708,26 @@ Aggregation: CountAggregation
            | ListaggAggregation
            ;
 
-CountAggregation: COUNT '(' '*' ')'                          { $$ = &ast.OpExpr{Op: COUNT} }
-                | COUNT '(' OptDistinct ValueExpression ')'  { $$ = &ast.OpExpr{Op: COUNT, Args: []ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}, $4}} }
+CountAggregation: COUNT '(' '*' ')'                          { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.CountFunc, Star: true, FuncPos: pos($<L>1), Rparen: pos($<L>4)}, $<L>1) }
+                | COUNT '(' OptDistinct ValueExpression ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.CountFunc, Distinct: $3, Arg: $4, FuncPos: pos($<L>1), Rparen: pos($<L>5)}, $<L>1) }
                 ;
 
-MinAggregation: MIN '(' OptDistinct ValueExpression ')'  { $$ = &ast.OpExpr{Op: MIN, Args: []ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}, $4}} }
+MinAggregation: MIN '(' OptDistinct ValueExpression ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.MinFunc, Distinct: $3, Arg: $4, FuncPos: pos($<L>1), Rparen: pos($<L>5)}, $<L>1) }
               ;
 
-MaxAggregation: MAX '(' OptDistinct ValueExpression ')'  { $$ = &ast.OpExpr{Op: MAX, Args: []ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}, $4}} }
+MaxAggregation: MAX '(' OptDistinct ValueExpression ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.MaxFunc, Distinct: $3, Arg: $4, FuncPos: pos($<L>1), Rparen: pos($<L>5)}, $<L>1) }
               ;
 
-AvgAggregation: AVG '(' OptDistinct ValueExpression ')'  { $$ = &ast.OpExpr{Op: AVG, Args: []ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}, $4}} }
+AvgAggregation: AVG '(' OptDistinct ValueExpression ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.AvgFunc, Distinct: $3, Arg: $4, FuncPos: pos($<L>1), Rparen: pos($<L>5)}, $<L>1) }
               ;
 
-SumAggregation: SUM '(' OptDistinct ValueExpression ')'  { $$ = &ast.OpExpr{Op: SUM, Args: []ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}, $4}} }
+SumAggregation: SUM '(' OptDistinct ValueExpression ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.SumFunc, Distinct: $3, Arg: $4, FuncPos: pos($<L>1), Rparen: pos($<L>5)}, $<L>1) }
               ;
 
-ArrayAggregation: ARRAY_AGG '(' OptDistinct ValueExpression ')'  { $$ = &ast.OpExpr{Op: ARRAY_AGG, Args: []ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}, $4}} }
+ArrayAggregation: ARRAY_AGG '(' OptDistinct ValueExpression ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.ArrayAggFunc, Distinct: $3, Arg: $4, FuncPos: pos($<L>1), Rparen: pos($<L>5)}, $<L>1) }
                 ;
 
-ListaggAggregation: LISTAGG '(' OptDistinct ValueExpression OptListaggSeparator ')'  { $$ = &ast.OpExpr{Op: LISTAGG, Args: append([]ast.Expr{&ast.BasicLit{S: fmt.Sprint($3), Kind: ast.BoolKind}}, $4, $5)} }
+ListaggAggregation: LISTAGG '(' OptDistinct ValueExpression OptListaggSeparator ')'  { $$ = leading(yylex, &ast.AggregateExpr{Func: ast.ListaggFunc, Distinct: $3, Arg: $4, Separator: $5, FuncPos: pos($<L>1), Rparen: pos($<L>6)}, $<L>1) }
                   ;
 
 OptListaggSeparator: /* empty */       { $$ = nil }
@@ -704,7 +741,8 @@ OptHavingClause: /* empty */   { $$ = nil }
                | HavingClause
                ;
 
-HavingClause: HAVING ValueExpression  { $$ = $2 }
+HavingClause: HAVING ValueExpression  { $$ = leading(yylex, $2, $<L>1) }
+            | HAVING error SYNC       { $$ = nil }
             ;
 
 // Sorting and Row Limiting
@@ -713,16 +751,17 @@ OptOrderByClause: /* empty */    { $$ = nil }
                 | OrderByClause
                 ;
 
-OrderByClause: ORDER BY OrderTermList  { $$ = $3 }
+OrderByClause: ORDER BY OrderTermList  { $$ = $3; leading(yylex, $3[0], $<L>1) }
+             | ORDER BY error SYNC     { $$ = nil }
              ;
 
 OrderTermList: OrderTerm
              | OrderTermList ',' OrderTerm  { $$ = append($1, $3[0]) }
              ;
 
-OrderTerm: ValueExpression       { $$ = []*ast.OrderTerm{{Expr: $1, Order: ast.DefaultOrder}} }
-         | ValueExpression ASC   { $$ = []*ast.OrderTerm{{Expr: $1, Order: ast.AscOrder}} }
-         | ValueExpression DESC  { $$ = []*ast.OrderTerm{{Expr: $1, Order: ast.DescOrder}} }
+OrderTerm: ValueExpression       { $$ = []*ast.OrderTerm{leading(yylex, &ast.OrderTerm{Expr: $1, Order: ast.DefaultOrder}, $<L>1)} }
+         | ValueExpression ASC   { $$ = []*ast.OrderTerm{leading(yylex, &ast.OrderTerm{Expr: $1, Order: ast.AscOrder, OrderPos: pos($<L>2)}, $<L>1)} }
+         | ValueExpression DESC  { $$ = []*ast.OrderTerm{leading(yylex, &ast.OrderTerm{Expr: $1, Order: ast.DescOrder, OrderPos: pos($<L>2)}, $<L>1)} }
          ;
 
 OptLimitOffsetClauses: /* empty */         { $$ = []ast.Expr{nil, nil} }
@@ -735,13 +774,13 @@ LimitOffsetClauses: LimitClause OffsetClause  { $$ = []ast.Expr{$1, $2} }
                   | OffsetClause              { $$ = []ast.Expr{nil, $1} }
                   ;
 
-LimitClause: LIMIT LimitOffsetValue  { $$ = $2 }
+LimitClause: LIMIT LimitOffsetValue  { $$ = leading(yylex, $2, $<L>1) }
            ;
 
-OffsetClause: OFFSET LimitOffsetValue  { $$ = $2 }
+OffsetClause: OFFSET LimitOffsetValue  { $$ = leading(yylex, $2, $<L>1) }
             ;
 
-LimitOffsetValue: UNSIGNED_INTEGER  { $$ = &ast.BasicLit{S: $1.S, Kind: ast.UIntKind, Pos: ast.Pos($1.P.Offset)} }
+LimitOffsetValue: UNSIGNED_INTEGER  { $$ = leading(yylex, uintLit($1), $<L>1) }
                 | BindVariable
                 ;
 
@@ -778,13 +817,14 @@ VariableReference: VariableName
                  ;
 
 // The 1.5 spec uses VariableReference. We use Identifier to solve a conflict with FunctionInvocation.
-PropertyAccess: Identifier '.' PropertyName  { $$ = &ast.QIdent{Names: []*ast.Ident{$1, $3}} }
+PropertyAccess: Identifier '.' PropertyName  { $$ = leading(yylex, &ast.QIdent{Names: []*ast.Ident{$1, $3}}, $<L>1) }
               ;
 
-BracketedValueExpression: '(' ValueExpression ')'  { $$ = $2 }
+BracketedValueExpression: '(' ValueExpression ')'  { $$ = leading(yylex, $2, $<L>1) }
                         ;
 
-// Time literals use STRING_LITERAL and must validate the string.
+// Time literals use STRING_LITERAL, and the string is validated by
+// checkLit.
 Literal: StringLiteral
        | NumericLiteral
        | BooleanLiteral
@@ -794,28 +834,28 @@ Literal: StringLiteral
        | IntervalLiteral
        ;
 
-StringLiteral: STRING_LITERAL  { $$ = &ast.BasicLit{S: $1.S, Kind: ast.StringKind, Pos: ast.Pos($1.P.Offset)} }
+StringLiteral: STRING_LITERAL  { $$ = leading(yylex, stringLit($1), $<L>1) }
              ;
 
-NumericLiteral: UNSIGNED_INTEGER  { $$ = &ast.BasicLit{S: $1.S, Kind: ast.UIntKind, Pos: ast.Pos($1.P.Offset)} }
-              | UNSIGNED_DECIMAL  { $$ = &ast.BasicLit{S: $1.S, Kind: ast.UDecKind, Pos: ast.Pos($1.P.Offset)} }
+NumericLiteral: UNSIGNED_INTEGER  { $$ = leading(yylex, uintLit($1), $<L>1) }
+              | UNSIGNED_DECIMAL  { $$ = leading(yylex, &ast.BasicLit{S: $1.S, Kind: ast.UDecKind, ValuePos: pos($1), EndPos: end($1)}, $<L>1) }
               ;
 
-// These are lower-case to match fmt.Sprint(true).
-BooleanLiteral: TRUE   { $$ = &ast.BasicLit{S: "true", Kind: ast.BoolKind, Pos: ast.Pos($1.P.Offset)} }
-              | FALSE  { $$ = &ast.BasicLit{S: "false", Kind: ast.BoolKind, Pos: ast.Pos($1.P.Offset)} }
+// These are lower-case to match strconv.FormatBool.
+BooleanLiteral: TRUE   { $$ = leading(yylex, &ast.BasicLit{S: "true", Kind: ast.BoolKind, ValuePos: pos($1), EndPos: end($1)}, $<L>1) }
+              | FALSE  { $$ = leading(yylex, &ast.BasicLit{S: "false", Kind: ast.BoolKind, ValuePos: pos($1), EndPos: end($1)}, $<L>1) }
               ;
 
-DateLiteral: DATE STRING_LITERAL  { $$ = &ast.BasicLit{S: $2.S, Kind: ast.DateKind, Pos: ast.Pos($2.P.Offset)} }
+DateLiteral: DATE STRING_LITERAL  { $$ = leading(yylex, stringLit($2), $<L>1); $$.Kind, $$.ValuePos = ast.DateKind, pos($<L>1); reportErrorAt(yylex, $2, checkLit($$)) }
            ;
 
-TimeLiteral: TIME STRING_LITERAL  { $$ = &ast.BasicLit{S: $2.S, Kind: ast.TimeKind, Pos: ast.Pos($2.P.Offset)} }
+TimeLiteral: TIME STRING_LITERAL  { $$ = leading(yylex, stringLit($2), $<L>1); $$.Kind, $$.ValuePos = ast.TimeKind, pos($<L>1); reportErrorAt(yylex, $2, checkLit($$)) }
            ;
 
-TimestampLiteral: TIMESTAMP STRING_LITERAL  { $$ = &ast.BasicLit{S: $2.S, Kind: ast.TimestampKind, Pos: ast.Pos($2.P.Offset)} }
+TimestampLiteral: TIMESTAMP STRING_LITERAL  { $$ = leading(yylex, stringLit($2), $<L>1); $$.Kind, $$.ValuePos = ast.TimestampKind, pos($<L>1); reportErrorAt(yylex, $2, checkLit($$)) }
                 ;
 
-IntervalLiteral: INTERVAL StringLiteral DateTimeField  { $$ = $2; $$.S = $2.S + " " + $3.S; $$.Kind = ast.IntervalKind }
+IntervalLiteral: INTERVAL StringLiteral DateTimeField  { $$ = leading(yylex, $2, $<L>1); $$.S = $2.S + " " + $3.S; $$.Kind = ast.IntervalKind; $$.ValuePos, $$.EndPos = pos($<L>1), end($<L>3); reportErrorAt(yylex, $<L>2, checkLit($$)) }
                ;
 
 DateTimeField: YEAR    { $$ = &ast.BasicLit{S: "YEAR"} }
@@ -826,7 +866,7 @@ DateTimeField: YEAR    { $$ = &ast.BasicLit{S: "YEAR"} }
              | SECOND  { $$ = &ast.BasicLit{S: "SECOND"} }
              ;
 
-BindVariable: '?'  { $$ = &ast.BindVar{} }
+BindVariable: '?'  { $$ = leading(yylex, &ast.BindVar{Index: nextBindVar(yylex), Qmark: pos($<L>1)}, $<L>1) }
             ;
 
 ArithmeticExpression: UnaryMinus
@@ -837,25 +877,25 @@ ArithmeticExpression: UnaryMinus
                     | Subtraction
                     ;
 
-UnaryMinus: '-' ValueExpression  %prec UMINUS  { $$ = &ast.OpExpr{Op: '-', Args: []ast.Expr{$2}} }
+UnaryMinus: '-' ValueExpression  %prec UMINUS  { $$ = leading(yylex, &ast.OpExpr{Op: ast.NegOp, Args: []ast.Expr{$2}, OpPos: pos($<L>1)}, $<L>1) }
           ;
 
-StringConcat: ValueExpression DPIPE ValueExpression  { $$ = &ast.OpExpr{Op: DPIPE, Args: []ast.Expr{$1, $3}} }
+StringConcat: ValueExpression DPIPE ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.ConcatOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
             ;
 
-Multiplication: ValueExpression '*' ValueExpression  { $$ = &ast.OpExpr{Op: '*', Args: []ast.Expr{$1, $3}} }
+Multiplication: ValueExpression '*' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.MulOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
               ;
 
-Division: ValueExpression '/' ValueExpression  { $$ = &ast.OpExpr{Op: '/', Args: []ast.Expr{$1, $3}} }
+Division: ValueExpression '/' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.DivOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
         ;
 
-Modulo: ValueExpression '%' ValueExpression  { $$ = &ast.OpExpr{Op: '%', Args: []ast.Expr{$1, $3}} }
+Modulo: ValueExpression '%' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.ModOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
       ;
 
-Addition: ValueExpression '+' ValueExpression  { $$ = &ast.OpExpr{Op: '+', Args: []ast.Expr{$1, $3}} }
+Addition: ValueExpression '+' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.AddOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
         ;
 
-Subtraction: ValueExpression '-' ValueExpression  { $$ = &ast.OpExpr{Op: '-', Args: []ast.Expr{$1, $3}} }
+Subtraction: ValueExpression '-' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.SubOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
            ;
 
 RelationalExpression: Equal
@@ -866,22 +906,22 @@ RelationalExpression: Equal
                     | LessOrEqual
                     ;
 
-Equal: ValueExpression '=' ValueExpression  { $$ = &ast.OpExpr{Op: '=', Args: []ast.Expr{$1, $3}} }
+Equal: ValueExpression '=' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.EqOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
      ;
 
-NotEqual: ValueExpression LTGT ValueExpression  { $$ = &ast.OpExpr{Op: LTGT, Args: []ast.Expr{$1, $3}} }
+NotEqual: ValueExpression LTGT ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.NeOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
         ;
 
-Greater: ValueExpression '>' ValueExpression  { $$ = &ast.OpExpr{Op: '>', Args: []ast.Expr{$1, $3}} }
+Greater: ValueExpression '>' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.GtOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
        ;
 
-Less: ValueExpression '<' ValueExpression  { $$ = &ast.OpExpr{Op: '<', Args: []ast.Expr{$1, $3}} }
+Less: ValueExpression '<' ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.LtOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
     ;
 
-GreaterOrEqual: ValueExpression GTEQ ValueExpression  { $$ = &ast.OpExpr{Op: GTEQ, Args: []ast.Expr{$1, $3}} }
+GreaterOrEqual: ValueExpression GTEQ ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.GeOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
               ;
 
-LessOrEqual: ValueExpression LTEQ ValueExpression  { $$ = &ast.OpExpr{Op: LTEQ, Args: []ast.Expr{$1, $3}} }
+LessOrEqual: ValueExpression LTEQ ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.LeOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
            ;
 
 LogicalExpression: Not
@@ -889,23 +929,23 @@ LogicalExpression: Not
                  | Or
                  ;
 
-Not: NOT ValueExpression  { $$ = &ast.OpExpr{Op: NOT, Args: []ast.Expr{$2}} }
+Not: NOT ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.NotOp, Args: []ast.Expr{$2}, OpPos: pos($<L>1)}, $<L>1) }
    ;
 
-And: ValueExpression AND ValueExpression  { $$ = &ast.OpExpr{Op: AND, Args: []ast.Expr{$1, $3}} }
+And: ValueExpression AND ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.AndOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
    ;
 
-Or: ValueExpression OR ValueExpression  { $$ = &ast.OpExpr{Op: OR, Args: []ast.Expr{$1, $3}} }
+Or: ValueExpression OR ValueExpression  { $$ = leading(yylex, &ast.OpExpr{Op: ast.OrOp, Args: []ast.Expr{$1, $3}, OpPos: pos($<L>2)}, $<L>1) }
   ;
 
-IsNullPredicate: ValueExpression IS NULL  { $$ = &ast.OpExpr{Op: NULL, Args: []ast.Expr{$1}} }
+IsNullPredicate: ValueExpression IS NULL  { $$ = leading(yylex, &ast.IsNullExpr{Arg: $1, Is: pos($<L>2), Null: pos($<L>3)}, $<L>1) }
                ;
 
-IsNotNullPredicate: ValueExpression IS NOT NULL  { $$ = &ast.OpExpr{Op: NOT_NULL, Args: []ast.Expr{$1}} }
+IsNotNullPredicate: ValueExpression IS NOT NULL  { $$ = leading(yylex, &ast.IsNullExpr{Arg: $1, Not: true, Is: pos($<L>2), Null: pos($<L>4)}, $<L>1) }
                   ;
 
-CharacterSubstring: SUBSTRING '(' ValueExpression FROM StartPosition FOR StringLength ')'  { $$ = &ast.OpExpr{Op: SUBSTRING, Args: []ast.Expr{$3, $5, $7}} }
-                  | SUBSTRING '(' ValueExpression FROM StartPosition ')'                   { $$ = &ast.OpExpr{Op: SUBSTRING, Args: []ast.Expr{$3, $5}} }
+CharacterSubstring: SUBSTRING '(' ValueExpression FROM StartPosition FOR StringLength ')'  { $$ = leading(yylex, &ast.SubstringExpr{Arg: $3, Start: $5, Length: $7, Substring: pos($<L>1), Rparen: pos($<L>8)}, $<L>1) }
+                  | SUBSTRING '(' ValueExpression FROM StartPosition ')'                   { $$ = leading(yylex, &ast.SubstringExpr{Arg: $3, Start: $5, Substring: pos($<L>1), Rparen: pos($<L>6)}, $<L>1) }
                   ;
 
 StartPosition: ValueExpression
@@ -914,24 +954,24 @@ StartPosition: ValueExpression
 StringLength: ValueExpression
             ;
 
-ExtractFunction: EXTRACT '(' ExtractField FROM ValueExpression ')'  { $$ = &ast.OpExpr{Op: EXTRACT, Args: []ast.Expr{$3, $5}} }
+ExtractFunction: EXTRACT '(' ExtractField FROM ValueExpression ')'  { $$ = leading(yylex, &ast.ExtractExpr{Field: $3, Arg: $5, Extract: pos($<L>1), Rparen: pos($<L>6)}, $<L>1) }
                ;
 
-ExtractField: YEAR             { $$ = &ast.Ident{Name: "YEAR"} }
-            | MONTH            { $$ = &ast.Ident{Name: "MONTH"} }
-            | DAY              { $$ = &ast.Ident{Name: "DAY"} }
-            | HOUR             { $$ = &ast.Ident{Name: "HOUR"} }
-            | MINUTE           { $$ = &ast.Ident{Name: "MINUTE"} }
-            | SECOND           { $$ = &ast.Ident{Name: "SECOND"} }
-            | TIMEZONE_HOUR    { $$ = &ast.Ident{Name: "TIMEZONE_HOUR"} }
-            | TIMEZONE_MINUTE  { $$ = &ast.Ident{Name: "TIMEZONE_MINUTE"} }
+ExtractField: YEAR             { $$ = ast.YearField }
+            | MONTH            { $$ = ast.MonthField }
+            | DAY              { $$ = ast.DayField }
+            | HOUR             { $$ = ast.HourField }
+            | MINUTE           { $$ = ast.MinuteField }
+            | SECOND           { $$ = ast.SecondField }
+            | TIMEZONE_HOUR    { $$ = ast.TimezoneHourField }
+            | TIMEZONE_MINUTE  { $$ = ast.TimezoneMinuteField }
             ;
 
 // The 1.5 spec uses PackageName. We use Identifier to solve a conflict with PropertyAccess.
-FunctionInvocation: FunctionName '(' OptArgumentList ')'                 { $$ = &ast.CallExpr{Func: &ast.QIdent{Names: []*ast.Ident{$1}}, Args: $3} }
-                  | Identifier '.' FunctionName '(' OptArgumentList ')'  { $$ = &ast.CallExpr{Func: &ast.QIdent{Names: []*ast.Ident{$1, $3}}, Args: $5} }
-                  | LABEL '(' OptArgumentList ')'                        { $$ = &ast.OpExpr{Op: LABEL, Args: $3} }
-                  | LABELS '(' OptArgumentList ')'                       { $$ = &ast.OpExpr{Op: LABELS, Args: $3} }
+FunctionInvocation: FunctionName '(' OptArgumentList ')'                 { $$ = leading(yylex, &ast.CallExpr{Func: &ast.QIdent{Names: []*ast.Ident{$1}}, Args: $3, Rparen: pos($<L>4)}, $<L>1) }
+                  | Identifier '.' FunctionName '(' OptArgumentList ')'  { $$ = leading(yylex, &ast.CallExpr{Func: &ast.QIdent{Names: []*ast.Ident{$1, $3}}, Args: $5, Rparen: pos($<L>6)}, $<L>1) }
+                  | LABEL '(' ValueExpression ')'                        { $$ = leading(yylex, &ast.LabelExpr{Arg: $3, FuncPos: pos($<L>1), Rparen: pos($<L>4)}, $<L>1) }
+                  | LABELS '(' ValueExpression ')'                       { $$ = leading(yylex, &ast.LabelExpr{Arg: $3, Plural: true, FuncPos: pos($<L>1), Rparen: pos($<L>4)}, $<L>1) }
                   ;
 
 FunctionName: Identifier
@@ -945,55 +985,56 @@ ArgumentList: ValueExpression                   { $$ = []ast.Expr{$1} }
             | ArgumentList ',' ValueExpression  { $$ = append($1, $3) }
             ;
 
-CastSpecification: CAST '(' ValueExpression AS DataType ')'  { $$ = &ast.CastExpr{Arg: $3, TypeKind: $5} }
+CastSpecification: CAST '(' ValueExpression AS DataType ')'  { $$ = leading(yylex, &ast.CastExpr{Arg: $3, TypeKind: $5, Cast: pos($<L>1), Rparen: pos($<L>6)}, $<L>1) }
                  ;
 
-DataType: STRING                    { $$ = STRING }
-        | BOOLEAN                   { $$ = BOOLEAN }
-        | INTEGER                   { $$ = INTEGER }
-        | INT                       { $$ = INT }
-        | LONG                      { $$ = LONG }
-        | FLOAT                     { $$ = FLOAT }
-        | DOUBLE                    { $$ = DOUBLE }
-        | DATE                      { $$ = DATE }
-        | TIME                      { $$ = TIME }
-        | TIME WITH TIME ZONE       { $$ = TIME_TZ }
-        | TIMESTAMP                 { $$ = TIMESTAMP }
-        | TIMESTAMP WITH TIME ZONE  { $$ = TIMESTAMP_TZ }
+DataType: STRING                    { $$ = ast.StringType }
+        | BOOLEAN                   { $$ = ast.BooleanType }
+        | INTEGER                   { $$ = ast.IntegerType }
+        | INT                       { $$ = ast.IntType }
+        | LONG                      { $$ = ast.LongType }
+        | FLOAT                     { $$ = ast.FloatType }
+        | DOUBLE                    { $$ = ast.DoubleType }
+        | DATE                      { $$ = ast.DateType }
+        | TIME                      { $$ = ast.TimeType }
+        | TIME WITH TIME ZONE       { $$ = ast.TimeTZType }
+        | TIMESTAMP                 { $$ = ast.TimestampType }
+        | TIMESTAMP WITH TIME ZONE  { $$ = ast.TimestampTZType }
         ;
 
 CaseExpression: SimpleCase
               | SearchedCase
               ;
 
-SimpleCase: CASE ValueExpression WhenClauseList OptElseClause END  { $$ = &ast.CaseExpr{Subject: $2, Whens: $3, Else: $4} }
+SimpleCase: CASE ValueExpression WhenClauseList OptElseClause END  { $$ = leading(yylex, &ast.CaseExpr{Subject: $2, Whens: $3, Else: $4, Case: pos($<L>1), EndPos: end($<L>5)}, $<L>1) }
           ;
 
-SearchedCase: CASE WhenClauseList OptElseClause END  { $$ = &ast.CaseExpr{Whens: $2, Else: $3} }
+SearchedCase: CASE WhenClauseList OptElseClause END  { $$ = leading(yylex, &ast.CaseExpr{Whens: $2, Else: $3, Case: pos($<L>1), EndPos: end($<L>4)}, $<L>1) }
             ;
 
 WhenClauseList: WhenClause
               | WhenClauseList WhenClause  { $$ = append($1, $2[0]) }
               ;
 
-WhenClause: WHEN ValueExpression THEN ValueExpression  { $$ = []*ast.WhenClause{{Cond: $2, Then: $4}} }
+WhenClause: WHEN ValueExpression THEN ValueExpression  { $$ = []*ast.WhenClause{{Cond: leading(yylex, $2, $<L>1), Then: $4, When: pos($<L>1)}} }
           ;
 
 OptElseClause: /* empty */  { $$ = nil }
              | ElseClause
              ;
 
-ElseClause: ELSE ValueExpression  { $$ = $2 }
+ElseClause: ELSE ValueExpression  { $$ = leading(yylex, $2, $<L>1) }
           ;
 
-InPredicate: ValueExpression IN InValueList  { $$ = &ast.InExpr{Subject: $1, Objects: $3} }
+InPredicate: ValueExpression IN InValueList  { x := $3.(*ast.InExpr); x.Subject = $1; $$ = leading(yylex, x, $<L>1) }
            ;
 
-NotInPredicate: ValueExpression NOT IN InValueList  { $$ = &ast.InExpr{Subject: $1, Objects: $4, Inv: true} }
+NotInPredicate: ValueExpression NOT IN InValueList  { x := $4.(*ast.InExpr); x.Subject, x.Inv = $1, true; $$ = leading(yylex, x, $<L>1) }
               ;
 
-InValueList: '(' ValueExpressionList ')'  { $$ = $2 }
-           | BindVariable                 { $$ = nil }
+// An InExpr without Subject.
+InValueList: '(' ValueExpressionList ')'  { $$ = &ast.InExpr{Objects: $2, Rparen: pos($<L>3)} }
+           | BindVariable                 { $$ = &ast.InExpr{BindVar: $1.(*ast.BindVar)} }
            ;
 
 ValueExpressionList: ValueExpression                          { $$ = []ast.Expr{$1} }
@@ -1002,11 +1043,11 @@ ValueExpressionList: ValueExpression                          { $$ = []ast.Expr{
 
 // Subqueries
 
-ExistsPredicate: EXISTS Subquery  { $$ = &ast.OpExpr{Op: EXISTS, Args: []ast.Expr{$2}} }
+ExistsPredicate: EXISTS Subquery  { $$ = leading(yylex, &ast.ExistsExpr{Query: $2.(*ast.SubqueryExpr), Exists: pos($<L>1)}, $<L>1) }
                ;
 
 // The 1.5 spec uses Query, which would allow ModifyQuery.
-Subquery: '(' SelectQuery ')'  { $$ = &ast.SubqueryExpr{Query: $2[0].(*ast.SelectStmt)} }
+Subquery: '(' SelectQuery ')'  { $$ = leading(yylex, &ast.SubqueryExpr{Query: $2[0].(*ast.SelectStmt), Lparen: pos($<L>1), Rparen: pos($<L>3)}, $<L>1) }
         ;
 
 ScalarSubquery: Subquery
@@ -1021,7 +1062,7 @@ ModifyQuery: ModifyQueryFull
 // allow an InsertClause alone. This creates a conflict. Parser code
 // must validate that if FromClause is missing, then ModificationList
 // is a single InsertClause and no other rules are present.
-ModifyQueryFull: OptPathPatternMacros ModificationList OptFromClause OptWhereClause OptGroupByClause OptHavingClause OptOrderByClause OptLimitOffsetClauses  { stmt := &ast.ModifyStmt{PathMacros: $1, Mods: $2, From: $3, Where: $4, GroupBy: $5, Having: $6, OrderBy: $7, Limit: $8[0], Offset: $8[1]}; $$ = []ast.Stmt{stmt}; reportError(yylex, checkModifyQuerySimple(stmt)) }
+ModifyQueryFull: OptPathPatternMacros ModificationList OptFromClause OptWhereClause OptGroupByClause OptHavingClause OptOrderByClause OptLimitOffsetClauses  { stmt := &ast.ModifyStmt{PathMacros: $1, Mods: $2, From: $3, Where: $4, GroupBy: $5, Having: $6, OrderBy: $7, Limit: $8[0], Offset: $8[1]}; $$ = []ast.Stmt{leading(yylex, leading(yylex, stmt, $<L>2), $<L>1)}; reportError(yylex, checkModifyQuerySimple(stmt)) }
                ;
 
 ModificationList: Modification
@@ -1033,7 +1074,7 @@ Modification: InsertClause
             | DeleteClause
             ;
 
-InsertClause: INSERT OptIntoClause GraphElementInsertionList  { $$ = []ast.ModClause{&ast.InsertClause{Into: $2, Vs: $3.Vs, Es: $3.Es}} }
+InsertClause: INSERT OptIntoClause GraphElementInsertionList  { $$ = []ast.ModClause{leading(yylex, &ast.InsertClause{Into: $2, Vs: $3.Vs, Es: $3.Es, Insert: pos($<L>1)}, $<L>1)} }
             ;
 
 GraphElementInsertionList: GraphElementInsertion
@@ -1044,32 +1085,32 @@ OptIntoClause: /* empty */  { $$ = nil }
              | IntoClause
              ;
 
-IntoClause: INTO GraphName  { $$ = $2 }
+IntoClause: INTO GraphName  { $$ = leading(yylex, $2, $<L>1) }
           ;
 
-GraphElementInsertion: VERTEX OptVariableName LabelsAndProperties                                            { $$ = &ast.InsertClause{Vs: []*ast.VertexInsertion{{Var: $2, Labels: $3.Vs[0].Labels, Props: $3.Vs[0].Props}}} }
-                     | EDGE OptVariableName BETWEEN VertexReference AND VertexReference LabelsAndProperties  { $$ = &ast.InsertClause{Es: []*ast.EdgeInsertion{{Var: $2, Source: $4, Dest: $6, Labels: $7.Vs[0].Labels, Props: $7.Vs[0].Props}}} }
+GraphElementInsertion: VERTEX OptVariableName LabelsAndProperties                                            { $$ = &ast.InsertClause{Vs: []*ast.VertexInsertion{{Var: $2, Labels: $3.Vs[0].Labels, Props: $3.Vs[0].Props, Vertex: pos($<L>1), Rparen: $3.Vs[0].Rparen}}} }
+                     | EDGE OptVariableName BETWEEN VertexReference AND VertexReference LabelsAndProperties  { $$ = &ast.InsertClause{Es: []*ast.EdgeInsertion{{Var: $2, Source: $4, Dest: $6, Labels: $7.Vs[0].Labels, Props: $7.Vs[0].Props, Edge: pos($<L>1), Rparen: $7.Vs[0].Rparen}}} }
                      ;
 
 VertexReference: Identifier
                ;
 
 // Reusing VertexInsertion to carry Labels and Props together.
-LabelsAndProperties: OptLabelSpecification OptPropertiesSpecification  { $$ =  &ast.InsertClause{Vs: []*ast.VertexInsertion{{Labels: $1, Props: $2}}} }
+LabelsAndProperties: OptLabelSpecification OptPropertiesSpecification  { $$ = &ast.InsertClause{Vs: []*ast.VertexInsertion{{Labels: $1, Props: $2, Rparen: $<Pos>1}}}; if $2 != nil { $$.Vs[0].Rparen = $<Pos>2 } }
                    ;
 
-OptLabelSpecification: /* empty */         { $$ = nil }
+OptLabelSpecification: /* empty */         { $$ = nil; $<Pos>$ = ast.NoPos }
                      | LabelSpecification
                      ;
 
-LabelSpecification: LABELS '(' LabelList ')'  { $$ = $3 }
+LabelSpecification: LABELS '(' LabelList ')'  { $$ = $3; $<Pos>$ = pos($<L>4) }
                   ;
 
 OptPropertiesSpecification: /* empty */              { $$ = nil }
                           | PropertiesSpecification
                           ;
 
-PropertiesSpecification: PROPERTIES '(' PropertyAssignmentList ')'  { $$ = $3 }
+PropertiesSpecification: PROPERTIES '(' PropertyAssignmentList ')'  { $$ = $3; $<Pos>$ = pos($<L>4) }
                        ;
 
 PropertyAssignmentList: PropertyAssignment
@@ -1079,21 +1120,27 @@ PropertyAssignmentList: PropertyAssignment
 PropertyAssignment: PropertyAccess '=' ValueExpression  { $$ = []*ast.PropAssignment{{Prop: $1, Value: $3}} }
                   ;
 
-UpdateClause: UPDATE GraphElementUpdateList  { $$ = []ast.ModClause{&ast.UpdateClause{Updates: $2}} }
+UpdateClause: UPDATE GraphElementUpdateList  { $$ = []ast.ModClause{leading(yylex, &ast.UpdateClause{Updates: $2, Update: pos($<L>1)}, $<L>1)} }
             ;
 
 GraphElementUpdateList: GraphElementUpdate                             { $$ = $1 }
                       | GraphElementUpdateList ',' GraphElementUpdate  { $$ = append($1, $3[0]) }
                       ;
 
-GraphElementUpdate: VariableReference SET '(' PropertyAssignmentList ')'  { $$ = []*ast.Update{{Var: $1, Props: $4}} }
+GraphElementUpdate: VariableReference SET '(' PropertyAssignmentList ')'  { $$ = []*ast.Update{{Var: $1, Props: $4, Rparen: pos($<L>5)}} }
                   ;
 
-DeleteClause: DELETE VariableReferenceList  { $$ = []ast.ModClause{&ast.DeleteClause{Vars: $2}} }
+DeleteClause: DELETE VariableReferenceList  { $$ = []ast.ModClause{leading(yylex, &ast.DeleteClause{Vars: $2, Delete: pos($<L>1)}, $<L>1)} }
             ;
 
 // Other Syntactic Rules
 
-Identifier: UNQUOTED_IDENTIFIER  { $$ = &ast.Ident{Name: $1.S, Pos: ast.Pos($1.P.Offset)} }
-          | QUOTED_IDENTIFIER    { $$ = &ast.Ident{Name: token.UnquoteIdentifier($1.S), Pos: ast.Pos($1.P.Offset)} }
+Identifier: UNQUOTED_IDENTIFIER  { $$ = leading(yylex, &ast.Ident{Name: $1.S, NamePos: pos($1), EndPos: end($1)}, $<L>1) }
+          | QUOTED_IDENTIFIER    { $$ = leading(yylex, &ast.Ident{Name: token.UnquoteIdentifier($1.S), NamePos: pos($1), EndPos: end($1)}, $<L>1) }
+          | NonReservedKeyword
           ;
+
+// NonReservedKeyword are keywords that can be used as identifiers.
+NonReservedKeyword: FOR        { $$ = leading(yylex, &ast.Ident{Name: $<L>1.S, NamePos: pos($<L>1), EndPos: end($<L>1)}, $<L>1) }
+                  | SUBSTRING  { $$ = leading(yylex, &ast.Ident{Name: $<L>1.S, NamePos: pos($<L>1), EndPos: end($<L>1)}, $<L>1) }
+                  ;
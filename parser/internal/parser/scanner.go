@@ -1,35 +1,57 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"unicode"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
 )
 
 // scanner splits a Reader into tokens.
 type scanner struct {
 	r    RuneReader
 	la   []rune
-	errs []error
+	errs ErrorList
 	pos  Position
+	file *ast.File
+
+	// rerr is true after the reader failed. The scanner then behaves
+	// as if the input ended.
+	rerr bool
+
+	// commented holds the tokens with comments, in order.
+	commented []*lexValue
 }
 
 // newScanner creates a new scanner using the Reader.
 func newScanner(r RuneReader) *scanner {
-	return &scanner{r: r}
+	return &scanner{r: r, file: ast.NewFile("")}
 }
 
-// Errors returns the errors encountered by the scanner.
-func (s *scanner) Errors() []error {
-	return s.errs
+// error records an error at the position.
+func (s *scanner) error(pos Position, err error) {
+	s.errs = append(s.errs, &Error{Pos: pos, Msg: err.Error()})
 }
 
 // Lex implements yyLexer and returns the next token.
 func (s *scanner) Lex(lval *yySymType) int {
+	tok := s.lex(lval)
+	lval.L.E = s.pos
+	return tok
+}
+
+// lex reads the next token. The end position is set by Lex.
+func (s *scanner) lex(lval *yySymType) int {
 	v := lexValue{P: s.pos}
 	lval.L = &v
 
+	// lastComment is the index in v.PreWS after the last comment.
+	lastComment := -1
+
 	for {
 		switch s.peekRune() {
 		case eof:
@@ -44,6 +66,7 @@ func (s *scanner) Lex(lval *yySymType) int {
 			return int(s.readRune())
 
 		case '/':
+			start := s.pos
 			s.readRune()
 			r := s.peekRune()
 			if r == bad {
@@ -53,21 +76,31 @@ func (s *scanner) Lex(lval *yySymType) int {
 			case '*': // Block comment.
 				v.PreWS = append(v.PreWS, '/', s.readRune())
 				ss, err := s.readWhile(func(r rune) bool { return r != '*' })
-				if err != nil {
-					s.errs = append(s.errs, err)
+				if err == io.EOF {
+					s.error(start, errors.New("unterminated comment"))
+					return bad
+				} else if err != nil {
+					s.error(start, err)
 					return bad
 				}
 				v.PreWS = append(v.PreWS, []rune(ss)...)
 				r := s.readRune()
+				if r == eof {
+					s.error(start, errors.New("unterminated comment"))
+					return bad
+				}
+				p := s.pos
 				r2 := s.readRune()
 				if r2 == bad {
 					return bad
 				} else if r2 != '/' {
-					s.errs = append(s.errs, fmt.Errorf("unexpected character %q, expected %q (asterisk not allowed in comments)", r2, '/'))
+					s.error(p, fmt.Errorf("unexpected character %q, expected %q (asterisk not allowed in comments)", r2, '/'))
 					return bad
 				}
 				v.PreWS = append(v.PreWS, r, r2)
 				v.P = s.pos
+				s.addComment(&v, &ast.Comment{Slash: ast.Pos(start.Offset + 1), Text: "/*" + ss + "*/"}, lastComment)
+				lastComment = len(v.PreWS)
 
 			case '-':
 				s.readRune()
@@ -208,7 +241,7 @@ func (s *scanner) Lex(lval *yySymType) int {
 		case '\'':
 			ss, err := s.readQuoted(s.readRune())
 			if err != nil {
-				s.errs = append(s.errs, err)
+				s.error(v.P, err)
 				return bad
 			}
 			v.S = ss
@@ -217,7 +250,7 @@ func (s *scanner) Lex(lval *yySymType) int {
 		case '"':
 			ss, err := s.readQuoted(s.readRune())
 			if err != nil {
-				s.errs = append(s.errs, err)
+				s.error(v.P, err)
 				return bad
 			}
 			v.S = ss
@@ -229,7 +262,7 @@ func (s *scanner) Lex(lval *yySymType) int {
 			if err == io.EOF {
 				return '.'
 			} else if err != nil {
-				s.errs = append(s.errs, err)
+				s.error(v.P, err)
 				return bad
 			}
 			if ss != "" {
@@ -243,8 +276,8 @@ func (s *scanner) Lex(lval *yySymType) int {
 			switch {
 			case unicode.IsDigit(r):
 				ss, err := s.readWhile(func(r rune) bool { return unicode.IsDigit(r) })
-				if err != nil {
-					s.errs = append(s.errs, err)
+				if err != nil && err != io.EOF {
+					s.error(v.P, err)
 					return bad
 				}
 				ss = string([]rune{r}) + ss
@@ -255,7 +288,7 @@ func (s *scanner) Lex(lval *yySymType) int {
 					if err == io.EOF {
 						// Decimals may end in a period.
 					} else if err != nil {
-						s.errs = append(s.errs, err)
+						s.error(v.P, err)
 						return bad
 					}
 					v.S = ss + "." + ss2
@@ -268,17 +301,12 @@ func (s *scanner) Lex(lval *yySymType) int {
 
 			case unicode.IsLetter(r):
 				ss, err := s.readWhile(func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' })
-				if err != nil {
-					s.errs = append(s.errs, err)
+				if err != nil && err != io.EOF {
+					s.error(v.P, err)
 					return bad
 				}
-				ss = string([]rune{r}) + ss
-				tok, ok := keywords[strings.ToUpper(ss)]
-				if ok {
-					return tok
-				}
-				v.S = ss
-				return UNQUOTED_IDENTIFIER
+				v.S = string([]rune{r}) + ss
+				return yyTokens[token.Lookup(v.S)]
 
 			case unicode.IsSpace(r):
 				// Ignore.
@@ -286,13 +314,32 @@ func (s *scanner) Lex(lval *yySymType) int {
 				v.P = s.pos
 
 			default:
-				s.errs = append(s.errs, fmt.Errorf("unexpected character %q", r))
+				v.S = string(r)
+				s.errs = append(s.errs, &Error{Pos: v.P, Msg: fmt.Sprintf("unexpected character %q", r), Unexpected: fmt.Sprintf("%q", r)})
 				return bad
 			}
 		}
 	}
 }
 
+// addComment adds the comment to v.Comments. It starts a new
+// comment group unless it is the first comment of v or the previous
+// comment ended at index lastComment of v.PreWS, with at most one
+// newline in between.
+func (s *scanner) addComment(v *lexValue, c *ast.Comment, lastComment int) {
+	if len(v.Comments) == 0 {
+		s.commented = append(s.commented, v)
+	}
+
+	// The comment itself has been appended to PreWS.
+	ws := v.PreWS[:len(v.PreWS)-len([]rune(c.Text))]
+	if lastComment < 0 || strings.Count(string(ws[lastComment:]), "\n") > 1 {
+		v.Comments = append(v.Comments, &ast.CommentGroup{})
+	}
+	g := v.Comments[len(v.Comments)-1]
+	g.List = append(g.List, c)
+}
+
 // readQuoted reads a quoted string or identifier until the end. The
 // returned string includes the surrounding quotes.
 func (s *scanner) readQuoted(quote rune) (string, error) {
@@ -305,6 +352,9 @@ func (s *scanner) readQuoted(quote rune) (string, error) {
 		buf += ss
 
 		r := s.readRune()
+		if r == eof {
+			return "", errors.New("unterminated quoted string")
+		}
 		switch s.peekRune() {
 		case quote:
 			buf += string([]rune{r, s.readRune()})
@@ -323,11 +373,11 @@ func (s *scanner) peekRune() rune {
 		return s.la[0]
 	}
 
-	r, _, err := s.r.ReadRune()
+	r, err := s.read()
 	if err == io.EOF {
 		return eof
 	} else if err != nil {
-		s.errs = append(s.errs, err)
+		s.error(s.pos, err)
 		return bad
 	}
 	s.la = append(s.la, r)
@@ -339,31 +389,45 @@ func (s *scanner) readRune() rune {
 	if len(s.la) > 0 {
 		r := s.la[0]
 		s.la = s.la[1:]
-		s.pos.Offset++
-		if r == '\n' {
-			s.pos.Line++
-			s.pos.Column = 0
-		} else {
-			s.pos.Column++
-		}
+		s.advance(r)
 		return r
 	}
 
-	r, _, err := s.r.ReadRune()
+	r, err := s.read()
 	if err == io.EOF {
 		return eof
 	} else if err != nil {
-		s.errs = append(s.errs, err)
+		s.error(s.pos, err)
 		return bad
 	}
+	s.advance(r)
+	return r
+}
+
+// read reads a rune from the reader. After the first error other
+// than io.EOF, it returns io.EOF, so the parser terminates.
+func (s *scanner) read() (rune, error) {
+	if s.rerr {
+		return 0, io.EOF
+	}
+
+	r, _, err := s.r.ReadRune()
+	if err != nil && err != io.EOF {
+		s.rerr = true
+	}
+	return r, err
+}
+
+// advance updates the position after reading r.
+func (s *scanner) advance(r rune) {
 	s.pos.Offset++
 	if r == '\n' {
 		s.pos.Line++
 		s.pos.Column = 0
+		s.file.AddLine(s.pos.Offset)
 	} else {
 		s.pos.Column++
 	}
-	return r
 }
 
 // readWhile returns the runes where f() returns true.
@@ -375,18 +439,12 @@ func (s *scanner) readWhile(f func(rune) bool) (string, error) {
 				s.la = s.la[i:]
 				return ret, nil
 			}
-			s.pos.Offset++
-			if r == '\n' {
-				s.pos.Line++
-				s.pos.Column = 0
-			} else {
-				s.pos.Column++
-			}
+			s.advance(r)
 		}
 	}
 
 	for {
-		r, _, err := s.r.ReadRune()
+		r, err := s.read()
 		if err == io.EOF && len(s.la) > 0 {
 			ret := string(s.la)
 			s.la = nil
@@ -401,78 +459,6 @@ func (s *scanner) readWhile(f func(rune) bool) (string, error) {
 			s.la = s.la[i:]
 			return ret, nil
 		}
-		s.pos.Offset++
-		if r == '\n' {
-			s.pos.Line++
-			s.pos.Column = 0
-		} else {
-			s.pos.Column++
-		}
+		s.advance(r)
 	}
 }
-
-// keywords is the list of reserved identifiers. They are case-insensitive.
-var keywords = map[string]int{
-	"ALL": ALL,
-	"AND": AND, "OR": OR, "NOT": NOT,
-	"ANY": ANY,
-	"ARE": ARE,
-	"AS":  AS,
-	"ASC": ASC, "DESC": DESC,
-	"BETWEEN": BETWEEN,
-	"BY":      BY,
-	"CASE":    CASE, "WHEN": WHEN, "THEN": THEN, "ELSE": ELSE, "END": END,
-	"CAST":    CAST,
-	"COLUMNS": COLUMNS,
-	"COST":    COST,
-	"COUNT":   COUNT, "MIN": MIN, "MAX": MAX, "AVG": AVG, "SUM": SUM, "ARRAY_AGG": ARRAY_AGG, "LISTAGG": LISTAGG,
-	"CREATE": CREATE,
-	"DATE":   DATE, "TIME": TIME, "TIMESTAMP": TIMESTAMP,
-	"DESTINATION": DESTINATION,
-	"DISTINCT":    DISTINCT,
-	"DROP":        DROP,
-	"EDGE":        EDGE,
-	"EXCEPT":      EXCEPT,
-	"EXISTS":      EXISTS,
-	"EXTRACT":     EXTRACT,
-	"FROM":        FROM,
-	"GRAPH":       GRAPH,
-	"GROUP":       GROUP,
-	"HAVING":      HAVING,
-	"IN":          IN,
-	"INSERT":      INSERT, "UPDATE": UPDATE, "DELETE": DELETE,
-	"INTERVAL": INTERVAL,
-	"INTO":     INTO,
-	"IS":       IS,
-	"KEY":      KEY,
-	"LABEL":    LABEL,
-	"LABELS":   LABELS,
-	"LIMIT":    LIMIT, "OFFSET": OFFSET,
-	"MATCH":      MATCH,
-	"NO":         NO,
-	"NULL":       NULL,
-	"ON":         ON,
-	"ONE":        ONE,
-	"ORDER":      ORDER,
-	"PATH":       PATH,
-	"PER":        PER,
-	"PREFIX":     PREFIX,
-	"PROPERTIES": PROPERTIES,
-	"PROPERTY":   PROPERTY,
-	"REFERENCES": REFERENCES,
-	"ROW":        ROW,
-	"SELECT":     SELECT,
-	"SET":        SET,
-	"SHORTEST":   SHORTEST, "CHEAPEST": CHEAPEST,
-	"SOURCE": SOURCE,
-	"STRING": STRING, "BOOLEAN": BOOLEAN, "INTEGER": INTEGER, "INT": INT, "LONG": LONG, "FLOAT": FLOAT, "DOUBLE": DOUBLE,
-	"STEP":   STEP,
-	"TABLES": TABLES,
-	"TOP":    TOP,
-	"TRUE":   TRUE, "FALSE": FALSE,
-	"VERTEX": VERTEX,
-	"WHERE":  WHERE,
-	"WITH":   WITH,
-	"YEAR":   YEAR, "MONTH": MONTH, "DAY": DAY, "HOUR": HOUR, "MINUTE": MINUTE, "SECOND": SECOND, "TIMEZONE_HOUR": TIMEZONE_HOUR, "TIMEZONE_MINUTE": TIMEZONE_MINUTE,
-	"ZONE": ZONE,
-}
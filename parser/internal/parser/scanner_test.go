@@ -7,6 +7,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
 )
 
 func TestScanner(t *testing.T) {
@@ -20,7 +23,8 @@ func TestScanner(t *testing.T) {
 		{"emptycomment", "/**/", nil},
 		{"comment", "/* abc */", nil},
 		{"multicomment", "/* abc\ndef */", nil},
-		{"precomment", "/* abc */%", []testToken{{'%', yySymType{L: &lexValue{P: Position{Offset: 9, Column: 9}, PreWS: []rune("/* abc */")}}}}},
+		{"precomment", "/* abc */%", []testToken{{'%', yySymType{L: &lexValue{P: Position{Offset: 9, Column: 9}, PreWS: []rune("/* abc */"), Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Slash: 1, Text: "/* abc */"}}}}}}}}},
+		{"commentgroups", "/* a */\n/* b */\n\n/* c */%", []testToken{{'%', yySymType{L: &lexValue{P: Position{Offset: 24, Line: 3, Column: 7}, PreWS: []rune("/* a */\n/* b */\n\n/* c */"), Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Slash: 1, Text: "/* a */"}, {Slash: 9, Text: "/* b */"}}}, {List: []*ast.Comment{{Slash: 18, Text: "/* c */"}}}}}}}}},
 
 		{"headingws", " %", []testToken{{'%', yySymType{L: &lexValue{P: Position{Offset: 1, Column: 1}, PreWS: []rune{' '}}}}}},
 		{"trailingws", "% ", []testToken{{Tok: '%'}}},
@@ -66,17 +70,21 @@ func TestScanner(t *testing.T) {
 		{"unsigned_decimal_first", "42.", []testToken{{UNSIGNED_DECIMAL, yySymType{L: &lexValue{S: "42."}}}}},
 		{"unsigned_decimal_both", "42.4711", []testToken{{UNSIGNED_DECIMAL, yySymType{L: &lexValue{S: "42.4711"}}}}},
 		{"unsigned_integer", "42", []testToken{{UNSIGNED_INTEGER, yySymType{L: &lexValue{S: "42"}}}}},
+		{"unsigned_integer1", "4", []testToken{{UNSIGNED_INTEGER, yySymType{L: &lexValue{S: "4"}}}}},
 
 		{"unquoted_identifier", "abc", []testToken{{UNQUOTED_IDENTIFIER, yySymType{L: &lexValue{S: "abc"}}}}},
-		{"keyword_ucase", "CREATE", []testToken{{Tok: CREATE}}},
-		{"keyword_lcase", "create", []testToken{{Tok: CREATE}}},
-		{"keyword_ccase", "Create", []testToken{{Tok: CREATE}}},
-
-		{"multiple", "CREATE- /", []testToken{{Tok: CREATE}, {'-', yySymType{L: &lexValue{P: Position{Offset: 6, Column: 6}}}}, {'/', yySymType{L: &lexValue{P: Position{Offset: 8, Column: 8}, PreWS: []rune{' '}}}}}},
+		{"unquoted_identifier1", "a", []testToken{{UNQUOTED_IDENTIFIER, yySymType{L: &lexValue{S: "a"}}}}},
+		{"keyword_ucase", "CREATE", []testToken{{CREATE, yySymType{L: &lexValue{S: "CREATE"}}}}},
+		{"keyword_lcase", "create", []testToken{{CREATE, yySymType{L: &lexValue{S: "create"}}}}},
+		{"keyword_ccase", "Create", []testToken{{CREATE, yySymType{L: &lexValue{S: "Create"}}}}},
+		{"keyword_for", "for", []testToken{{FOR, yySymType{L: &lexValue{S: "for"}}}}},
+		{"keyword_substring", "Substring", []testToken{{SUBSTRING, yySymType{L: &lexValue{S: "Substring"}}}}},
+
+		{"multiple", "CREATE- /", []testToken{{CREATE, yySymType{L: &lexValue{S: "CREATE"}}}, {'-', yySymType{L: &lexValue{P: Position{Offset: 6, Column: 6}}}}, {'/', yySymType{L: &lexValue{P: Position{Offset: 8, Column: 8}, PreWS: []rune{' '}}}}}},
 		{"multiplelines", "CREATE\n - /*\nabc*//", []testToken{
-			{Tok: CREATE},
+			{CREATE, yySymType{L: &lexValue{S: "CREATE"}}},
 			{'-', yySymType{L: &lexValue{P: Position{Offset: 8, Line: 1, Column: 1}, PreWS: []rune("\n ")}}},
-			{'/', yySymType{L: &lexValue{P: Position{Offset: 18, Line: 2, Column: 5}, PreWS: []rune(" /*\nabc*/")}}}},
+			{'/', yySymType{L: &lexValue{P: Position{Offset: 18, Line: 2, Column: 5}, PreWS: []rune(" /*\nabc*/"), Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Slash: 11, Text: "/*\nabc*/"}}}}}}}},
 		},
 	}
 	for _, tst := range tsts {
@@ -102,9 +110,56 @@ func TestScanner(t *testing.T) {
 				got = append(got, tok)
 			}
 
-			if diff := cmp.Diff(tst.Want, got, cmpopts.IgnoreUnexported(yySymType{})); diff != "" {
+			// End positions are tested in TestScannerEnd.
+			if diff := cmp.Diff(tst.Want, got, cmpopts.IgnoreUnexported(yySymType{}), cmpopts.IgnoreFields(lexValue{}, "E")); diff != "" {
 				t.Errorf("Lex: +got, -want:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestScannerEnd(t *testing.T) {
+	l := newScanner(bufio.NewReader(strings.NewReader("CREATE\n  'a''b' /* c */ -> xy")))
+	var got [][2]Position
+	for {
+		var lval yySymType
+		if tok := l.Lex(&lval); tok == bad {
+			t.Fatalf("Lex failed: %v", l.errs)
+		} else if tok == eof {
+			break
+		}
+		got = append(got, [2]Position{lval.L.P, lval.L.E})
+	}
+
+	want := [][2]Position{
+		{{Offset: 0}, {Offset: 6, Column: 6}},
+		{{Offset: 9, Line: 1, Column: 2}, {Offset: 15, Line: 1, Column: 8}},
+		{{Offset: 24, Line: 1, Column: 17}, {Offset: 26, Line: 1, Column: 19}},
+		{{Offset: 27, Line: 1, Column: 20}, {Offset: 29, Line: 1, Column: 22}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Lex: +got, -want:\n%s", diff)
+	}
+
+	if got, want := l.file.LineCount(), 2; got != want {
+		t.Errorf("LineCount: got %d, want %d", got, want)
+	}
+}
+
+func TestYYTokens(t *testing.T) {
+	if got, want := len(yyTokens), int(token.ZONE)+1; got != want {
+		t.Errorf("len(yyTokens): got %d, want %d", got, want)
+	}
+	for i, n := range yyTokens {
+		tok := token.Token(i)
+		if (tok.IsLiteral() || tok.IsOperator() || tok.IsKeyword()) && n == 0 {
+			t.Errorf("yyTokens[%v]: missing", tok)
+		}
+		if !tok.IsKeyword() {
+			continue
+		}
+		if got := yyTokname(int(yyTok2[n-yyPrivate])); got != tok.String() {
+			t.Errorf("yyTokens[%v]: got %s", tok, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"github.com/itergia/pgql-go/token"
+)
+
+// Mode controls the behavior of a Scanner.
+type Mode uint
+
+const (
+	// ScanComments makes Scan return comments as COMMENT tokens.
+	// Otherwise, they are skipped.
+	ScanComments Mode = 1 << iota
+)
+
+// An ErrorHandler is called for each error found by a Scanner.
+type ErrorHandler func(pos Position, msg string)
+
+// A Scanner splits its input into tokens, the same way the parser
+// does.
+type Scanner struct {
+	s    *scanner
+	err  ErrorHandler
+	mode Mode
+
+	// queue holds the tokens read, but not yet returned by Scan.
+	queue []scannedToken
+
+	// ErrorCount is the number of errors found so far.
+	ErrorCount int
+}
+
+type scannedToken struct {
+	pos Position
+	tok token.Token
+	lit string
+}
+
+// NewScanner returns a Scanner reading from r. If err is not nil, it
+// is called for each error.
+func NewScanner(r RuneReader, err ErrorHandler, mode Mode) *Scanner {
+	return &Scanner{s: newScanner(r), err: err, mode: mode}
+}
+
+// Scan returns the next token, and its position. The literal is the
+// source text of identifiers, keywords, literals and comments, and is
+// empty for operators. At the end of the input, Scan returns EOF. On
+// errors, it returns ILLEGAL, with the offending text as literal.
+func (s *Scanner) Scan() (pos Position, tok token.Token, lit string) {
+	if len(s.queue) == 0 {
+		s.scan()
+	}
+
+	t := s.queue[0]
+	s.queue = s.queue[1:]
+	return t.pos, t.tok, t.lit
+}
+
+// scan reads the next token, and its comments, into s.queue.
+func (s *Scanner) scan() {
+	var lval yySymType
+	yytok := s.s.Lex(&lval)
+	v := lval.L
+
+	for _, e := range s.s.errs {
+		s.ErrorCount++
+		if s.err != nil {
+			s.err(e.Pos, e.Msg)
+		}
+	}
+	s.s.errs = nil
+
+	if s.mode&ScanComments != 0 {
+		for _, g := range v.Comments {
+			for _, c := range g.List {
+				s.queue = append(s.queue, scannedToken{s.s.file.Position(c.Slash), token.COMMENT, c.Text})
+			}
+		}
+	}
+	// Comments are not needed for parsing.
+	s.s.commented = nil
+
+	tok, ok := tokens[yytok]
+	if !ok {
+		tok = token.ILLEGAL
+	}
+	t := scannedToken{pos: v.P, tok: tok}
+	if tok.IsLiteral() || tok.IsKeyword() || tok == token.ILLEGAL {
+		t.lit = v.S
+	}
+	s.queue = append(s.queue, t)
+}
@@ -0,0 +1,158 @@
+package parser
+
+import "github.com/itergia/pgql-go/token"
+
+// yyTokens maps tokens to goyacc token numbers.
+var yyTokens = [...]int{
+	token.ILLEGAL: bad,
+	token.EOF:     eof,
+
+	token.IDENT:            UNQUOTED_IDENTIFIER,
+	token.QUOTED_IDENT:     QUOTED_IDENTIFIER,
+	token.UNSIGNED_INTEGER: UNSIGNED_INTEGER,
+	token.UNSIGNED_DECIMAL: UNSIGNED_DECIMAL,
+	token.STRING_LITERAL:   STRING_LITERAL,
+
+	token.LPAREN:        '(',
+	token.RPAREN:        ')',
+	token.LBRACE:        '{',
+	token.RBRACE:        '}',
+	token.RBRACK:        ']',
+	token.COLON:         ':',
+	token.QUESTION:      '?',
+	token.SEMICOLON:     ';',
+	token.COMMA:         ',',
+	token.PERIOD:        '.',
+	token.EQL:           '=',
+	token.NEQ:           LTGT,
+	token.LSS:           '<',
+	token.GTR:           '>',
+	token.LEQ:           LTEQ,
+	token.GEQ:           GTEQ,
+	token.ADD:           '+',
+	token.SUB:           '-',
+	token.MUL:           '*',
+	token.QUO:           '/',
+	token.REM:           '%',
+	token.CONCAT:        DPIPE,
+	token.PIPE:          '|',
+	token.LARROW:        LARROW,
+	token.RARROW:        RARROW,
+	token.LARROWBRACKET: LARROWBRACKET,
+	token.RBRACKETARROW: RBRACKETARROW,
+	token.LARROWSLASH:   LARROWSLASH,
+	token.RSLASHARROW:   RSLASHARROW,
+	token.LDASHBRACKET:  LDASHBRACKET,
+	token.RBRACKETDASH:  RBRACKETDASH,
+	token.LDASHSLASH:    LDASHSLASH,
+	token.RSLASHDASH:    RSLASHDASH,
+
+	token.ALL:             ALL,
+	token.AND:             AND,
+	token.ANY:             ANY,
+	token.ARE:             ARE,
+	token.ARRAY_AGG:       ARRAY_AGG,
+	token.AS:              AS,
+	token.ASC:             ASC,
+	token.AVG:             AVG,
+	token.BETWEEN:         BETWEEN,
+	token.BOOLEAN:         BOOLEAN,
+	token.BY:              BY,
+	token.CASE:            CASE,
+	token.CAST:            CAST,
+	token.CHEAPEST:        CHEAPEST,
+	token.COLUMNS:         COLUMNS,
+	token.COST:            COST,
+	token.COUNT:           COUNT,
+	token.CREATE:          CREATE,
+	token.DATE:            DATE,
+	token.DAY:             DAY,
+	token.DELETE:          DELETE,
+	token.DESC:            DESC,
+	token.DESTINATION:     DESTINATION,
+	token.DISTINCT:        DISTINCT,
+	token.DOUBLE:          DOUBLE,
+	token.DROP:            DROP,
+	token.EDGE:            EDGE,
+	token.ELSE:            ELSE,
+	token.END:             END,
+	token.EXCEPT:          EXCEPT,
+	token.EXISTS:          EXISTS,
+	token.EXTRACT:         EXTRACT,
+	token.FALSE:           FALSE,
+	token.FLOAT:           FLOAT,
+	token.FOR:             FOR,
+	token.FROM:            FROM,
+	token.GRAPH:           GRAPH,
+	token.GROUP:           GROUP,
+	token.HAVING:          HAVING,
+	token.HOUR:            HOUR,
+	token.IN:              IN,
+	token.INSERT:          INSERT,
+	token.INT:             INT,
+	token.INTEGER:         INTEGER,
+	token.INTERVAL:        INTERVAL,
+	token.INTO:            INTO,
+	token.IS:              IS,
+	token.KEY:             KEY,
+	token.LABEL:           LABEL,
+	token.LABELS:          LABELS,
+	token.LIMIT:           LIMIT,
+	token.LISTAGG:         LISTAGG,
+	token.LONG:            LONG,
+	token.MATCH:           MATCH,
+	token.MAX:             MAX,
+	token.MIN:             MIN,
+	token.MINUTE:          MINUTE,
+	token.MONTH:           MONTH,
+	token.NO:              NO,
+	token.NOT:             NOT,
+	token.NULL:            NULL,
+	token.OFFSET:          OFFSET,
+	token.ON:              ON,
+	token.ONE:             ONE,
+	token.OR:              OR,
+	token.ORDER:           ORDER,
+	token.PATH:            PATH,
+	token.PER:             PER,
+	token.PREFIX:          PREFIX,
+	token.PROPERTIES:      PROPERTIES,
+	token.PROPERTY:        PROPERTY,
+	token.REFERENCES:      REFERENCES,
+	token.ROW:             ROW,
+	token.SECOND:          SECOND,
+	token.SELECT:          SELECT,
+	token.SET:             SET,
+	token.SHORTEST:        SHORTEST,
+	token.SOURCE:          SOURCE,
+	token.STEP:            STEP,
+	token.STRING:          STRING,
+	token.SUBSTRING:       SUBSTRING,
+	token.SUM:             SUM,
+	token.TABLES:          TABLES,
+	token.THEN:            THEN,
+	token.TIME:            TIME,
+	token.TIMESTAMP:       TIMESTAMP,
+	token.TIMEZONE_HOUR:   TIMEZONE_HOUR,
+	token.TIMEZONE_MINUTE: TIMEZONE_MINUTE,
+	token.TOP:             TOP,
+	token.TRUE:            TRUE,
+	token.UPDATE:          UPDATE,
+	token.VERTEX:          VERTEX,
+	token.WHEN:            WHEN,
+	token.WHERE:           WHERE,
+	token.WITH:            WITH,
+	token.YEAR:            YEAR,
+	token.ZONE:            ZONE,
+}
+
+// tokens maps goyacc token numbers back to tokens.
+var tokens = map[int]token.Token{}
+
+func init() {
+	for tok, n := range yyTokens {
+		if n != 0 || token.Token(tok) == token.EOF {
+			tokens[n] = token.Token(tok)
+		}
+	}
+}
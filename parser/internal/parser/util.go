@@ -4,19 +4,101 @@ import (
 	"errors"
 
 	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
 )
 
 // reportError reports a non-nil error to the lexer. Returns zero on
-// nil and one on error.
+// nil and one on error. Unlike syntax errors, this does not trigger
+// error recovery.
 func reportError(lex yyLexer, err error) int {
 	if err == nil {
 		return 0
 	}
 
-	lex.Error(err.Error())
+	if pc, ok := lex.(*parserContext); ok {
+		pc.addError(err.Error())
+	} else {
+		lex.Error(err.Error())
+	}
 	return 1
 }
 
+// reportErrorAt is like reportError, but records the error at the
+// token l instead of the last token.
+func reportErrorAt(lex yyLexer, l *lexValue, err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if pc, ok := lex.(*parserContext); ok && l != nil {
+		pc.errs = append(pc.errs, newSyntaxError(l.P, err.Error()))
+		return 1
+	}
+	return reportError(lex, err)
+}
+
+// pos returns the position of the token l. The token is nil in
+// tests.
+func pos(l *lexValue) ast.Pos {
+	if l == nil {
+		return ast.NoPos
+	}
+	return ast.Pos(l.P.Offset + 1)
+}
+
+// end returns the position after the token l.
+func end(l *lexValue) ast.Pos {
+	if l == nil {
+		return ast.NoPos
+	}
+	return ast.Pos(l.E.Offset + 1)
+}
+
+// nextBindVar returns the index of the next bind variable in the
+// statement. The lexer is not a parserContext in tests, and zero is
+// returned.
+func nextBindVar(yylex yyLexer) int {
+	pc, ok := yylex.(*parserContext)
+	if !ok {
+		return 0
+	}
+	pc.nbindVars++
+	return pc.nbindVars - 1
+}
+
+// uintLit returns an unsigned integer literal for the token.
+func uintLit(l *lexValue) *ast.BasicLit {
+	return &ast.BasicLit{S: l.S, Kind: ast.UIntKind, ValuePos: pos(l), EndPos: end(l)}
+}
+
+// stringLit returns a string literal for the token.
+func stringLit(l *lexValue) *ast.BasicLit {
+	// The scanner only returns valid STRING_LITERALs.
+	text, _ := token.UnquoteString(l.S)
+	return &ast.BasicLit{S: l.S, Text: text, Kind: ast.StringKind, ValuePos: pos(l), EndPos: end(l)}
+}
+
+// checkLit validates the string of a literal, like the date in a
+// DATE literal.
+func checkLit(lit *ast.BasicLit) error {
+	_, err := lit.Value()
+	return err
+}
+
+// leading records that the node starts with the token l, so comments
+// before l are associated with it. Outer nodes are reduced later, and
+// take precedence. The token is nil in tests. Returns n.
+func leading[N ast.Node](yylex yyLexer, n N, l *lexValue) N {
+	if pc, ok := yylex.(*parserContext); ok && l != nil {
+		if pc.anchors == nil {
+			pc.anchors = map[*lexValue]ast.Node{}
+		}
+		pc.anchors[l] = n
+	}
+
+	return n
+}
+
 // checkPathPattern validates the AllPathPattern production.
 func checkPathPattern(pats []*ast.PathPattern) error {
 	pat := pats[0]
@@ -3,16 +3,32 @@ package parser
 import (
 	"bufio"
 	"io"
+	"strings"
 
+	"github.com/itergia/pgql-go/ast"
 	"github.com/itergia/pgql-go/parser/internal/parser"
+	"github.com/itergia/pgql-go/token"
 )
 
 type Statements = parser.Statements
 
+// An Error is a syntax error, with the position and name of the
+// offending token, and the names of the expected tokens.
+type Error = parser.Error
+
+// ErrorList is the error returned by Parse. Use errors.As to
+// retrieve it, or its first *Error.
+type ErrorList = parser.ErrorList
+
 // Parse parses the given UTF-8 stream as a list of PGQL statements,
 // separated by semicolons. If the reader is a *bufio.Reader, it is
 // used directly, otherwise a new bufio.Reader is created, which means
 // the parser may read more data than needed from r.
+//
+// Parsing continues after syntax errors, at the next semicolon or
+// clause keyword. The returned Statements then holds the statements
+// that could be parsed, some of which may be partial, and the error
+// lists all errors, sorted by position.
 func Parse(r io.Reader) (*Statements, error) {
 	if br, ok := r.(parser.RuneReader); ok {
 		return parser.Parse(br)
@@ -20,3 +36,28 @@ func Parse(r io.Reader) (*Statements, error) {
 
 	return parser.Parse(bufio.NewReader(r))
 }
+
+// ParseExpr parses a single value expression, like the condition of a
+// WHERE clause. The expression must span the whole input.
+func ParseExpr(s string) (ast.Expr, error) {
+	return parser.ParseExpr(strings.NewReader(s))
+}
+
+// ParsePathPattern parses a single path pattern, like
+// "(a)-[e:knows]->(b)", as found in a MATCH clause.
+func ParsePathPattern(s string) (*ast.PathPattern, error) {
+	return parser.ParsePathPattern(strings.NewReader(s))
+}
+
+// ParseStatement parses a single statement. The terminating semicolon
+// is optional, but nothing may follow it. On errors, the partial
+// statement is returned, if any.
+func ParseStatement(s string) (ast.Stmt, error) {
+	return parser.ParseStatement(strings.NewReader(s))
+}
+
+// IsKeyword returns true if s is a reserved identifier, which must be
+// quoted to be used as an identifier. The check is case-insensitive.
+func IsKeyword(s string) bool {
+	return token.IsKeyword(s)
+}
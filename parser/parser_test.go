@@ -2,10 +2,17 @@ package parser
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
 )
 
 func TestParse(t *testing.T) {
@@ -35,3 +42,378 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRecovery(t *testing.T) {
+	tsts := []struct {
+		Name    string
+		Input   string
+		Want    []string
+		WantErr string
+	}{
+		{
+			"nextStatement",
+			"SELECT a b c;\nDROP PROPERTY GRAPH g;",
+			[]string{"*ast.DropStmt DROP PROPERTY GRAPH g"},
+			"at 1:10: syntax error: unexpected UNQUOTED_IDENTIFIER, expecting FROM",
+		},
+		{
+			"from",
+			"SELECT a b FROM MATCH (a);",
+			[]string{"*ast.SelectStmt SELECT a b FROM MATCH (a)"},
+			"at 1:10: syntax error: unexpected UNQUOTED_IDENTIFIER, expecting FROM",
+		},
+		{
+			"orderBy",
+			"SELECT a FROM MATCH (a) WHERE a.x = = 1 ORDER BY a;",
+			[]string{"*ast.SelectStmt SELECT a FROM MATCH (a) WHERE a.x = = 1 ORDER BY a"},
+			"at 1:37: syntax error: unexpected '='",
+		},
+		{
+			"semicolon",
+			"SELECT a FROM;\nDROP PROPERTY GRAPH g;",
+			[]string{"*ast.SelectStmt SELECT a", "*ast.DropStmt DROP PROPERTY GRAPH g"},
+			"at 1:14: syntax error: unexpected ';', expecting MATCH",
+		},
+		{
+			"multiple",
+			"SELECT FROM MATCH (a) GROUP BY ) ORDER BY b;\nDROP PROPERTY GRAPH;",
+			[]string{"*ast.SelectStmt SELECT FROM MATCH (a) GROUP BY ) ORDER BY b"},
+			"at 1:8: syntax error: unexpected FROM (and 2 more errors)",
+		},
+		{
+			"scanner",
+			"SELECT a FROM MATCH (a) WHERE a # b;\nDROP PROPERTY GRAPH g;",
+			[]string{"*ast.SelectStmt SELECT a FROM MATCH (a) WHERE a", "*ast.DropStmt DROP PROPERTY GRAPH g"},
+			"at 1:33: unexpected character '#'",
+		},
+		{
+			"unterminated",
+			"DROP PROPERTY GRAPH g;\nSELECT 'a FROM MATCH (a);",
+			[]string{"*ast.DropStmt DROP PROPERTY GRAPH g"},
+			"at 2:8: unterminated quoted string",
+		},
+		{
+			"missingSemicolon",
+			"SELECT a FROM MATCH (a)",
+			[]string{"*ast.SelectStmt SELECT a FROM MATCH (a)"},
+			"at 1:24: syntax error: unexpected $end, expecting ';'",
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := Parse(strings.NewReader(tst.Input))
+			if err == nil {
+				t.Fatalf("Parse: got nil error, want %q", tst.WantErr)
+			}
+			if got := err.Error(); got != tst.WantErr {
+				t.Errorf("Parse: got error %q, want %q", got, tst.WantErr)
+			}
+
+			src := []rune(tst.Input)
+			var got []string
+			for _, stmt := range stmts.Stmts {
+				got = append(got, fmt.Sprintf("%T %s", stmt, string(src[stmt.Pos()-1:stmt.End()-1])))
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Stmts: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseErrorList(t *testing.T) {
+	_, err := Parse(strings.NewReader("SELECT a FROM;\nSELECT a b c;\nSELECT # FROM MATCH (a);"))
+
+	var errs ErrorList
+	if !errors.As(err, &errs) {
+		t.Fatalf("Parse: got error %T, want ErrorList", err)
+	}
+	want := ErrorList{
+		{Pos: token.Position{Offset: 13, Column: 13}, Msg: "syntax error: unexpected ';', expecting MATCH", Unexpected: "';'", Expected: []string{"MATCH"}},
+		{Pos: token.Position{Offset: 24, Line: 1, Column: 9}, Msg: "syntax error: unexpected UNQUOTED_IDENTIFIER, expecting FROM", Unexpected: "UNQUOTED_IDENTIFIER", Expected: []string{"FROM"}},
+		{Pos: token.Position{Offset: 36, Line: 2, Column: 7}, Msg: "unexpected character '#'", Unexpected: "'#'"},
+	}
+	if diff := cmp.Diff(want, errs); diff != "" {
+		t.Errorf("Parse: +got, -want:\n%s", diff)
+	}
+
+	var first *Error
+	if !errors.As(err, &first) {
+		t.Fatalf("Parse: got error %T, want *Error", err)
+	}
+	if first != errs[0] {
+		t.Errorf("errors.As: got %v, want %v", first, errs[0])
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	stmts, err := Parse(strings.NewReader(`/* Find friends. */
+SELECT a.name, /* the friend */ b.name
+FROM MATCH (a) -> (b)
+/* Only adults. */
+WHERE b.age > /* years */ 18 /* end */;
+/* trailing */
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	nodes := map[*ast.CommentGroup]ast.Node{}
+	for n, gs := range stmts.Comments {
+		for _, g := range gs {
+			nodes[g] = n
+		}
+	}
+	var got []string
+	for _, g := range stmts.Comments.Comments() {
+		got = append(got, fmt.Sprintf("%T %s", nodes[g], g.Text()))
+	}
+
+	want := []string{
+		"*ast.SelectStmt  Find friends.\n",
+		"*ast.SelectElem  the friend\n",
+		"*ast.OpExpr  Only adults.\n",
+		"*ast.BasicLit  years\n",
+		"*parser.Statements  end\n",
+		"*parser.Statements  trailing\n",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Comments: +got, -want:\n%s", diff)
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	src := "SELECT n.name AS x, COUNT(*)\nFROM MATCH (n:Person) -[e]-> (m)\nWHERE m.age IS NOT NULL\nORDER BY x DESC;"
+	stmts, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	rs := []rune(src)
+	var got []string
+	ast.Inspect(stmts.Stmts[0], func(n ast.Node) bool {
+		if n != nil {
+			got = append(got, fmt.Sprintf("%T %s", n, string(rs[n.Pos()-1:n.End()-1])))
+		}
+		return true
+	})
+
+	want := []string{
+		"*ast.SelectStmt SELECT n.name AS x, COUNT(*)\nFROM MATCH (n:Person) -[e]-> (m)\nWHERE m.age IS NOT NULL\nORDER BY x DESC",
+		"*ast.SelectElem n.name AS x",
+		"*ast.NamedExpr n.name AS x",
+		"*ast.QIdent n.name",
+		"*ast.Ident n",
+		"*ast.Ident name",
+		"*ast.Ident x",
+		"*ast.SelectElem COUNT(*)",
+		"*ast.NamedExpr COUNT(*)",
+		"*ast.AggregateExpr COUNT(*)",
+		"*ast.MatchClause MATCH (n:Person) -[e]-> (m)",
+		"*ast.PathPattern (n:Person) -[e]-> (m)",
+		"*ast.VertexPattern (n:Person)",
+		"*ast.Ident n",
+		"*ast.Ident Person",
+		"*ast.PathPatternPrimary -[e]->",
+		"*ast.EdgePattern -[e]->",
+		"*ast.Ident e",
+		"*ast.VertexPattern (m)",
+		"*ast.Ident m",
+		"*ast.IsNullExpr m.age IS NOT NULL",
+		"*ast.QIdent m.age",
+		"*ast.Ident m",
+		"*ast.Ident age",
+		"*ast.OrderTerm x DESC",
+		"*ast.Ident x",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("spans: +got, -want:\n%s", diff)
+	}
+
+	where := stmts.Stmts[0].(*ast.SelectStmt).Where
+	if got, want := stmts.File.Position(where.Pos()).String(), "3:7"; got != want {
+		t.Errorf("Position: got %q, want %q", got, want)
+	}
+}
+
+// TestParseSpecPositions checks that the span of each node is within
+// the span of its parent.
+func TestParseSpecPositions(t *testing.T) {
+	des, err := os.ReadDir("./testdata/spec")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	for _, de := range des {
+		if !strings.HasSuffix(de.Name(), ".pgql") {
+			continue
+		}
+
+		de := de
+		t.Run(de.Name(), func(t *testing.T) {
+			t.Parallel()
+
+			bs, err := os.ReadFile(filepath.Join("./testdata/spec", de.Name()))
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			bs = append(bs, ';', '\n')
+
+			stmts, err := Parse(bytes.NewReader(bs))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			var parents []ast.Node
+			ast.Inspect(stmts.Stmts[0], func(n ast.Node) bool {
+				if n == nil {
+					parents = parents[:len(parents)-1]
+					return true
+				}
+				parents = append(parents, n)
+
+				// Implicit literals have no position.
+				if lit, ok := n.(*ast.BasicLit); ok && !lit.Pos().IsValid() {
+					return true
+				}
+				if !n.Pos().IsValid() || n.End() <= n.Pos() {
+					t.Errorf("%T: invalid span [%d, %d)", n, n.Pos(), n.End())
+				}
+				if len(parents) > 1 {
+					p := parents[len(parents)-2]
+					if n.Pos() < p.Pos() || n.End() > p.End() {
+						t.Errorf("%T [%d, %d) is not within %T [%d, %d)", n, n.Pos(), n.End(), p, p.Pos(), p.End())
+					}
+				}
+				return true
+			})
+		})
+	}
+}
+
+func TestParseFragments(t *testing.T) {
+	tsts := []struct {
+		Name    string
+		Parse   func(string) (ast.Node, error)
+		Input   string
+		Want    string
+		WantErr string
+	}{
+		{"expr", parseExprNode, "a.x > 1 AND b IS NOT NULL", "*ast.OpExpr a.x > 1 AND b IS NOT NULL", ""},
+		{"exprTrailing", parseExprNode, "a b", "", "at 1:3: syntax error: unexpected UNQUOTED_IDENTIFIER"},
+		{"exprEmpty", parseExprNode, "", "", "at 1:1: syntax error: unexpected $end"},
+
+		{"path", parsePathPatternNode, "(a)-[e:knows]->(b)", "*ast.PathPattern (a)-[e:knows]->(b)", ""},
+		{"pathShortest", parsePathPatternNode, "ANY SHORTEST (a)-[e]->*(b)", "*ast.PathPattern ANY SHORTEST (a)-[e]->*(b)", ""},
+		{"pathWhere", parsePathPatternNode, "(a) WHERE a.x = 1", "", "at 1:5: syntax error: unexpected WHERE"},
+
+		{"stmt", parseStatementNode, "SELECT a FROM MATCH (a)", "*ast.SelectStmt SELECT a FROM MATCH (a)", ""},
+		{"stmtSemicolon", parseStatementNode, "DROP PROPERTY GRAPH g;", "*ast.DropStmt DROP PROPERTY GRAPH g", ""},
+		{"stmtNonReserved", parseStatementNode, "SELECT SUBSTRING(for.substring FROM 1 FOR 2) FROM MATCH (for)", "*ast.SelectStmt SELECT SUBSTRING(for.substring FROM 1 FOR 2) FROM MATCH (for)", ""},
+		{"stmtTrailing", parseStatementNode, "DROP PROPERTY GRAPH g; DROP PROPERTY GRAPH h", "*ast.DropStmt DROP PROPERTY GRAPH g", "at 1:24: syntax error: unexpected DROP"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			n, err := tst.Parse(tst.Input)
+			if tst.WantErr == "" && err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			} else if tst.WantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tst.WantErr)) {
+				t.Errorf("Parse: got error %v, want %q", err, tst.WantErr)
+			}
+
+			var got string
+			if n != nil {
+				src := []rune(tst.Input)
+				got = fmt.Sprintf("%T %s", n, string(src[n.Pos()-1:n.End()-1]))
+			}
+			if got != tst.Want {
+				t.Errorf("Parse: got %q, want %q", got, tst.Want)
+			}
+		})
+	}
+}
+
+func parseExprNode(s string) (ast.Node, error) {
+	e, err := ParseExpr(s)
+	if e == nil {
+		return nil, err
+	}
+	return e, err
+}
+
+func parsePathPatternNode(s string) (ast.Node, error) {
+	p, err := ParsePathPattern(s)
+	if p == nil {
+		return nil, err
+	}
+	return p, err
+}
+
+func parseStatementNode(s string) (ast.Node, error) {
+	stmt, err := ParseStatement(s)
+	if stmt == nil {
+		return nil, err
+	}
+	return stmt, err
+}
+
+func TestParseStringLiteral(t *testing.T) {
+	tsts := []struct {
+		Input string
+		Want  string
+	}{
+		{`'it''s'`, `it's`},
+		{`''`, ``},
+		{`DATE '2000-01-02'`, `2000-01-02`},
+		{`INTERVAL '3' DAY`, `3`},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Input, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := ParseExpr(tst.Input)
+			if err != nil {
+				t.Fatalf("ParseExpr failed: %v", err)
+			}
+			lit, ok := e.(*ast.BasicLit)
+			if !ok {
+				t.Fatalf("ParseExpr: got %T, want *ast.BasicLit", e)
+			}
+			if lit.Text != tst.Want {
+				t.Errorf("Text: got %q, want %q", lit.Text, tst.Want)
+			}
+		})
+	}
+}
+
+func TestParseInvalidLiteral(t *testing.T) {
+	tsts := []struct {
+		Input   string
+		WantErr string
+	}{
+		{`DATE '2000-13-01'`, "at 1:6: invalid DATE literal '2000-13-01'"},
+		{`TIME '12:60:00'`, "at 1:6: invalid TIME literal '12:60:00'"},
+		{`TIMESTAMP '2000-01-02'`, "at 1:11: invalid TIMESTAMP literal '2000-01-02'"},
+		{`INTERVAL 'x' DAY`, "at 1:10: invalid INTERVAL literal 'x' DAY"},
+		{`1 + DATE '2000-13-01' + 2`, "at 1:10: invalid DATE literal '2000-13-01'"},
+		{`1 + INTERVAL 'x' DAY + 2`, "at 1:14: invalid INTERVAL literal 'x' DAY"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Input, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseExpr(tst.Input)
+			if err == nil || err.Error() != tst.WantErr {
+				t.Errorf("ParseExpr: got error %v, want %q", err, tst.WantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Package scanner splits PGQL source into tokens. It uses the same
+// rules as the parser, and is meant for tools working on tokens, like
+// syntax highlighters.
+package scanner
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/itergia/pgql-go/parser/internal/parser"
+)
+
+// A Scanner returns the tokens of its input, one at a time.
+type Scanner = parser.Scanner
+
+// Mode controls the behavior of a Scanner.
+type Mode = parser.Mode
+
+// ScanComments makes Scan return comments as token.COMMENT. Otherwise,
+// they are skipped.
+const ScanComments = parser.ScanComments
+
+// An ErrorHandler is called with the position and message of each
+// error found by a Scanner.
+type ErrorHandler = parser.ErrorHandler
+
+// New returns a Scanner reading UTF-8 source from r. If err is not
+// nil, it is called for each error. If the reader is a
+// *bufio.Reader, it is used directly, otherwise a new bufio.Reader is
+// created.
+func New(r io.Reader, err ErrorHandler, mode Mode) *Scanner {
+	if br, ok := r.(parser.RuneReader); ok {
+		return parser.NewScanner(br, err, mode)
+	}
+
+	return parser.NewScanner(bufio.NewReader(r), err, mode)
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/token"
+)
+
+func TestScanner(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Mode  Mode
+		Want  []string
+	}{
+		{
+			"select",
+			"SELECT a.name AS n FROM MATCH (a)-[e:knows]->(b) WHERE e.since > 2000",
+			0,
+			[]string{
+				"1:1 SELECT SELECT", "1:8 IDENT a", "1:9 . ", "1:10 IDENT name", "1:15 AS AS", "1:18 IDENT n",
+				"1:20 FROM FROM", "1:25 MATCH MATCH", "1:31 ( ", "1:32 IDENT a", "1:33 ) ", "1:34 -[ ", "1:36 IDENT e", "1:37 : ", "1:38 IDENT knows", "1:43 ]-> ", "1:46 ( ", "1:47 IDENT b", "1:48 ) ",
+				"1:50 WHERE WHERE", "1:56 IDENT e", "1:57 . ", "1:58 IDENT since", "1:64 > ", "1:66 UNSIGNED_INTEGER 2000",
+				"1:70 EOF ",
+			},
+		},
+		{
+			"literals",
+			`'it''s' "Quoted" 4.5 true`,
+			0,
+			[]string{"1:1 STRING_LITERAL 'it''s'", `1:9 QUOTED_IDENT "Quoted"`, "1:18 UNSIGNED_DECIMAL 4.5", "1:22 TRUE true", "1:26 EOF "},
+		},
+		{
+			"skipComments",
+			"/* a */ x /* b */",
+			0,
+			[]string{"1:9 IDENT x", "1:18 EOF "},
+		},
+		{
+			"comments",
+			"/* a */ x\n/* b */",
+			ScanComments,
+			[]string{"1:1 COMMENT /* a */", "1:9 IDENT x", "2:1 COMMENT /* b */", "2:8 EOF "},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			s := New(strings.NewReader(tst.Input), func(pos token.Position, msg string) {
+				t.Errorf("Scan failed: %v: %s", pos, msg)
+			}, tst.Mode)
+			var got []string
+			for {
+				pos, tok, lit := s.Scan()
+				got = append(got, fmt.Sprintf("%v %v %s", pos, tok, lit))
+				if tok == token.EOF {
+					break
+				}
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Scan: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScannerError(t *testing.T) {
+	var errs []string
+	s := New(strings.NewReader("a # b"), func(pos token.Position, msg string) {
+		errs = append(errs, fmt.Sprintf("%v: %s", pos, msg))
+	}, 0)
+
+	var got []string
+	for {
+		_, tok, lit := s.Scan()
+		got = append(got, fmt.Sprintf("%v %s", tok, lit))
+		if tok == token.EOF {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{"IDENT a", "ILLEGAL #", "IDENT b", "EOF "}, got); diff != "" {
+		t.Errorf("Scan: +got, -want:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"1:3: unexpected character '#'"}, errs); diff != "" {
+		t.Errorf("ErrorHandler: +got, -want:\n%s", diff)
+	}
+	if s.ErrorCount != 1 {
+		t.Errorf("ErrorCount: got %d, want 1", s.ErrorCount)
+	}
+}
@@ -0,0 +1,1042 @@
+// Package printer implements printing of AST nodes as PGQL text.
+//
+// The output is canonical: keywords are upper-case, each clause starts
+// on a new line, as does each MATCH and each path pattern of a graph
+// pattern, and expressions are parenthesized only where the grammar
+// requires it. Parsing the output yields an AST equal to the
+// input, apart from positions.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/token"
+)
+
+// Fprint pretty-prints an AST node to w. The node must be an
+// ast.Stmt, ast.Expr or ast.ModClause, or one of *ast.PathMacroClause,
+// *ast.SelectElem, *ast.MatchClause, *ast.PathPattern,
+// *ast.NamedExpr or *ast.OrderTerm. Statements are printed without
+// the terminating semicolon.
+//
+// If node is a *CommentedNode, the comments are printed before the
+// nodes they are associated with. Comments of nodes that are not
+// printed on their own, e.g. because the node is implicit in the
+// output, are printed last.
+func Fprint(w io.Writer, node ast.Node) error {
+	var p printer
+	if cn, ok := node.(*CommentedNode); ok {
+		p.cmap = cn.Comments
+		node = cn.Node
+	}
+
+	if err := p.node(node); err != nil {
+		return err
+	}
+	p.remainingComments(node)
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// A CommentedNode bundles an AST node and the comments associated
+// with it, as returned by the parser.
+type CommentedNode struct {
+	Node     ast.Node
+	Comments ast.CommentMap
+}
+
+func (cn *CommentedNode) Pos() ast.Pos { return cn.Node.Pos() }
+func (cn *CommentedNode) End() ast.Pos { return cn.Node.End() }
+
+// printer holds the state while printing a single node.
+type printer struct {
+	buf    bytes.Buffer
+	indent int
+
+	cmap    ast.CommentMap
+	printed map[*ast.CommentGroup]bool
+}
+
+// node prints any supported node.
+func (p *printer) node(node ast.Node) error {
+	switch n := node.(type) {
+	case ast.Stmt:
+		return p.stmt(n)
+	case ast.Expr:
+		p.expr(n)
+	case ast.ModClause:
+		p.modClause(n)
+	case *ast.PathMacroClause:
+		p.pathMacro(n)
+	case *ast.SelectElem:
+		p.selectElem(n)
+	case *ast.MatchClause:
+		p.matchClause(n)
+	case *ast.PathPattern:
+		p.pathPattern(n)
+	case *ast.NamedExpr:
+		p.namedExpr(n)
+	case *ast.OrderTerm:
+		p.orderTerm(n)
+	default:
+		return fmt.Errorf("printer.Fprint: unsupported node type %T", node)
+	}
+
+	return nil
+}
+
+// print writes all arguments, which must be strings or runes.
+func (p *printer) print(args ...interface{}) {
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			p.buf.WriteString(arg)
+		case rune:
+			p.buf.WriteRune(arg)
+		default:
+			panic(fmt.Errorf("printer.print: unexpected argument type %T", arg))
+		}
+	}
+}
+
+// newline starts a new line at the current indentation.
+func (p *printer) newline() {
+	p.buf.WriteByte('\n')
+	for i := 0; i < p.indent; i++ {
+		p.buf.WriteString("  ")
+	}
+}
+
+// atLineStart returns true if nothing but indentation has been
+// printed on the current line.
+func (p *printer) atLineStart() bool {
+	bs := bytes.TrimRight(p.buf.Bytes(), " ")
+	return len(bs) == 0 || bs[len(bs)-1] == '\n'
+}
+
+// comments prints the comment groups associated with n that haven't
+// been printed yet. At the start of a line, each comment is put on a
+// line of its own. Otherwise, they are printed inline.
+func (p *printer) comments(n ast.Node) {
+	for _, g := range p.cmap[n] {
+		p.commentGroup(g)
+	}
+}
+
+func (p *printer) commentGroup(g *ast.CommentGroup) {
+	if p.printed[g] {
+		return
+	}
+	if p.printed == nil {
+		p.printed = map[*ast.CommentGroup]bool{}
+	}
+	p.printed[g] = true
+
+	bol := p.atLineStart()
+	for _, c := range g.List {
+		p.print(c.Text)
+		if bol {
+			p.newline()
+		} else {
+			p.print(" ")
+		}
+	}
+}
+
+// remainingComments prints the comments of nodes reachable from node
+// that haven't been printed yet.
+func (p *printer) remainingComments(node ast.Node) {
+	if len(p.cmap) == 0 {
+		return
+	}
+
+	var gs []*ast.CommentGroup
+	for _, g := range p.cmap.Filter(node).Comments() {
+		if !p.printed[g] {
+			gs = append(gs, g)
+		}
+	}
+	for _, g := range gs {
+		p.print(" ")
+		p.commentGroup(g)
+	}
+	if len(gs) > 0 {
+		p.buf.Truncate(len(bytes.TrimRight(p.buf.Bytes(), " ")))
+	}
+}
+
+// Statements
+
+func (p *printer) stmt(stmt ast.Stmt) error {
+	switch stmt := stmt.(type) {
+	case *ast.SelectStmt:
+		p.selectStmt(stmt)
+	case *ast.ModifyStmt:
+		p.modifyStmt(stmt)
+	case *ast.CreateStmt:
+		p.createStmt(stmt)
+	case *ast.DropStmt:
+		p.comments(stmt)
+		p.print("DROP PROPERTY GRAPH ")
+		p.qident(stmt.GraphName)
+	default:
+		return fmt.Errorf("printer.Fprint: unsupported statement type %T", stmt)
+	}
+
+	return nil
+}
+
+func (p *printer) selectStmt(stmt *ast.SelectStmt) {
+	p.comments(stmt)
+	p.pathMacros(stmt.PathMacros)
+
+	p.print("SELECT ")
+	if stmt.Distinct {
+		p.print("DISTINCT ")
+	}
+	if len(stmt.Sels) == 0 {
+		p.print("*")
+	}
+	for i, sel := range stmt.Sels {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.selectElem(sel)
+	}
+
+	p.fromClause(stmt.From)
+	p.tailClauses(stmt.Where, stmt.GroupBy, stmt.Having, stmt.OrderBy, stmt.Limit, stmt.Offset)
+}
+
+func (p *printer) modifyStmt(stmt *ast.ModifyStmt) {
+	p.comments(stmt)
+	p.pathMacros(stmt.PathMacros)
+
+	for i, mod := range stmt.Mods {
+		if i > 0 {
+			p.newline()
+		}
+		p.modClause(mod)
+	}
+
+	p.fromClause(stmt.From)
+	p.tailClauses(stmt.Where, stmt.GroupBy, stmt.Having, stmt.OrderBy, stmt.Limit, stmt.Offset)
+}
+
+func (p *printer) pathMacros(pms []*ast.PathMacroClause) {
+	for _, pm := range pms {
+		p.pathMacro(pm)
+		p.newline()
+	}
+}
+
+func (p *printer) pathMacro(pm *ast.PathMacroClause) {
+	p.comments(pm)
+	p.print("PATH ")
+	p.ident(pm.Name)
+	p.print(" AS ")
+	p.pathPattern(pm.Pattern)
+	if pm.Where != nil {
+		p.print(" WHERE ")
+		p.expr(pm.Where)
+	}
+}
+
+func (p *printer) selectElem(sel *ast.SelectElem) {
+	p.comments(sel)
+	if sel.AllOf != nil {
+		p.ident(sel.AllOf)
+		p.print(".*")
+		if sel.Prefix != nil {
+			p.print(" PREFIX ")
+			p.basicLit(sel.Prefix)
+		}
+		return
+	}
+
+	p.namedExpr(sel.Named)
+}
+
+func (p *printer) namedExpr(ne *ast.NamedExpr) {
+	p.comments(ne)
+	p.expr(ne.Expr)
+	if ne.Name != nil {
+		p.print(" AS ")
+		p.ident(ne.Name)
+	}
+}
+
+func (p *printer) fromClause(ms []*ast.MatchClause) {
+	if len(ms) == 0 {
+		return
+	}
+
+	p.newline()
+	p.comments(ms[0])
+	p.print("FROM ")
+	p.indent++
+	for i, m := range ms {
+		if i > 0 {
+			p.print(",")
+			p.newline()
+		}
+		p.matchClause(m)
+	}
+	p.indent--
+}
+
+// tailClauses prints the clauses following FROM, which are shared by
+// SelectStmt and ModifyStmt.
+func (p *printer) tailClauses(where ast.Expr, groupBy []*ast.NamedExpr, having ast.Expr, orderBy []*ast.OrderTerm, limit, offset ast.Expr) {
+	// Comments before a clause keyword are associated with the first
+	// node of the clause.
+	if where != nil {
+		p.newline()
+		p.comments(where)
+		p.print("WHERE ")
+		p.expr(where)
+	}
+	if len(groupBy) > 0 {
+		p.newline()
+		p.comments(groupBy[0])
+		p.print("GROUP BY ")
+		for i, ne := range groupBy {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.namedExpr(ne)
+		}
+	}
+	if having != nil {
+		p.newline()
+		p.comments(having)
+		p.print("HAVING ")
+		p.expr(having)
+	}
+	if len(orderBy) > 0 {
+		p.newline()
+		p.comments(orderBy[0])
+		p.print("ORDER BY ")
+		for i, ot := range orderBy {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.orderTerm(ot)
+		}
+	}
+	if limit != nil {
+		p.newline()
+		p.comments(limit)
+		p.print("LIMIT ")
+		p.expr(limit)
+	}
+	if offset != nil {
+		p.newline()
+		p.comments(offset)
+		p.print("OFFSET ")
+		p.expr(offset)
+	}
+}
+
+func (p *printer) orderTerm(ot *ast.OrderTerm) {
+	p.comments(ot)
+	p.expr(ot.Expr)
+	switch ot.Order {
+	case ast.AscOrder:
+		p.print(" ASC")
+	case ast.DescOrder:
+		p.print(" DESC")
+	}
+}
+
+// Graph Pattern Matching
+
+func (p *printer) matchClause(m *ast.MatchClause) {
+	p.comments(m)
+	p.print("MATCH ")
+	if len(m.Patterns) == 1 {
+		p.pathPattern(m.Patterns[0])
+	} else {
+		// A graph pattern has one path pattern per line.
+		p.print("(")
+		p.indent++
+		for i, pat := range m.Patterns {
+			if i > 0 {
+				p.print(",")
+			}
+			p.newline()
+			p.pathPattern(pat)
+		}
+		p.indent--
+		p.newline()
+		p.print(")")
+	}
+
+	if m.On != nil {
+		p.print(" ON ")
+		p.qident(m.On)
+	}
+
+	if m.Rows != nil {
+		switch m.Rows.Kind {
+		case ast.OneRowPerMatch:
+			p.print(" ONE ROW PER MATCH")
+		case ast.OneRowPerVertex:
+			p.print(" ONE ROW PER VERTEX (")
+			p.identList(m.Rows.Vars)
+			p.print(")")
+		case ast.OneRowPerStep:
+			p.print(" ONE ROW PER STEP (")
+			p.identList(m.Rows.Vars)
+			p.print(")")
+		}
+	}
+}
+
+func (p *printer) pathPattern(pat *ast.PathPattern) {
+	p.comments(pat)
+	switch pat.Cardinality {
+	case ast.AnyCardinality:
+		p.print("ANY ")
+	case ast.AllCardinality:
+		p.print("ALL ")
+	case ast.TopCardinality:
+		p.print("TOP ")
+		p.basicLit(pat.K)
+		p.print(" ")
+	}
+	switch pat.Metric {
+	case ast.LengthMetric:
+		p.print("SHORTEST ")
+	case ast.CostMetric:
+		p.print("CHEAPEST ")
+	}
+
+	for i, v := range pat.Vs {
+		if i > 0 {
+			p.print(" ")
+			p.pathPrimary(pat.Es[i-1])
+			p.print(" ")
+		}
+		p.vertexPattern(v)
+	}
+}
+
+func (p *printer) pathPrimary(pp *ast.PathPatternPrimary) {
+	p.comments(pp)
+	e := pp.Es[0]
+	switch {
+	case e.Reachability:
+		if e.Dir == ast.Incoming {
+			p.print("<-/")
+		} else {
+			p.print("-/")
+		}
+		p.labelPredicate(e.LabelAlts)
+		p.quantifier(pp.Quantity)
+		if e.Dir == ast.Incoming {
+			p.print("/-")
+		} else {
+			p.print("/->")
+		}
+		return
+
+	case len(pp.Vs) > 0 || pp.Where != nil || pp.Cost != nil:
+		p.print("(")
+		if v := indexOr(pp.Vs, 0); v != nil {
+			p.vertexPattern(v)
+			p.print(" ")
+		}
+		p.edgePattern(e)
+		if v := indexOr(pp.Vs, 1); v != nil {
+			p.print(" ")
+			p.vertexPattern(v)
+		}
+		if pp.Where != nil {
+			p.print(" WHERE ")
+			p.expr(pp.Where)
+		}
+		if pp.Cost != nil {
+			p.print(" COST ")
+			p.expr(pp.Cost)
+		}
+		p.print(")")
+
+	default:
+		p.edgePattern(e)
+	}
+
+	p.quantifier(pp.Quantity)
+}
+
+func (p *printer) vertexPattern(v *ast.VertexPattern) {
+	p.comments(v)
+	p.print("(")
+	p.variableSpec(v.Name, v.LabelAlts)
+	p.print(")")
+}
+
+func (p *printer) edgePattern(e *ast.EdgePattern) {
+	p.comments(e)
+	if e.Name == nil && len(e.LabelAlts) == 0 {
+		switch e.Dir {
+		case ast.Outgoing:
+			p.print("->")
+		case ast.Incoming:
+			p.print("<-")
+		default:
+			p.print("-")
+		}
+		return
+	}
+
+	if e.Dir == ast.Incoming {
+		p.print("<-[")
+	} else {
+		p.print("-[")
+	}
+	p.variableSpec(e.Name, e.LabelAlts)
+	if e.Dir == ast.Outgoing {
+		p.print("]->")
+	} else {
+		p.print("]-")
+	}
+}
+
+func (p *printer) variableSpec(name *ast.Ident, labels []*ast.Ident) {
+	if name != nil {
+		p.ident(name)
+	}
+	p.labelPredicate(labels)
+}
+
+func (p *printer) labelPredicate(labels []*ast.Ident) {
+	for i, l := range labels {
+		if i == 0 {
+			p.print(":")
+		} else {
+			p.print("|")
+		}
+		p.ident(l)
+	}
+}
+
+func (p *printer) quantifier(q *ast.Quantifier) {
+	switch {
+	case q == nil:
+	case !q.Group && q.Min == nil && q.Max != nil && q.Max.S == "1":
+		p.print("?")
+	case q.Min == nil && q.Max == nil:
+		p.print("*")
+	case q.Min != nil && q.Min.S == "1" && q.Max == nil:
+		p.print("+")
+	case q.Min == nil:
+		p.print("{,", q.Max.S, "}")
+	case q.Max == nil:
+		p.print("{", q.Min.S, ",}")
+	case q.Min.S == q.Max.S:
+		p.print("{", q.Min.S, "}")
+	default:
+		p.print("{", q.Min.S, ",", q.Max.S, "}")
+	}
+}
+
+// Graph Modification
+
+func (p *printer) modClause(mod ast.ModClause) {
+	p.comments(mod)
+	switch mod := mod.(type) {
+	case *ast.InsertClause:
+		p.print("INSERT ")
+		if mod.Into != nil {
+			p.print("INTO ")
+			p.qident(mod.Into)
+			p.print(" ")
+		}
+		for i, v := range mod.Vs {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.print("VERTEX")
+			if v.Var != nil {
+				p.print(" ")
+				p.ident(v.Var)
+			}
+			p.labelsAndProps(v.Labels, v.Props)
+		}
+		for i, e := range mod.Es {
+			if i > 0 || len(mod.Vs) > 0 {
+				p.print(", ")
+			}
+			p.print("EDGE")
+			if e.Var != nil {
+				p.print(" ")
+				p.ident(e.Var)
+			}
+			p.print(" BETWEEN ")
+			p.ident(e.Source)
+			p.print(" AND ")
+			p.ident(e.Dest)
+			p.labelsAndProps(e.Labels, e.Props)
+		}
+
+	case *ast.UpdateClause:
+		p.print("UPDATE ")
+		for i, u := range mod.Updates {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.ident(u.Var)
+			p.print(" SET (")
+			p.propAssignments(u.Props)
+			p.print(")")
+		}
+
+	case *ast.DeleteClause:
+		p.print("DELETE ")
+		p.identList(mod.Vars)
+	}
+}
+
+func (p *printer) labelsAndProps(labels []*ast.Ident, props []*ast.PropAssignment) {
+	if len(labels) > 0 {
+		p.print(" LABELS (")
+		p.identList(labels)
+		p.print(")")
+	}
+	if len(props) > 0 {
+		p.print(" PROPERTIES (")
+		p.propAssignments(props)
+		p.print(")")
+	}
+}
+
+func (p *printer) propAssignments(pas []*ast.PropAssignment) {
+	for i, pa := range pas {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.qident(pa.Prop)
+		p.print(" = ")
+		p.expr(pa.Value)
+	}
+}
+
+// Creating a Property Graph
+
+func (p *printer) createStmt(stmt *ast.CreateStmt) {
+	p.comments(stmt)
+	p.print("CREATE PROPERTY GRAPH ")
+	p.qident(stmt.GraphName)
+
+	p.indent++
+	p.newline()
+	p.print("VERTEX TABLES (")
+	p.indent++
+	for i, vt := range stmt.VertexTables {
+		if i > 0 {
+			p.print(",")
+		}
+		p.newline()
+		p.comments(vt)
+		p.tableName(vt.TableName, vt.TableAlias, vt.Keys)
+		p.labelAndProps(vt.Label, vt.Props)
+	}
+	p.indent--
+	p.newline()
+	p.print(")")
+
+	if len(stmt.EdgeTables) > 0 {
+		p.newline()
+		p.print("EDGE TABLES (")
+		p.indent++
+		for i, et := range stmt.EdgeTables {
+			if i > 0 {
+				p.print(",")
+			}
+			p.newline()
+			p.comments(et)
+			p.tableName(et.TableName, et.TableAlias, et.Keys)
+			p.print(" SOURCE ")
+			p.vertexTableRef(et.Source)
+			p.print(" DESTINATION ")
+			p.vertexTableRef(et.Dest)
+			p.labelAndProps(et.Label, et.Props)
+		}
+		p.indent--
+		p.newline()
+		p.print(")")
+	}
+	p.indent--
+}
+
+func (p *printer) tableName(name *ast.QIdent, alias *ast.Ident, keys []*ast.Ident) {
+	p.qident(name)
+	if alias != nil {
+		p.print(" AS ")
+		p.ident(alias)
+	}
+	if len(keys) > 0 {
+		p.print(" KEY (")
+		p.identList(keys)
+		p.print(")")
+	}
+}
+
+func (p *printer) vertexTableRef(ref *ast.VertexTableRef) {
+	if len(ref.Keys) == 0 {
+		p.qident(ref.TableName)
+		return
+	}
+
+	p.print("KEY (")
+	p.identList(ref.Keys)
+	p.print(") REFERENCES ")
+	p.qident(ref.TableName)
+	p.print(" (")
+	p.identList(ref.Columns)
+	p.print(")")
+}
+
+func (p *printer) labelAndProps(label *ast.Ident, props *ast.PropsClause) {
+	if label != nil {
+		p.print(" LABEL ")
+		p.ident(label)
+	}
+
+	switch {
+	case props == nil:
+	case props.None:
+		p.print(" NO PROPERTIES")
+	case len(props.Exprs) > 0:
+		p.print(" PROPERTIES (")
+		for i, pe := range props.Exprs {
+			if i > 0 {
+				p.print(", ")
+			}
+			if pe.CastAs != nil {
+				p.expr(pe.CastAs)
+			} else {
+				p.ident(pe.Column)
+			}
+			if pe.Name != nil {
+				p.print(" AS ")
+				p.ident(pe.Name)
+			}
+		}
+		p.print(")")
+	default:
+		p.print(" PROPERTIES ARE ALL COLUMNS")
+		if len(props.Except) > 0 {
+			p.print(" EXCEPT (")
+			p.identList(props.Except)
+			p.print(")")
+		}
+	}
+}
+
+// Functions and Expressions
+
+// Operator precedence levels, from the precedence declarations in
+// pgql.y. Higher binds tighter.
+const (
+	precOr = iota + 1
+	precAnd
+	precNot
+	precCompare
+	precIn
+	precAdd
+	precMul
+	precConcat
+	precIs
+	precUnary
+	precAtom
+)
+
+// binaryPrecs maps binary operators to their precedence.
+var binaryPrecs = map[ast.Op]int{
+	ast.OrOp:     precOr,
+	ast.AndOp:    precAnd,
+	ast.EqOp:     precCompare,
+	ast.NeOp:     precCompare,
+	ast.LtOp:     precCompare,
+	ast.GtOp:     precCompare,
+	ast.LeOp:     precCompare,
+	ast.GeOp:     precCompare,
+	ast.AddOp:    precAdd,
+	ast.SubOp:    precAdd,
+	ast.MulOp:    precMul,
+	ast.DivOp:    precMul,
+	ast.ModOp:    precMul,
+	ast.ConcatOp: precConcat,
+}
+
+// exprPrec returns the precedence of the expression's outermost
+// operator.
+func exprPrec(e ast.Expr) int {
+	switch e := e.(type) {
+	case *ast.OpExpr:
+		switch e.Op {
+		case ast.NegOp:
+			return precUnary
+		case ast.NotOp:
+			return precNot
+		}
+		if prec, ok := binaryPrecs[e.Op]; ok {
+			return prec
+		}
+	case *ast.IsNullExpr:
+		return precIs
+	case *ast.InExpr:
+		if e.Inv {
+			// The NOT token decides when NOT IN is shifted.
+			return precNot
+		}
+		return precIn
+	}
+
+	return precAtom
+}
+
+// operand prints e, parenthesized if its precedence is less than
+// prec.
+func (p *printer) operand(e ast.Expr, prec int) {
+	if exprPrec(e) < prec {
+		p.comments(e)
+		p.print("(")
+		p.expr(e)
+		p.print(")")
+		return
+	}
+
+	p.expr(e)
+}
+
+func (p *printer) expr(e ast.Expr) {
+	p.comments(e)
+
+	switch e := e.(type) {
+	case *ast.OpExpr:
+		p.opExpr(e)
+
+	case *ast.AggregateExpr:
+		p.aggregateExpr(e)
+
+	case *ast.ExtractExpr:
+		p.print("EXTRACT(", e.Field.String(), " FROM ")
+		p.expr(e.Arg)
+		p.print(")")
+
+	case *ast.SubstringExpr:
+		p.print("SUBSTRING(")
+		p.expr(e.Arg)
+		p.print(" FROM ")
+		p.expr(e.Start)
+		if e.Length != nil {
+			p.print(" FOR ")
+			p.expr(e.Length)
+		}
+		p.print(")")
+
+	case *ast.IsNullExpr:
+		p.operand(e.Arg, precIs)
+		if e.Not {
+			p.print(" IS NOT NULL")
+		} else {
+			p.print(" IS NULL")
+		}
+
+	case *ast.ExistsExpr:
+		p.print("EXISTS ")
+		p.expr(e.Query)
+
+	case *ast.LabelExpr:
+		if e.Plural {
+			p.print("LABELS(")
+		} else {
+			p.print("LABEL(")
+		}
+		p.expr(e.Arg)
+		p.print(")")
+
+	case *ast.CallExpr:
+		p.qident(e.Func)
+		p.print("(")
+		p.exprList(e.Args)
+		p.print(")")
+
+	case *ast.CastExpr:
+		p.print("CAST(")
+		p.expr(e.Arg)
+		p.print(" AS ", e.TypeKind.String(), ")")
+
+	case *ast.CaseExpr:
+		p.print("CASE")
+		if e.Subject != nil {
+			p.print(" ")
+			p.expr(e.Subject)
+		}
+		for _, w := range e.Whens {
+			p.print(" WHEN ")
+			p.expr(w.Cond)
+			p.print(" THEN ")
+			p.expr(w.Then)
+		}
+		if e.Else != nil {
+			p.print(" ELSE ")
+			p.expr(e.Else)
+		}
+		p.print(" END")
+
+	case *ast.InExpr:
+		if e.Inv {
+			p.operand(e.Subject, precCompare)
+			p.print(" NOT IN ")
+		} else {
+			p.operand(e.Subject, precIn)
+			p.print(" IN ")
+		}
+		if e.BindVar != nil {
+			p.expr(e.BindVar)
+		} else {
+			p.print("(")
+			p.exprList(e.Objects)
+			p.print(")")
+		}
+
+	case *ast.SubqueryExpr:
+		p.print("(")
+		p.indent++
+		p.newline()
+		p.selectStmt(e.Query)
+		p.indent--
+		p.newline()
+		p.print(")")
+
+	case *ast.QIdent:
+		p.qident(e)
+
+	case *ast.Ident:
+		p.ident(e)
+
+	case *ast.BasicLit:
+		p.basicLit(e)
+
+	case *ast.BindVar:
+		p.print("?")
+
+	default:
+		panic(fmt.Errorf("printer: unexpected expression type %T", e))
+	}
+}
+
+func (p *printer) opExpr(e *ast.OpExpr) {
+	if prec, ok := binaryPrecs[e.Op]; ok {
+		// Binary operators are left-associative, except comparisons,
+		// which are non-associative.
+		lprec := prec
+		if prec == precCompare {
+			lprec++
+		}
+		p.operand(e.Args[0], lprec)
+		p.print(" ", e.Op.String(), " ")
+		p.operand(e.Args[1], prec+1)
+		return
+	}
+
+	switch e.Op {
+	case ast.NegOp:
+		p.print("-")
+		p.operand(e.Args[0], precUnary)
+
+	case ast.NotOp:
+		p.print("NOT ")
+		p.operand(e.Args[0], precNot)
+
+	default:
+		panic(fmt.Errorf("printer: unexpected operator %v", e.Op))
+	}
+}
+
+func (p *printer) aggregateExpr(e *ast.AggregateExpr) {
+	p.print(e.Func.String(), "(")
+	if e.Star {
+		p.print("*)")
+		return
+	}
+	if e.Distinct {
+		p.print("DISTINCT ")
+	}
+	p.expr(e.Arg)
+	if e.Separator != nil {
+		p.print(", ")
+		p.expr(e.Separator)
+	}
+	p.print(")")
+}
+
+func (p *printer) exprList(es []ast.Expr) {
+	for i, e := range es {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.expr(e)
+	}
+}
+
+func (p *printer) basicLit(lit *ast.BasicLit) {
+	p.comments(lit)
+	switch lit.Kind {
+	case ast.BoolKind:
+		p.print(strings.ToUpper(lit.S))
+	case ast.DateKind:
+		p.print("DATE ", lit.S)
+	case ast.TimeKind:
+		p.print("TIME ", lit.S)
+	case ast.TimestampKind:
+		p.print("TIMESTAMP ", lit.S)
+	case ast.IntervalKind:
+		p.print("INTERVAL ", lit.S)
+	default:
+		p.print(lit.S)
+	}
+}
+
+// Other Syntactic Rules
+
+func (p *printer) qident(qid *ast.QIdent) {
+	p.comments(qid)
+	for i, id := range qid.Names {
+		if i > 0 {
+			p.print(".")
+		}
+		p.ident(id)
+	}
+}
+
+func (p *printer) identList(ids []*ast.Ident) {
+	for i, id := range ids {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.ident(id)
+	}
+}
+
+func (p *printer) ident(id *ast.Ident) {
+	p.comments(id)
+	p.print(token.QuoteIdentifier(id.Name))
+}
+
+func indexOr[T any](s []T, i int) T {
+	var zero T
+	if i < len(s) {
+		return s[i]
+	}
+	return zero
+}
@@ -0,0 +1,152 @@
+package printer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestFprint(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"select", "select * from match (n)", "SELECT *\nFROM MATCH (n)"},
+		{"selectElems", "SELECT DISTINCT n.a AS x, n.* PREFIX 'p_' FROM MATCH (n:Person|Company)", "SELECT DISTINCT n.a AS x, n.* PREFIX 'p_'\nFROM MATCH (n:Person|Company)"},
+		{"clauses", "SELECT n FROM MATCH (n) WHERE n.a > 1 GROUP BY n.b AS b HAVING COUNT(*) > 1 ORDER BY b DESC, n OFFSET 2 LIMIT ?", "SELECT n\nFROM MATCH (n)\nWHERE n.a > 1\nGROUP BY n.b AS b\nHAVING COUNT(*) > 1\nORDER BY b DESC, n\nLIMIT ?\nOFFSET 2"},
+		{"edges", "SELECT * FROM MATCH (a) -[e:x]-> (b) <-[:y]- (c) - (d) -> (e) <- (f) -[g]- (h)", "SELECT *\nFROM MATCH (a) -[e:x]-> (b) <-[:y]- (c) - (d) -> (e) <- (f) -[g]- (h)"},
+		{"graphPattern", "SELECT * FROM MATCH ((a) -> (b), (b) -> (c)) ON g ONE ROW PER VERTEX (v), MATCH (x)", "SELECT *\nFROM MATCH (\n    (a) -> (b),\n    (b) -> (c)\n  ) ON g ONE ROW PER VERTEX (v),\n  MATCH (x)"},
+		{"reachability", "SELECT * FROM MATCH (a) -/:knows+/-> (b) <-/:likes/- (c)", "SELECT *\nFROM MATCH (a) -/:knows+/-> (b) <-/:likes/- (c)"},
+		{"paths", "PATH p AS (a) -> (b) WHERE a.x = 1 SELECT * FROM MATCH TOP 2 CHEAPEST ((a) (-[e]-> COST e.w){1,3} (b))", "PATH p AS (a) -> (b) WHERE a.x = 1\nSELECT *\nFROM MATCH TOP 2 CHEAPEST (a) (-[e]-> COST e.w){1,3} (b)"},
+		{"quantifiers", "SELECT * FROM MATCH ANY (a) ->* (b), MATCH ALL SHORTEST (a) ->? (b), MATCH ALL (a) ->{,4} (b), MATCH ANY (a) ->{2,} (b), MATCH ANY (a) ->{2} (b)", "SELECT *\nFROM MATCH ANY (a) ->* (b),\n  MATCH ALL SHORTEST (a) ->? (b),\n  MATCH ALL (a) ->{,4} (b),\n  MATCH ANY (a) ->{2,} (b),\n  MATCH ANY (a) ->{2} (b)"},
+		{"subquery", "SELECT a FROM MATCH (a) WHERE EXISTS (SELECT b FROM MATCH (a) -> (b))", "SELECT a\nFROM MATCH (a)\nWHERE EXISTS (\n  SELECT b\n  FROM MATCH (a) -> (b)\n)"},
+
+		{"precedenceMul", "SELECT (1 + 2) * 3, 1 + 2 * 3, 1 - (2 - 3), (1 - 2) - 3 FROM MATCH (n)", "SELECT (1 + 2) * 3, 1 + 2 * 3, 1 - (2 - 3), 1 - 2 - 3\nFROM MATCH (n)"},
+		{"precedenceLogical", "SELECT * FROM MATCH (n) WHERE (a OR b) AND NOT (c AND d) OR NOT e = f", "SELECT *\nFROM MATCH (n)\nWHERE (a OR b) AND NOT (c AND d) OR NOT e = f"},
+		{"precedenceCompare", "SELECT (a = b) = c, a = (b = c), (NOT a) = b FROM MATCH (n)", "SELECT (a = b) = c, a = (b = c), (NOT a) = b\nFROM MATCH (n)"},
+		{"precedenceUnary", "SELECT -(a + b), - -a, -a * b, a - -b, (-a) IS NULL, -(a IS NULL) FROM MATCH (n)", "SELECT -(a + b), --a, -a * b, a - -b, -a IS NULL, -(a IS NULL)\nFROM MATCH (n)"},
+		{"precedenceIs", "SELECT (a + b) IS NOT NULL, a + b IS NULL, a || b * c FROM MATCH (n)", "SELECT (a + b) IS NOT NULL, a + b IS NULL, a || b * c\nFROM MATCH (n)"},
+		{"precedenceIn", "SELECT (a + 1) IN (1, 2), a = b NOT IN ?, (a = b) IN (true), a = (b NOT IN ?), a AND b NOT IN ? FROM MATCH (n)", "SELECT a + 1 IN (1, 2), a = b NOT IN ?, (a = b) IN (TRUE), a = (b NOT IN ?), a AND b NOT IN ?\nFROM MATCH (n)"},
+
+		{"functions", "SELECT f(a), m.g(), COUNT(DISTINCT a), LISTAGG(a, ';'), SUBSTRING(a FROM 1 FOR 2), EXTRACT(year FROM a), LABELS(a), CAST(a AS TIMESTAMP WITH TIME ZONE) FROM MATCH (n)", "SELECT f(a), m.g(), COUNT(DISTINCT a), LISTAGG(a, ';'), SUBSTRING(a FROM 1 FOR 2), EXTRACT(YEAR FROM a), LABELS(a), CAST(a AS TIMESTAMP WITH TIME ZONE)\nFROM MATCH (n)"},
+		{"case", "SELECT CASE a WHEN 1 THEN 'x' ELSE 'y' END, CASE WHEN a THEN b END FROM MATCH (n)", "SELECT CASE a WHEN 1 THEN 'x' ELSE 'y' END, CASE WHEN a THEN b END\nFROM MATCH (n)"},
+		{"literals", "SELECT 'it''s', 1.5, .5, false, DATE '2000-01-01', TIME '12:00:00', TIMESTAMP '2000-01-01 12:00:00', INTERVAL '1' day FROM MATCH (n)", "SELECT 'it''s', 1.5, .5, FALSE, DATE '2000-01-01', TIME '12:00:00', TIMESTAMP '2000-01-01 12:00:00', INTERVAL '1' DAY\nFROM MATCH (n)"},
+		{"quoting", `SELECT "select", "a b", "a""b", "1a", abc_1 FROM MATCH (n)`, `SELECT "select", "a b", "a""b", "1a", abc_1` + "\nFROM MATCH (n)"},
+
+		{"insert", "INSERT INTO g VERTEX v LABELS (L) PROPERTIES (v.a = 1), EDGE e BETWEEN v AND w", "INSERT INTO g VERTEX v LABELS (L) PROPERTIES (v.a = 1), EDGE e BETWEEN v AND w"},
+		{"modify", "UPDATE v SET (v.a = 1), w SET (w.b = 2) DELETE x, y FROM MATCH (v) -> (w), MATCH (x) -> (y) WHERE v.a > 0", "UPDATE v SET (v.a = 1), w SET (w.b = 2)\nDELETE x, y\nFROM MATCH (v) -> (w),\n  MATCH (x) -> (y)\nWHERE v.a > 0"},
+
+		{"create", "CREATE PROPERTY GRAPH s.g VERTEX TABLES (t AS u KEY (k) LABEL l PROPERTIES (c AS p, CAST(d AS INT) AS q), v NO PROPERTIES) EDGE TABLES (e SOURCE KEY (s) REFERENCES t (k) DESTINATION v PROPERTIES ARE ALL COLUMNS EXCEPT (x))", "CREATE PROPERTY GRAPH s.g\n  VERTEX TABLES (\n    t AS u KEY (k) LABEL l PROPERTIES (c AS p, CAST(d AS INT) AS q),\n    v NO PROPERTIES\n  )\n  EDGE TABLES (\n    e SOURCE KEY (s) REFERENCES t (k) DESTINATION v PROPERTIES ARE ALL COLUMNS EXCEPT (x)\n  )"},
+		{"drop", "DROP PROPERTY GRAPH g", "DROP PROPERTY GRAPH g"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmt := parse(t, tst.Input)
+
+			var buf bytes.Buffer
+			if err := Fprint(&buf, stmt); err != nil {
+				t.Fatalf("Fprint failed: %v", err)
+			}
+
+			if diff := cmp.Diff(tst.Want, buf.String()); diff != "" {
+				t.Errorf("Fprint: +got, -want:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(stmt, parse(t, buf.String()), cmpopts.IgnoreTypes(ast.Pos(0))); diff != "" {
+				t.Errorf("Parse(Fprint): +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFprintComments(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"none", "SELECT n FROM MATCH (n)", "SELECT n\nFROM MATCH (n)"},
+		{"stmt", "/* a */ /* b */\nSELECT n FROM MATCH (n)", "/* a */\n/* b */\nSELECT n\nFROM MATCH (n)"},
+		{"clauses", "SELECT n /* f */ FROM MATCH (n) /* w */ WHERE n.a > 1 /* g */ GROUP BY n.b /* o */ ORDER BY n.b /* l */ LIMIT 1", "SELECT n\n/* f */\nFROM MATCH (n)\n/* w */\nWHERE n.a > 1\n/* g */\nGROUP BY n.b\n/* o */\nORDER BY n.b\n/* l */\nLIMIT 1"},
+		{"inline", "SELECT /* x */ n, /* y */ m FROM MATCH (n) -> /* z */ (m) WHERE n.a = /* one */ 1", "SELECT /* x */ n, /* y */ m\nFROM MATCH (n) -> /* z */ (m)\nWHERE n.a = /* one */ 1"},
+		{"alias", "SELECT n /* as */ AS x FROM MATCH (n)", "SELECT n AS /* as */ x\nFROM MATCH (n)"},
+		{"parenthesized", "SELECT /* p */ (1 + 2) * 3 FROM MATCH (n)", "SELECT /* p */ (1 + 2) * 3\nFROM MATCH (n)"},
+		{"subquery", "SELECT n FROM MATCH (n) WHERE EXISTS ( /* s */ SELECT m FROM MATCH (n) -> (m))", "SELECT n\nFROM MATCH (n)\nWHERE EXISTS (\n  /* s */\n  SELECT m\n  FROM MATCH (n) -> (m)\n)"},
+		{"create", "CREATE PROPERTY GRAPH g VERTEX TABLES (/* v */ t, /* w */ u)", "CREATE PROPERTY GRAPH g\n  VERTEX TABLES (\n    /* v */\n    t,\n    /* w */\n    u\n  )"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := parser.Parse(strings.NewReader(tst.Input + ";"))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := Fprint(&buf, &CommentedNode{Node: stmts.Stmts[0], Comments: stmts.Comments}); err != nil {
+				t.Fatalf("Fprint failed: %v", err)
+			}
+
+			if diff := cmp.Diff(tst.Want, buf.String()); diff != "" {
+				t.Errorf("Fprint: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFprintSpec(t *testing.T) {
+	des, err := os.ReadDir("../parser/testdata/spec")
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	for _, de := range des {
+		if !strings.HasSuffix(de.Name(), ".pgql") {
+			continue
+		}
+
+		de := de
+		t.Run(de.Name(), func(t *testing.T) {
+			t.Parallel()
+
+			bs, err := os.ReadFile(filepath.Join("../parser/testdata/spec", de.Name()))
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			stmt := parse(t, string(bs))
+
+			var buf bytes.Buffer
+			if err := Fprint(&buf, stmt); err != nil {
+				t.Fatalf("Fprint failed: %v", err)
+			}
+
+			if diff := cmp.Diff(stmt, parse(t, buf.String()), cmpopts.IgnoreTypes(ast.Pos(0))); diff != "" {
+				t.Errorf("Parse(Fprint): +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func parse(t *testing.T, s string) ast.Stmt {
+	t.Helper()
+
+	stmts, err := parser.Parse(strings.NewReader(s + ";"))
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return stmts.Stmts[0]
+}
@@ -0,0 +1,393 @@
+package sema
+
+import (
+	"fmt"
+
+	"github.com/itergia/pgql-go/ast"
+)
+
+// Check resolves the variables of stmt. The file maps positions in
+// errors to lines and columns. If it is nil, the statement is assumed
+// to be on a single line. On errors, Info holds what could be
+// resolved, and the error is an ErrorList.
+func Check(file *ast.File, stmt ast.Stmt) (*Info, error) {
+	if file == nil {
+		file = ast.NewFile("")
+	}
+	c := checker{
+		file: file,
+		info: &Info{
+			Defs:   map[*ast.Ident]*Object{},
+			Uses:   map[*ast.Ident]*Object{},
+			Macros: map[*ast.Ident]*ast.PathMacroClause{},
+		},
+	}
+
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		c.selectStmt(nil, s)
+	case *ast.ModifyStmt:
+		c.modifyStmt(nil, s)
+	}
+
+	if len(c.errs) > 0 {
+		c.errs.Sort()
+		return c.info, c.errs
+	}
+	return c.info, nil
+}
+
+type checker struct {
+	file *ast.File
+	info *Info
+	errs ErrorList
+}
+
+// A scope holds the variables visible in a part of a query.
+type scope struct {
+	parent *scope
+	objs   map[string]*Object
+	macros map[string]*ast.PathMacroClause
+
+	// match is true for the scope of the MATCH clauses of a query.
+	// Declaring a vertex or edge already declared in an enclosing
+	// query correlates the queries.
+	match bool
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, objs: map[string]*Object{}}
+}
+
+// lookup returns the innermost object named name, or nil.
+func (sc *scope) lookup(name string) *Object {
+	for ; sc != nil; sc = sc.parent {
+		if obj := sc.objs[name]; obj != nil {
+			return obj
+		}
+	}
+	return nil
+}
+
+// lookupMacro returns the innermost path macro named name, or nil.
+func (sc *scope) lookupMacro(name string) *ast.PathMacroClause {
+	for ; sc != nil; sc = sc.parent {
+		if m := sc.macros[name]; m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+func (c *checker) errorf(n ast.Node, format string, args ...any) {
+	c.errs = append(c.errs, &Error{Pos: c.file.Position(n.Pos()), Msg: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) selectStmt(parent *scope, s *ast.SelectStmt) {
+	sc := c.matchScope(parent, s.PathMacros, s.From)
+	c.expr(sc, s.Where)
+	gsc := c.groupBy(sc, s.GroupBy)
+
+	// SELECT aliases are only visible in ORDER BY.
+	osc := newScope(gsc)
+	for _, sel := range s.Sels {
+		if sel.AllOf != nil {
+			c.use(gsc, sel.AllOf)
+			continue
+		}
+		c.expr(gsc, sel.Named.Expr)
+		c.alias(osc, sel.Named)
+	}
+
+	c.expr(gsc, s.Having)
+	for _, t := range s.OrderBy {
+		c.expr(osc, t.Expr)
+	}
+	c.expr(sc, s.Limit)
+	c.expr(sc, s.Offset)
+}
+
+func (c *checker) modifyStmt(parent *scope, s *ast.ModifyStmt) {
+	sc := c.matchScope(parent, s.PathMacros, s.From)
+
+	// Inserted variables can be used by any modification.
+	for _, mod := range s.Mods {
+		if ins, ok := mod.(*ast.InsertClause); ok {
+			for _, v := range ins.Vs {
+				c.insert(sc, v.Var, VertexKind, v)
+			}
+			for _, e := range ins.Es {
+				c.insert(sc, e.Var, EdgeKind, e)
+			}
+		}
+	}
+	for _, mod := range s.Mods {
+		switch mod := mod.(type) {
+		case *ast.InsertClause:
+			for _, v := range mod.Vs {
+				c.props(sc, v.Props)
+			}
+			for _, e := range mod.Es {
+				c.useKind(sc, e.Source, VertexKind)
+				c.useKind(sc, e.Dest, VertexKind)
+				c.props(sc, e.Props)
+			}
+
+		case *ast.UpdateClause:
+			for _, u := range mod.Updates {
+				c.useKind(sc, u.Var, VertexKind, EdgeKind)
+				c.props(sc, u.Props)
+			}
+
+		case *ast.DeleteClause:
+			for _, id := range mod.Vars {
+				c.useKind(sc, id, VertexKind, EdgeKind)
+			}
+		}
+	}
+
+	c.expr(sc, s.Where)
+	gsc := c.groupBy(sc, s.GroupBy)
+	c.expr(gsc, s.Having)
+	for _, t := range s.OrderBy {
+		c.expr(gsc, t.Expr)
+	}
+	c.expr(sc, s.Limit)
+	c.expr(sc, s.Offset)
+}
+
+// matchScope returns the scope of a query, with its path macros and
+// the variables declared by its MATCH clauses.
+func (c *checker) matchScope(parent *scope, macros []*ast.PathMacroClause, from []*ast.MatchClause) *scope {
+	sc := newScope(parent)
+	sc.match = true
+
+	for _, m := range macros {
+		// The variables of a macro are local to it.
+		msc := newScope(nil)
+		msc.macros = sc.macros
+		c.path(msc, m.Pattern, false)
+		c.pathExprs(msc, m.Pattern)
+		c.expr(msc, m.Where)
+
+		if sc.macros == nil {
+			sc.macros = map[string]*ast.PathMacroClause{}
+		}
+		if sc.macros[m.Name.Name] != nil {
+			c.errorf(m.Name, "path macro %s is already defined", m.Name.Name)
+		}
+		sc.macros[m.Name.Name] = m
+	}
+
+	for _, mc := range from {
+		for _, p := range mc.Patterns {
+			c.path(sc, p, false)
+		}
+		if mc.Rows != nil {
+			kinds := []Kind{VertexKind}
+			if mc.Rows.Kind == ast.OneRowPerStep {
+				kinds = []Kind{VertexKind, EdgeKind, VertexKind}
+			}
+			for i, id := range mc.Rows.Vars {
+				c.declare(sc, id, kinds[i%len(kinds)], mc.Rows, false)
+			}
+		}
+	}
+	// Conditions inside patterns can use any variable.
+	for _, mc := range from {
+		for _, p := range mc.Patterns {
+			c.pathExprs(sc, p)
+		}
+	}
+
+	return sc
+}
+
+// path declares the variables of a path pattern.
+func (c *checker) path(sc *scope, p *ast.PathPattern, group bool) {
+	for _, v := range p.Vs {
+		c.declare(sc, v.Name, VertexKind, v, group)
+	}
+	for _, pp := range p.Es {
+		g := group || pp.Quantity != nil
+		for _, v := range pp.Vs {
+			if v != nil {
+				c.declare(sc, v.Name, VertexKind, v, g)
+			}
+		}
+		for _, e := range pp.Es {
+			if e.Reachability {
+				for _, l := range e.LabelAlts {
+					if m := sc.lookupMacro(l.Name); m != nil {
+						c.info.Macros[l] = m
+					}
+				}
+			}
+			c.declare(sc, e.Name, EdgeKind, e, g)
+		}
+	}
+}
+
+// pathExprs resolves the WHERE and COST expressions inside a path
+// pattern.
+func (c *checker) pathExprs(sc *scope, p *ast.PathPattern) {
+	for _, pp := range p.Es {
+		c.expr(sc, pp.Where)
+		c.expr(sc, pp.Cost)
+	}
+}
+
+// declare declares a vertex or edge variable. Repeating a variable
+// refers to the same element, as does repeating a variable of an
+// enclosing query in a subquery.
+func (c *checker) declare(sc *scope, id *ast.Ident, kind Kind, decl ast.Node, group bool) {
+	if id == nil {
+		return
+	}
+
+	obj := sc.objs[id.Name]
+	if obj == nil && sc.match {
+		if outer := sc.parent.lookup(id.Name); outer != nil && outer.Kind != ExprKind {
+			obj = outer
+		}
+	}
+	if obj == nil {
+		obj = &Object{Name: id.Name, Kind: kind, Decl: decl, Group: group}
+		sc.objs[id.Name] = obj
+		c.info.Defs[id] = obj
+		return
+	}
+
+	switch {
+	case obj.Kind != kind:
+		c.errorf(id, "variable %s is bound as both %s and %s", id.Name, article(obj.Kind), article(kind))
+	case obj.Group != group:
+		c.errorf(id, "variable %s is bound both inside and outside a quantified path pattern", id.Name)
+	}
+	c.info.Uses[id] = obj
+}
+
+// insert declares a variable of an INSERT clause, which must be new.
+func (c *checker) insert(sc *scope, id *ast.Ident, kind Kind, decl ast.Node) {
+	if id == nil {
+		return
+	}
+	if sc.lookup(id.Name) != nil {
+		c.errorf(id, "variable %s is already defined", id.Name)
+		return
+	}
+
+	obj := &Object{Name: id.Name, Kind: kind, Decl: decl}
+	sc.objs[id.Name] = obj
+	c.info.Defs[id] = obj
+}
+
+// alias declares the name of an expression, if any.
+func (c *checker) alias(sc *scope, ne *ast.NamedExpr) {
+	if ne.Name == nil {
+		return
+	}
+	if sc.objs[ne.Name.Name] != nil {
+		c.errorf(ne.Name, "alias %s is already defined", ne.Name.Name)
+		return
+	}
+
+	obj := &Object{Name: ne.Name.Name, Kind: ExprKind, Decl: ne}
+	sc.objs[ne.Name.Name] = obj
+	c.info.Defs[ne.Name] = obj
+}
+
+// groupBy resolves the GROUP BY expressions, and returns the scope
+// with their aliases.
+func (c *checker) groupBy(sc *scope, groupBy []*ast.NamedExpr) *scope {
+	if len(groupBy) == 0 {
+		return sc
+	}
+
+	gsc := newScope(sc)
+	for _, ne := range groupBy {
+		c.expr(sc, ne.Expr)
+		c.alias(gsc, ne)
+	}
+	return gsc
+}
+
+// props resolves property assignments, like "v.name = 'x'".
+func (c *checker) props(sc *scope, props []*ast.PropAssignment) {
+	for _, a := range props {
+		c.expr(sc, a.Prop)
+		c.expr(sc, a.Value)
+	}
+}
+
+// use resolves a variable reference.
+func (c *checker) use(sc *scope, id *ast.Ident) *Object {
+	obj := sc.lookup(id.Name)
+	if obj == nil {
+		c.errorf(id, "undefined variable %s", id.Name)
+		return nil
+	}
+	c.info.Uses[id] = obj
+	return obj
+}
+
+// useKind resolves a variable reference, which must be one of the
+// kinds.
+func (c *checker) useKind(sc *scope, id *ast.Ident, kinds ...Kind) {
+	obj := c.use(sc, id)
+	if obj == nil {
+		return
+	}
+	for _, k := range kinds {
+		if obj.Kind == k {
+			return
+		}
+	}
+
+	want := article(kinds[0])
+	for _, k := range kinds[1:] {
+		want += " or " + k.String()
+	}
+	c.errorf(id, "variable %s is not %s", id.Name, want)
+}
+
+// expr resolves the variables of an expression, which may be nil.
+func (c *checker) expr(sc *scope, e ast.Expr) {
+	if e == nil {
+		return
+	}
+
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			c.use(sc, n)
+
+		case *ast.QIdent:
+			// A property access.
+			c.use(sc, n.Names[0])
+			return false
+
+		case *ast.CallExpr:
+			// The function name is not a variable.
+			for _, arg := range n.Args {
+				c.expr(sc, arg)
+			}
+			return false
+
+		case *ast.SubqueryExpr:
+			c.selectStmt(sc, n.Query)
+			return false
+		}
+		return true
+	})
+}
+
+// article returns the kind prefixed by "a" or "an".
+func article(k Kind) string {
+	switch k {
+	case EdgeKind, ExprKind:
+		return "an " + k.String()
+	default:
+		return "a " + k.String()
+	}
+}
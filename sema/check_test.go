@@ -0,0 +1,188 @@
+package sema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/parser"
+)
+
+func TestCheck(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{
+			"repeated",
+			"SELECT a.name FROM MATCH (a) -[e]-> (b), MATCH (b) -> (a)",
+			[]string{
+				"a@56 vertex@27",
+				"a@8 vertex@27",
+				"b@49 vertex@38",
+			},
+		},
+		{
+			"where",
+			"SELECT b FROM MATCH (a) -> (b) WHERE a.x = b.x",
+			[]string{
+				"a@38 vertex@22",
+				"b@44 vertex@29",
+				"b@8 vertex@29",
+			},
+		},
+		{
+			"correlated",
+			"SELECT a FROM MATCH (a) WHERE EXISTS (SELECT b FROM MATCH (a) -> (b) WHERE b.x > a.x)",
+			[]string{
+				"a@60 vertex@22",
+				"a@8 vertex@22",
+				"a@82 vertex@22",
+				"b@46 vertex@67",
+				"b@76 vertex@67",
+			},
+		},
+		{
+			"groupByAlias",
+			"SELECT n, COUNT(*) AS c FROM MATCH (a) GROUP BY a.name AS n ORDER BY c, n",
+			[]string{
+				"a@49 vertex@37",
+				"c@70 expression@23",
+				"n@73 expression@59",
+				"n@8 expression@59",
+			},
+		},
+		{
+			"macro",
+			"PATH p AS (x) -> (y) WHERE x.a = y.a SELECT b FROM MATCH (a) -/:p*/-> (b)",
+			[]string{
+				"b@45 vertex@72",
+				"p@65 macro@6",
+				"x@28 vertex@12",
+				"y@34 vertex@19",
+			},
+		},
+		{
+			"modify",
+			"INSERT VERTEX v, EDGE e BETWEEN v AND a PROPERTIES (e.w = a.w) UPDATE a SET (a.x = 1) FROM MATCH (a)",
+			[]string{
+				"a@39 vertex@99",
+				"a@59 vertex@99",
+				"a@71 vertex@99",
+				"a@78 vertex@99",
+				"e@53 edge@23",
+				"v@33 vertex@15",
+			},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmt, err := parser.ParseStatement(tst.Input)
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+
+			info, err := Check(nil, stmt)
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+
+			var got []string
+			for id, obj := range info.Uses {
+				got = append(got, fmt.Sprintf("%s@%d %v@%d", id.Name, id.Pos(), obj.Kind, declPos(info, obj)))
+			}
+			for id, m := range info.Macros {
+				got = append(got, fmt.Sprintf("%s@%d macro@%d", id.Name, id.Pos(), m.Name.Pos()))
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Check: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{
+			"undefined",
+			"SELECT b FROM MATCH (a)",
+			"at 1:8: undefined variable b",
+		},
+		{
+			"kindConflict",
+			"SELECT a FROM MATCH (a) -[a]-> (b)",
+			"at 1:27: variable a is bound as both a vertex and an edge",
+		},
+		{
+			"group",
+			"SELECT a FROM MATCH ANY SHORTEST (a) (-[e]-> (b))* (c), MATCH (b)",
+			"at 1:64: variable b is bound both inside and outside a quantified path pattern",
+		},
+		{
+			"selectAliasInWhere",
+			"SELECT a.x AS y FROM MATCH (a) WHERE y = 1",
+			"at 1:38: undefined variable y",
+		},
+		{
+			"updateUnbound",
+			"UPDATE b SET (b.x = 1) FROM MATCH (a)",
+			"at 1:8: undefined variable b (and 1 more errors)",
+		},
+		{
+			"edgeEndpoint",
+			"INSERT EDGE f BETWEEN e AND a FROM MATCH (a) -[e]-> (b)",
+			"at 1:23: variable e is not a vertex",
+		},
+		{
+			"insertExisting",
+			"INSERT VERTEX a FROM MATCH (a)",
+			"at 1:15: variable a is already defined",
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stmt, err := parser.ParseStatement(tst.Input)
+			if err != nil {
+				t.Fatalf("ParseStatement failed: %v", err)
+			}
+
+			_, err = Check(nil, stmt)
+			if err == nil {
+				t.Fatalf("Check succeeded, want %q", tst.Want)
+			}
+			if got := err.Error(); got != tst.Want {
+				t.Errorf("Check: got %q, want %q", got, tst.Want)
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Errorf("errors.As(%v) failed", err)
+			}
+		})
+	}
+}
+
+// declPos returns the position of the identifier declaring obj.
+func declPos(info *Info, obj *Object) ast.Pos {
+	for id, o := range info.Defs {
+		if o == obj {
+			return id.Pos()
+		}
+	}
+	return ast.NoPos
+}
@@ -0,0 +1,55 @@
+package sema
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/itergia/pgql-go/token"
+)
+
+// An Error is a semantic error at a position in the input.
+type Error struct {
+	// Pos is the start of the offending node.
+	Pos token.Position
+
+	// Msg is the message, without the position.
+	Msg string
+}
+
+// Error returns the message prefixed with the position.
+func (e *Error) Error() string {
+	return fmt.Sprintf("at %s: %s", e.Pos, e.Msg)
+}
+
+// ErrorList is a list of Errors, sorted by position. It is the error
+// returned by Check.
+type ErrorList []*Error
+
+// Error returns the first error, and the number of others.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// As sets target to the first error if it is an **Error, so that
+// errors.As can find an *Error. Go 1.20 would use Unwrap() []error,
+// but the module supports Go 1.18.
+func (l ErrorList) As(target any) bool {
+	p, ok := target.(**Error)
+	if !ok || len(l) == 0 {
+		return false
+	}
+	*p = l[0]
+	return true
+}
+
+// Sort sorts the list by position, keeping the order of errors at
+// the same position.
+func (l ErrorList) Sort() {
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Pos.Offset < l[j].Pos.Offset })
+}
@@ -0,0 +1,80 @@
+// Package sema resolves the variables of PGQL statements.
+//
+// Check binds every variable reference to the vertex pattern, edge
+// pattern or expression declaring it, and reports references to
+// undefined variables, and variables declared with conflicting kinds.
+//
+// Variables are compared by name, exactly, since the AST does not
+// record whether an identifier was quoted.
+package sema
+
+import (
+	"fmt"
+
+	"github.com/itergia/pgql-go/ast"
+)
+
+// Kind is the kind of an Object.
+type Kind int
+
+const (
+	UnknownKind Kind = iota
+	VertexKind
+	EdgeKind
+	ExprKind // An alias of a SELECT or GROUP BY expression.
+)
+
+var kindStrings = [...]string{
+	VertexKind: "vertex",
+	EdgeKind:   "edge",
+	ExprKind:   "expression",
+}
+
+// String returns the kind in lower-case, e.g. "vertex".
+func (k Kind) String() string {
+	if k > UnknownKind && int(k) < len(kindStrings) {
+		return kindStrings[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// An Object is a variable of a statement.
+type Object struct {
+	Name string
+	Kind Kind
+
+	// Decl is the node declaring the variable. It is one of
+	// *ast.VertexPattern, *ast.EdgePattern, *ast.NamedExpr,
+	// *ast.MatchRows, *ast.VertexInsertion or *ast.EdgeInsertion.
+	Decl ast.Node
+
+	// Group is true for variables declared inside a quantified path
+	// pattern, which are bound to lists of elements.
+	Group bool
+}
+
+// Info holds the results of Check.
+type Info struct {
+	// Defs maps the identifiers declaring variables to their objects.
+	// A vertex or edge variable repeated in a pattern, or used in a
+	// correlated subquery, is only declared once, and the other
+	// identifiers are in Uses.
+	Defs map[*ast.Ident]*Object
+
+	// Uses maps the identifiers referencing variables to their
+	// objects. For property accesses, like "a.name", only the variable
+	// is in Uses.
+	Uses map[*ast.Ident]*Object
+
+	// Macros maps the labels of reachability patterns that name a
+	// path macro to the macro.
+	Macros map[*ast.Ident]*ast.PathMacroClause
+}
+
+// ObjectOf returns the object declared or referenced by id, or nil.
+func (info *Info) ObjectOf(id *ast.Ident) *Object {
+	if obj := info.Defs[id]; obj != nil {
+		return obj
+	}
+	return info.Uses[id]
+}
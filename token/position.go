@@ -0,0 +1,30 @@
+package token
+
+import "fmt"
+
+// Position indicates a position in the input stream.
+type Position struct {
+	// Filename is the name of the source, if any.
+	Filename string
+
+	// Offset is the zero-based UTF-8 codepoint offset from the start
+	// of the stream.
+	Offset int
+
+	// Line is the zero-based line number, as separated by NL.
+	Line int
+
+	// Column is the zero-based column number on the current line.
+	Column int
+}
+
+// String returns the position as "filename:line:column", with
+// one-based line and column numbers. The filename is omitted if
+// empty.
+func (p Position) String() string {
+	s := fmt.Sprintf("%d:%d", p.Line+1, p.Column+1)
+	if p.Filename != "" {
+		s = p.Filename + ":" + s
+	}
+	return s
+}
@@ -3,8 +3,385 @@ package token
 import (
 	"fmt"
 	"strings"
+	"unicode"
 )
 
+// Token is the kind of a lexical token of PGQL.
+type Token int
+
+const (
+	// Special tokens.
+	ILLEGAL Token = iota
+	EOF
+	COMMENT
+
+	literalBeg
+	IDENT            // abc
+	QUOTED_IDENT     // "abc"
+	UNSIGNED_INTEGER // 42
+	UNSIGNED_DECIMAL // 42.5
+	STRING_LITERAL   // 'abc'
+	literalEnd
+
+	operatorBeg
+	LPAREN    // (
+	RPAREN    // )
+	LBRACE    // {
+	RBRACE    // }
+	RBRACK    // ]
+	COLON     // :
+	QUESTION  // ?
+	SEMICOLON // ;
+	COMMA     // ,
+	PERIOD    // .
+
+	EQL // =
+	NEQ // <>
+	LSS // <
+	GTR // >
+	LEQ // <=
+	GEQ // >=
+
+	ADD    // +
+	SUB    // -
+	MUL    // *
+	QUO    // /
+	REM    // %
+	CONCAT // ||
+	PIPE   // |
+
+	LARROW        // <-
+	RARROW        // ->
+	LARROWBRACKET // <-[
+	RBRACKETARROW // ]->
+	LARROWSLASH   // <-/
+	RSLASHARROW   // /->
+	LDASHBRACKET  // -[
+	RBRACKETDASH  // ]-
+	LDASHSLASH    // -/
+	RSLASHDASH    // /-
+	operatorEnd
+
+	keywordBeg
+	ALL
+	AND
+	ANY
+	ARE
+	ARRAY_AGG
+	AS
+	ASC
+	AVG
+	BETWEEN
+	BOOLEAN
+	BY
+	CASE
+	CAST
+	CHEAPEST
+	COLUMNS
+	COST
+	COUNT
+	CREATE
+	DATE
+	DAY
+	DELETE
+	DESC
+	DESTINATION
+	DISTINCT
+	DOUBLE
+	DROP
+	EDGE
+	ELSE
+	END
+	EXCEPT
+	EXISTS
+	EXTRACT
+	FALSE
+	FLOAT
+	FOR
+	FROM
+	GRAPH
+	GROUP
+	HAVING
+	HOUR
+	IN
+	INSERT
+	INT
+	INTEGER
+	INTERVAL
+	INTO
+	IS
+	KEY
+	LABEL
+	LABELS
+	LIMIT
+	LISTAGG
+	LONG
+	MATCH
+	MAX
+	MIN
+	MINUTE
+	MONTH
+	NO
+	NOT
+	NULL
+	OFFSET
+	ON
+	ONE
+	OR
+	ORDER
+	PATH
+	PER
+	PREFIX
+	PROPERTIES
+	PROPERTY
+	REFERENCES
+	ROW
+	SECOND
+	SELECT
+	SET
+	SHORTEST
+	SOURCE
+	STEP
+	STRING
+	SUBSTRING
+	SUM
+	TABLES
+	THEN
+	TIME
+	TIMESTAMP
+	TIMEZONE_HOUR
+	TIMEZONE_MINUTE
+	TOP
+	TRUE
+	UPDATE
+	VERTEX
+	WHEN
+	WHERE
+	WITH
+	YEAR
+	ZONE
+	keywordEnd
+)
+
+var tokens = [...]string{
+	ILLEGAL: "ILLEGAL",
+	EOF:     "EOF",
+	COMMENT: "COMMENT",
+
+	IDENT:            "IDENT",
+	QUOTED_IDENT:     "QUOTED_IDENT",
+	UNSIGNED_INTEGER: "UNSIGNED_INTEGER",
+	UNSIGNED_DECIMAL: "UNSIGNED_DECIMAL",
+	STRING_LITERAL:   "STRING_LITERAL",
+
+	LPAREN:    "(",
+	RPAREN:    ")",
+	LBRACE:    "{",
+	RBRACE:    "}",
+	RBRACK:    "]",
+	COLON:     ":",
+	QUESTION:  "?",
+	SEMICOLON: ";",
+	COMMA:     ",",
+	PERIOD:    ".",
+
+	EQL: "=",
+	NEQ: "<>",
+	LSS: "<",
+	GTR: ">",
+	LEQ: "<=",
+	GEQ: ">=",
+
+	ADD:    "+",
+	SUB:    "-",
+	MUL:    "*",
+	QUO:    "/",
+	REM:    "%",
+	CONCAT: "||",
+	PIPE:   "|",
+
+	LARROW:        "<-",
+	RARROW:        "->",
+	LARROWBRACKET: "<-[",
+	RBRACKETARROW: "]->",
+	LARROWSLASH:   "<-/",
+	RSLASHARROW:   "/->",
+	LDASHBRACKET:  "-[",
+	RBRACKETDASH:  "]-",
+	LDASHSLASH:    "-/",
+	RSLASHDASH:    "/-",
+
+	ALL:             "ALL",
+	AND:             "AND",
+	ANY:             "ANY",
+	ARE:             "ARE",
+	ARRAY_AGG:       "ARRAY_AGG",
+	AS:              "AS",
+	ASC:             "ASC",
+	AVG:             "AVG",
+	BETWEEN:         "BETWEEN",
+	BOOLEAN:         "BOOLEAN",
+	BY:              "BY",
+	CASE:            "CASE",
+	CAST:            "CAST",
+	CHEAPEST:        "CHEAPEST",
+	COLUMNS:         "COLUMNS",
+	COST:            "COST",
+	COUNT:           "COUNT",
+	CREATE:          "CREATE",
+	DATE:            "DATE",
+	DAY:             "DAY",
+	DELETE:          "DELETE",
+	DESC:            "DESC",
+	DESTINATION:     "DESTINATION",
+	DISTINCT:        "DISTINCT",
+	DOUBLE:          "DOUBLE",
+	DROP:            "DROP",
+	EDGE:            "EDGE",
+	ELSE:            "ELSE",
+	END:             "END",
+	EXCEPT:          "EXCEPT",
+	EXISTS:          "EXISTS",
+	EXTRACT:         "EXTRACT",
+	FALSE:           "FALSE",
+	FLOAT:           "FLOAT",
+	FOR:             "FOR",
+	FROM:            "FROM",
+	GRAPH:           "GRAPH",
+	GROUP:           "GROUP",
+	HAVING:          "HAVING",
+	HOUR:            "HOUR",
+	IN:              "IN",
+	INSERT:          "INSERT",
+	INT:             "INT",
+	INTEGER:         "INTEGER",
+	INTERVAL:        "INTERVAL",
+	INTO:            "INTO",
+	IS:              "IS",
+	KEY:             "KEY",
+	LABEL:           "LABEL",
+	LABELS:          "LABELS",
+	LIMIT:           "LIMIT",
+	LISTAGG:         "LISTAGG",
+	LONG:            "LONG",
+	MATCH:           "MATCH",
+	MAX:             "MAX",
+	MIN:             "MIN",
+	MINUTE:          "MINUTE",
+	MONTH:           "MONTH",
+	NO:              "NO",
+	NOT:             "NOT",
+	NULL:            "NULL",
+	OFFSET:          "OFFSET",
+	ON:              "ON",
+	ONE:             "ONE",
+	OR:              "OR",
+	ORDER:           "ORDER",
+	PATH:            "PATH",
+	PER:             "PER",
+	PREFIX:          "PREFIX",
+	PROPERTIES:      "PROPERTIES",
+	PROPERTY:        "PROPERTY",
+	REFERENCES:      "REFERENCES",
+	ROW:             "ROW",
+	SECOND:          "SECOND",
+	SELECT:          "SELECT",
+	SET:             "SET",
+	SHORTEST:        "SHORTEST",
+	SOURCE:          "SOURCE",
+	STEP:            "STEP",
+	STRING:          "STRING",
+	SUBSTRING:       "SUBSTRING",
+	SUM:             "SUM",
+	TABLES:          "TABLES",
+	THEN:            "THEN",
+	TIME:            "TIME",
+	TIMESTAMP:       "TIMESTAMP",
+	TIMEZONE_HOUR:   "TIMEZONE_HOUR",
+	TIMEZONE_MINUTE: "TIMEZONE_MINUTE",
+	TOP:             "TOP",
+	TRUE:            "TRUE",
+	UPDATE:          "UPDATE",
+	VERTEX:          "VERTEX",
+	WHEN:            "WHEN",
+	WHERE:           "WHERE",
+	WITH:            "WITH",
+	YEAR:            "YEAR",
+	ZONE:            "ZONE",
+}
+
+// String returns the source text of operators and keywords, like
+// "->" or "SELECT", and the name of other tokens, like "IDENT".
+func (tok Token) String() string {
+	if tok >= 0 && int(tok) < len(tokens) && tokens[tok] != "" {
+		return tokens[tok]
+	}
+	return fmt.Sprintf("Token(%d)", int(tok))
+}
+
+// IsLiteral returns true for identifiers and literals.
+func (tok Token) IsLiteral() bool { return literalBeg < tok && tok < literalEnd }
+
+// IsOperator returns true for operators and delimiters.
+func (tok Token) IsOperator() bool { return operatorBeg < tok && tok < operatorEnd }
+
+// IsKeyword returns true for keywords.
+func (tok Token) IsKeyword() bool { return keywordBeg < tok && tok < keywordEnd }
+
+var keywords map[string]Token
+
+func init() {
+	keywords = make(map[string]Token, keywordEnd-keywordBeg-1)
+	for tok := keywordBeg + 1; tok < keywordEnd; tok++ {
+		keywords[tokens[tok]] = tok
+	}
+}
+
+// Lookup returns the keyword token of ident, or IDENT if it is not a
+// keyword. Keywords are case-insensitive.
+func Lookup(ident string) Token {
+	if tok, ok := keywords[strings.ToUpper(ident)]; ok {
+		return tok
+	}
+	return IDENT
+}
+
+// IsKeyword returns true if s is a keyword, which must be quoted to be
+// used as an identifier, except for the non-reserved FOR and SUBSTRING.
+// The check is case-insensitive.
+func IsKeyword(s string) bool {
+	_, ok := keywords[strings.ToUpper(s)]
+	return ok
+}
+
+// QuoteIdentifier returns s as an identifier token. It is quoted only
+// if it is not a valid unquoted identifier, like a keyword or a name
+// with spaces.
+func QuoteIdentifier(s string) string {
+	if !needsQuoting(s) {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// needsQuoting returns true if s cannot be scanned as an IDENT.
+func needsQuoting(s string) bool {
+	if s == "" || IsKeyword(s) {
+		return true
+	}
+
+	for i, r := range s {
+		if i == 0 && !unicode.IsLetter(r) {
+			return true
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UnquoteIdentifier turns a quoted identifier token into an
 // identifier. The function panics if the input is not surrounded by
 // double-quotes.
@@ -15,3 +392,24 @@ func UnquoteIdentifier(s string) string {
 
 	return strings.Replace(s[1:len(s)-1], `""`, `"`, -1)
 }
+
+// QuoteString returns s as a string literal token, surrounded by
+// single-quotes.
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// UnquoteString turns a string literal token into its value. It
+// returns an error if the input is not surrounded by single-quotes, or
+// has a single-quote that is not doubled.
+func UnquoteString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return "", fmt.Errorf("PGQL string literal without surrounding quotes: %q", s)
+	}
+
+	s = s[1 : len(s)-1]
+	if strings.Contains(strings.ReplaceAll(s, "''", ""), "'") {
+		return "", fmt.Errorf("PGQL string literal with unescaped quote: %q", s)
+	}
+	return strings.ReplaceAll(s, "''", "'"), nil
+}
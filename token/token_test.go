@@ -2,6 +2,48 @@ package token
 
 import "testing"
 
+func TestLookup(t *testing.T) {
+	tsts := []struct {
+		Input string
+		Want  Token
+	}{
+		{"SELECT", SELECT},
+		{"select", SELECT},
+		{"Timezone_Hour", TIMEZONE_HOUR},
+		{"true", TRUE},
+		{"name", IDENT},
+		{"", IDENT},
+	}
+	for _, tst := range tsts {
+		t.Run(tst.Input, func(t *testing.T) {
+			if got := Lookup(tst.Input); got != tst.Want {
+				t.Errorf("Lookup: got %v, want %v", got, tst.Want)
+			}
+			if got, want := IsKeyword(tst.Input), tst.Want != IDENT; got != want {
+				t.Errorf("IsKeyword: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTokenString(t *testing.T) {
+	tsts := []struct {
+		Input Token
+		Want  string
+	}{
+		{EOF, "EOF"},
+		{IDENT, "IDENT"},
+		{RBRACKETARROW, "]->"},
+		{STRING, "STRING"},
+		{Token(-1), "Token(-1)"},
+	}
+	for _, tst := range tsts {
+		if got := tst.Input.String(); got != tst.Want {
+			t.Errorf("String(%d): got %q, want %q", int(tst.Input), got, tst.Want)
+		}
+	}
+}
+
 func TestUnquoteIdentifier(t *testing.T) {
 	tsts := []struct {
 		Input string
@@ -23,3 +65,68 @@ func TestUnquoteIdentifier(t *testing.T) {
 		})
 	}
 }
+
+func TestQuoteIdentifier(t *testing.T) {
+	tsts := []struct {
+		Input string
+		Want  string
+	}{
+		{`a`, `a`},
+		{`a_1`, `a_1`},
+		{`Été`, `Été`},
+		{``, `""`},
+		{`1a`, `"1a"`},
+		{`a b`, `"a b"`},
+		{`select`, `"select"`},
+		{`a"b`, `"a""b"`},
+	}
+	for _, tst := range tsts {
+		t.Run(tst.Input, func(t *testing.T) {
+			got := QuoteIdentifier(tst.Input)
+			if got != tst.Want {
+				t.Errorf("QuoteIdentifier: got %q, want %q", got, tst.Want)
+			}
+			if got[0] == '"' {
+				if s := UnquoteIdentifier(got); s != tst.Input {
+					t.Errorf("UnquoteIdentifier: got %q, want %q", s, tst.Input)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	tsts := []struct {
+		Input string
+		Want  string
+	}{
+		{``, `''`},
+		{`abc`, `'abc'`},
+		{`it's`, `'it''s'`},
+		{`''`, `''''''`},
+		{`a"\b`, `'a"\b'`},
+	}
+	for _, tst := range tsts {
+		t.Run(tst.Input, func(t *testing.T) {
+			got := QuoteString(tst.Input)
+			if got != tst.Want {
+				t.Errorf("QuoteString: got %q, want %q", got, tst.Want)
+			}
+			s, err := UnquoteString(got)
+			if err != nil {
+				t.Fatalf("UnquoteString failed: %v", err)
+			}
+			if s != tst.Input {
+				t.Errorf("UnquoteString: got %q, want %q", s, tst.Input)
+			}
+		})
+	}
+}
+
+func TestUnquoteStringError(t *testing.T) {
+	for _, s := range []string{``, `'`, `abc`, `'abc`, `"abc"`, `'a'b'`, `'a'''b'`} {
+		if got, err := UnquoteString(s); err == nil {
+			t.Errorf("UnquoteString(%q): got %q, want error", s, got)
+		}
+	}
+}
@@ -0,0 +1,695 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/itergia/pgql-go/ast"
+	"github.com/itergia/pgql-go/catalog"
+	"github.com/itergia/pgql-go/printer"
+	"github.com/itergia/pgql-go/sema"
+)
+
+// Info holds the results of Check.
+type Info struct {
+	// Types maps every checked expression to its type.
+	Types map[ast.Expr]Type
+
+	// Columns are the result columns of a SELECT statement.
+	Columns []*Column
+}
+
+// TypeOf returns the type of e, or nil if it was not checked.
+func (info *Info) TypeOf(e ast.Expr) Type {
+	return info.Types[e]
+}
+
+// A Column is a result column of a query.
+type Column struct {
+	Name string // See ColumnName.
+	Type Type
+}
+
+// Check infers the types of the expressions of stmt, using the
+// variables resolved by sema.Check, and the tables of variables
+// returned by catalog.Catalog.Tables, which may be nil. The file may
+// be nil, like in sema.Check. On errors, Info holds what could be
+// inferred, and the error is a sema.ErrorList.
+func Check(file *ast.File, stmt ast.Stmt, info *sema.Info, tables map[*sema.Object][]*catalog.Table) (*Info, error) {
+	if file == nil {
+		file = ast.NewFile("")
+	}
+	c := checker{
+		file:   file,
+		sema:   info,
+		tables: tables,
+		info:   &Info{Types: map[ast.Expr]Type{}},
+	}
+
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		c.info.Columns = c.selectStmt(s)
+	case *ast.ModifyStmt:
+		c.modifyStmt(s)
+	}
+
+	if len(c.errs) > 0 {
+		c.errs.Sort()
+		return c.info, c.errs
+	}
+	return c.info, nil
+}
+
+type checker struct {
+	file   *ast.File
+	sema   *sema.Info
+	tables map[*sema.Object][]*catalog.Table
+	info   *Info
+	errs   sema.ErrorList
+
+	// elems is true in the conditions of path patterns, where
+	// variables of quantified path patterns are single elements.
+	elems bool
+}
+
+func (c *checker) errorf(n ast.Node, format string, args ...any) {
+	c.errs = append(c.errs, &sema.Error{Pos: c.file.Position(n.Pos()), Msg: fmt.Sprintf(format, args...)})
+}
+
+// selectStmt checks a query, and returns its columns.
+func (c *checker) selectStmt(s *ast.SelectStmt) []*Column {
+	c.match(s.PathMacros, s.From)
+	c.cond(s.Where, "WHERE")
+	for _, ne := range s.GroupBy {
+		c.expr(ne.Expr)
+	}
+
+	var cols []*Column
+	for _, sel := range s.Sels {
+		if sel.AllOf != nil {
+			cols = append(cols, c.allOf(sel)...)
+			continue
+		}
+		cols = append(cols, &Column{Name: ColumnName(sel.Named), Type: c.expr(sel.Named.Expr)})
+	}
+
+	c.cond(s.Having, "HAVING")
+	c.tail(s.OrderBy, s.Limit, s.Offset)
+	return cols
+}
+
+func (c *checker) modifyStmt(s *ast.ModifyStmt) {
+	c.match(s.PathMacros, s.From)
+	for _, mod := range s.Mods {
+		switch mod := mod.(type) {
+		case *ast.InsertClause:
+			for _, v := range mod.Vs {
+				c.assign(v.Props)
+			}
+			for _, e := range mod.Es {
+				c.assign(e.Props)
+			}
+		case *ast.UpdateClause:
+			for _, u := range mod.Updates {
+				c.assign(u.Props)
+			}
+		}
+	}
+
+	c.cond(s.Where, "WHERE")
+	for _, ne := range s.GroupBy {
+		c.expr(ne.Expr)
+	}
+	c.cond(s.Having, "HAVING")
+	c.tail(s.OrderBy, s.Limit, s.Offset)
+}
+
+// match checks the conditions and costs inside path patterns.
+func (c *checker) match(macros []*ast.PathMacroClause, from []*ast.MatchClause) {
+	var ps []*ast.PathPattern
+	for _, m := range macros {
+		ps = append(ps, m.Pattern)
+		c.cond(m.Where, "WHERE")
+	}
+	for _, mc := range from {
+		ps = append(ps, mc.Patterns...)
+	}
+
+	// Inside quantified path patterns, variables are single elements.
+	c.elems = true
+	defer func() { c.elems = false }()
+	for _, p := range ps {
+		for _, pp := range p.Es {
+			c.cond(pp.Where, "WHERE")
+			if pp.Cost != nil {
+				if t := c.expr(pp.Cost); !IsUnknown(t) && !IsNumeric(t) {
+					c.errorf(pp.Cost, "COST has type %s, want a number", t)
+				}
+			}
+		}
+	}
+}
+
+// tail checks ORDER BY, LIMIT and OFFSET. The grammar only allows
+// integers and bind variables in LIMIT and OFFSET.
+func (c *checker) tail(orderBy []*ast.OrderTerm, limit, offset ast.Expr) {
+	for _, t := range orderBy {
+		c.expr(t.Expr)
+	}
+	for _, e := range []ast.Expr{limit, offset} {
+		if e != nil {
+			c.expr(e)
+		}
+	}
+}
+
+// cond checks an optional condition, which must be a BOOLEAN.
+func (c *checker) cond(e ast.Expr, what string) {
+	if e == nil {
+		return
+	}
+	if t := c.expr(e); !IsUnknown(t) && t != Boolean {
+		c.errorf(e, "%s condition has type %s, want BOOLEAN", what, t)
+	}
+}
+
+// assign checks that values can be assigned to their properties.
+func (c *checker) assign(props []*ast.PropAssignment) {
+	for _, a := range props {
+		pt, vt := c.expr(a.Prop), c.expr(a.Value)
+		if IsNumeric(pt) && IsNumeric(vt) {
+			continue
+		}
+		if _, ok := Unify(pt, vt); !ok {
+			c.errorf(a.Value, "cannot assign %s to property %s of type %s", vt, a.Prop.Names[len(a.Prop.Names)-1].Name, pt)
+		}
+	}
+}
+
+// allOf returns the columns of "v.*", which must have declared
+// properties.
+func (c *checker) allOf(sel *ast.SelectElem) []*Column {
+	obj := c.sema.ObjectOf(sel.AllOf)
+	ts := c.tables[obj]
+	if len(ts) == 0 {
+		c.errorf(sel.AllOf, "cannot expand %s.* without known tables", sel.AllOf.Name)
+		return nil
+	}
+
+	var prefix string
+	if sel.Prefix != nil {
+		prefix = sel.Prefix.Text
+	}
+
+	var cols []*Column
+	seen := map[string]*Column{}
+	for _, t := range ts {
+		if t.AllColumns {
+			c.errorf(sel.AllOf, "cannot expand %s.* since table %s has all columns as properties", sel.AllOf.Name, t.Name)
+			return nil
+		}
+		for _, p := range t.Props {
+			if col := seen[p.Name]; col != nil {
+				col.Type = unifyProp(col.Type, FromDataType(p.Type))
+				continue
+			}
+			col := &Column{Name: prefix + p.Name, Type: FromDataType(p.Type)}
+			seen[p.Name] = col
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// ColumnName returns the name of the result column of a SELECT
+// element: its alias, the variable or property name, or the
+// expression as printed.
+func ColumnName(ne *ast.NamedExpr) string {
+	switch e := ne.Expr.(type) {
+	case *ast.Ident:
+		if ne.Name == nil {
+			return e.Name
+		}
+	case *ast.QIdent:
+		if ne.Name == nil {
+			return e.Names[len(e.Names)-1].Name
+		}
+	}
+	if ne.Name != nil {
+		return ne.Name.Name
+	}
+
+	var sb strings.Builder
+	if err := printer.Fprint(&sb, ne.Expr); err != nil {
+		return ""
+	}
+	return sb.String()
+}
+
+// expr returns the type of e, and records it.
+func (c *checker) expr(e ast.Expr) Type {
+	if t, ok := c.info.Types[e]; ok {
+		return t
+	}
+	t := c.infer(e)
+	c.info.Types[e] = t
+	return t
+}
+
+func (c *checker) infer(e ast.Expr) Type {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return c.lit(e)
+
+	case *ast.BindVar:
+		return Unknown
+
+	case *ast.Ident:
+		return c.variable(e)
+
+	case *ast.QIdent:
+		return c.property(e)
+
+	case *ast.OpExpr:
+		return c.op(e)
+
+	case *ast.CastExpr:
+		t := c.expr(e.Arg)
+		switch t.(type) {
+		case *List:
+			c.errorf(e, "cannot cast %s to %s", t, e.TypeKind)
+		default:
+			if t == Vertex || t == Edge {
+				c.errorf(e, "cannot cast %s to %s", t, e.TypeKind)
+			}
+		}
+		return FromDataType(e.TypeKind)
+
+	case *ast.CaseExpr:
+		return c.caseExpr(e)
+
+	case *ast.ExtractExpr:
+		if t := c.expr(e.Arg); !IsUnknown(t) && !IsTemporal(t) {
+			c.errorf(e.Arg, "cannot extract %s from %s", e.Field, t)
+		}
+		if e.Field == ast.SecondField {
+			return Double
+		}
+		return Integer
+
+	case *ast.AggregateExpr:
+		return c.aggregate(e)
+
+	case *ast.SubstringExpr:
+		c.want(e.Arg, "SUBSTRING argument", String)
+		c.wantNumber(e.Start, "SUBSTRING start")
+		if e.Length != nil {
+			c.wantNumber(e.Length, "SUBSTRING length")
+		}
+		return String
+
+	case *ast.IsNullExpr:
+		c.expr(e.Arg)
+		return Boolean
+
+	case *ast.ExistsExpr:
+		c.selectStmt(e.Query.Query)
+		return Boolean
+
+	case *ast.InExpr:
+		st := c.expr(e.Subject)
+		for _, o := range e.Objects {
+			if ot := c.expr(o); !Comparable(st, ot) {
+				c.errorf(o, "cannot compare %s and %s", st, ot)
+			}
+		}
+		if e.BindVar != nil {
+			c.expr(e.BindVar)
+		}
+		return Boolean
+
+	case *ast.LabelExpr:
+		if t := c.expr(e.Arg); !IsUnknown(t) && t != Vertex && t != Edge {
+			c.errorf(e.Arg, "LABEL argument has type %s, want VERTEX or EDGE", t)
+		}
+		if e.Plural {
+			return &List{Elem: String}
+		}
+		return String
+
+	case *ast.CallExpr:
+		return c.call(e)
+
+	case *ast.SubqueryExpr:
+		cols := c.selectStmt(e.Query)
+		if len(cols) != 1 {
+			c.errorf(e, "scalar subquery has %d columns, want 1", len(cols))
+			return Unknown
+		}
+		return cols[0].Type
+
+	default:
+		return Unknown
+	}
+}
+
+func (c *checker) lit(x *ast.BasicLit) Type {
+	switch x.Kind {
+	case ast.UIntKind:
+		if v, err := x.Value(); err == nil {
+			if i, ok := v.(int64); ok && i <= math.MaxInt32 {
+				return Integer
+			}
+		}
+		return Long
+	case ast.UDecKind:
+		return Double
+	case ast.BoolKind:
+		return Boolean
+	case ast.StringKind:
+		return String
+	case ast.DateKind:
+		return Date
+	case ast.TimeKind:
+		return zoned(x, Time, TimeTZ)
+	case ast.TimestampKind:
+		return zoned(x, Timestamp, TimestampTZ)
+	case ast.IntervalKind:
+		return Interval
+	default:
+		return Unknown
+	}
+}
+
+// zoned returns tz if the literal has a time zone.
+func zoned(x *ast.BasicLit, t, tz Type) Type {
+	v, err := x.Value()
+	if err != nil {
+		return t
+	}
+	if tm, ok := v.(time.Time); ok && tm.Location() != time.UTC {
+		return tz
+	}
+	return t
+}
+
+// variable returns the type of a variable reference.
+func (c *checker) variable(id *ast.Ident) Type {
+	obj := c.sema.ObjectOf(id)
+	if obj == nil {
+		return Unknown
+	}
+
+	var t Type
+	switch obj.Kind {
+	case sema.VertexKind:
+		t = Vertex
+	case sema.EdgeKind:
+		t = Edge
+	case sema.ExprKind:
+		return c.expr(obj.Decl.(*ast.NamedExpr).Expr)
+	default:
+		return Unknown
+	}
+	return c.wrap(obj, t)
+}
+
+// property returns the type of a property access, from the properties
+// of the tables the variable can be bound to.
+func (c *checker) property(q *ast.QIdent) Type {
+	if len(q.Names) != 2 {
+		return Unknown
+	}
+	obj := c.sema.ObjectOf(q.Names[0])
+	if obj == nil {
+		return Unknown
+	}
+	if obj.Kind == sema.ExprKind {
+		c.errorf(q, "%s is not a vertex or edge variable", obj.Name)
+		return Unknown
+	}
+
+	var t Type
+	name := q.Names[1].Name
+	for _, tbl := range c.tables[obj] {
+		if tbl.AllColumns {
+			return c.wrap(obj, Unknown)
+		}
+		if p := tbl.Property(name); p != nil {
+			if t == nil {
+				t = FromDataType(p.Type)
+			} else {
+				t = unifyProp(t, FromDataType(p.Type))
+			}
+		}
+	}
+	if t == nil {
+		t = Unknown
+	}
+	return c.wrap(obj, t)
+}
+
+// wrap returns a list of t for variables of quantified path patterns.
+func (c *checker) wrap(obj *sema.Object, t Type) Type {
+	if obj.Group && !c.elems {
+		return &List{Elem: t}
+	}
+	return t
+}
+
+// unifyProp returns the type of a property in several tables, which
+// is Unknown if they differ.
+func unifyProp(a, b Type) Type {
+	if IsUnknown(a) || IsUnknown(b) {
+		return Unknown
+	}
+	if t, ok := Unify(a, b); ok {
+		return t
+	}
+	return Unknown
+}
+
+func (c *checker) op(e *ast.OpExpr) Type {
+	ts := make([]Type, len(e.Args))
+	for i, arg := range e.Args {
+		ts[i] = c.expr(arg)
+	}
+
+	switch e.Op {
+	case ast.OrOp, ast.AndOp, ast.NotOp:
+		for i, t := range ts {
+			if !IsUnknown(t) && t != Boolean {
+				c.errorf(e.Args[i], "%s operand has type %s, want BOOLEAN", e.Op, t)
+			}
+		}
+		return Boolean
+
+	case ast.EqOp, ast.NeOp, ast.LtOp, ast.GtOp, ast.LeOp, ast.GeOp:
+		if len(ts) != 2 {
+			return Boolean
+		}
+		a, b := ts[0], ts[1]
+		switch {
+		case !Comparable(a, b):
+			c.errorf(e, "cannot compare %s and %s", a, b)
+		case e.Op != ast.EqOp && e.Op != ast.NeOp && (a == Vertex || a == Edge || b == Vertex || b == Edge):
+			c.errorf(e, "cannot order %s values", nonUnknown(a, b))
+		}
+		return Boolean
+
+	case ast.NegOp:
+		if len(ts) == 1 && !IsUnknown(ts[0]) && !IsNumeric(ts[0]) && ts[0] != Interval {
+			c.errorf(e, "invalid operand type %s for unary -", ts[0])
+			return Unknown
+		}
+		return ts[0]
+
+	case ast.ConcatOp:
+		for i, t := range ts {
+			if !IsUnknown(t) && t != String {
+				c.errorf(e.Args[i], "|| operand has type %s, want STRING", t)
+			}
+		}
+		return String
+
+	case ast.AddOp, ast.SubOp, ast.MulOp, ast.DivOp, ast.ModOp:
+		if len(ts) != 2 {
+			return Unknown
+		}
+		if t, ok := arith(e.Op, ts[0], ts[1]); ok {
+			return t
+		}
+		c.errorf(e, "mismatched types %s and %s for %s", ts[0], ts[1], e.Op)
+		return Unknown
+
+	default:
+		return Unknown
+	}
+}
+
+// arith returns the result type of a binary arithmetic operator.
+func arith(op ast.Op, a, b Type) (Type, bool) {
+	switch {
+	case IsNumeric(a) && IsNumeric(b):
+		return Unify(a, b)
+	case IsUnknown(a) && IsUnknown(b):
+		return Unknown, true
+	case IsUnknown(a) && IsNumeric(b), IsNumeric(a) && IsUnknown(b):
+		return Unknown, true
+	}
+
+	if op != ast.AddOp && op != ast.SubOp {
+		return nil, false
+	}
+	switch {
+	case IsTemporal(a) && (b == Interval || IsUnknown(b)):
+		return a, true
+	case op == ast.AddOp && (a == Interval || IsUnknown(a)) && IsTemporal(b):
+		return b, true
+	case a == Interval && IsUnknown(b), IsUnknown(a) && b == Interval:
+		// The other side may be a datetime.
+		return Unknown, true
+	}
+	return nil, false
+}
+
+// nonUnknown returns a if it is known, or b.
+func nonUnknown(a, b Type) Type {
+	if IsUnknown(a) {
+		return b
+	}
+	return a
+}
+
+func (c *checker) caseExpr(e *ast.CaseExpr) Type {
+	var st Type
+	if e.Subject != nil {
+		st = c.expr(e.Subject)
+	}
+
+	var rt Type = Unknown
+	result := func(x ast.Expr) {
+		t := c.expr(x)
+		u, ok := Unify(rt, t)
+		if !ok {
+			c.errorf(x, "CASE results have types %s and %s", rt, t)
+			return
+		}
+		rt = u
+	}
+	for _, w := range e.Whens {
+		if st != nil {
+			if t := c.expr(w.Cond); !Comparable(st, t) {
+				c.errorf(w.Cond, "cannot compare %s and %s", st, t)
+			}
+		} else {
+			c.cond(w.Cond, "WHEN")
+		}
+		result(w.Then)
+	}
+	if e.Else != nil {
+		result(e.Else)
+	}
+	return rt
+}
+
+func (c *checker) aggregate(e *ast.AggregateExpr) Type {
+	var t Type = Unknown
+	if e.Arg != nil {
+		t = c.expr(e.Arg)
+		// Aggregations over quantified path patterns aggregate lists.
+		if l, ok := t.(*List); ok {
+			t = l.Elem
+		}
+	}
+
+	switch e.Func {
+	case ast.CountFunc:
+		return Long
+
+	case ast.AvgFunc:
+		c.wantNumberType(e.Arg, t, "AVG argument")
+		return Double
+
+	case ast.SumFunc:
+		c.wantNumberType(e.Arg, t, "SUM argument")
+		switch t {
+		case Integer, Long:
+			return Long
+		case Float, Double:
+			return Double
+		}
+		return Unknown
+
+	case ast.MinFunc, ast.MaxFunc:
+		return t
+
+	case ast.ArrayAggFunc:
+		return &List{Elem: t}
+
+	case ast.ListaggFunc:
+		return String
+
+	default:
+		return Unknown
+	}
+}
+
+// callTypes are the result types of functions that do not depend on
+// their arguments.
+var callTypes = map[string]Type{
+	"ALL_DIFFERENT":    Boolean,
+	"HAS_LABEL":        Boolean,
+	"ID":               Long,
+	"IN_DEGREE":        Long,
+	"OUT_DEGREE":       Long,
+	"ELEMENT_NUMBER":   Long,
+	"MATCH_NUMBER":     Long,
+	"JAVA_REGEXP_LIKE": Boolean,
+	"LOWER":            String,
+	"UPPER":            String,
+}
+
+func (c *checker) call(e *ast.CallExpr) Type {
+	ts := make([]Type, len(e.Args))
+	for i, arg := range e.Args {
+		ts[i] = c.expr(arg)
+	}
+
+	if len(e.Func.Names) > 1 {
+		// User-defined functions are unknown.
+		return Unknown
+	}
+	name := strings.ToUpper(e.Func.Names[0].Name)
+	if t, ok := callTypes[name]; ok {
+		return t
+	}
+	switch name {
+	case "ABS", "CEIL", "CEILING", "FLOOR", "ROUND":
+		if len(ts) == 1 {
+			c.wantNumberType(e.Args[0], ts[0], name+" argument")
+			return ts[0]
+		}
+	}
+	return Unknown
+}
+
+// want checks that e has type t, or Unknown.
+func (c *checker) want(e ast.Expr, what string, t Type) {
+	if et := c.expr(e); !IsUnknown(et) && !Identical(et, t) {
+		c.errorf(e, "%s has type %s, want %s", what, et, t)
+	}
+}
+
+// wantNumber checks that e is a number.
+func (c *checker) wantNumber(e ast.Expr, what string) {
+	c.wantNumberType(e, c.expr(e), what)
+}
+
+func (c *checker) wantNumberType(e ast.Expr, t Type, what string) {
+	if e != nil && !IsUnknown(t) && !IsNumeric(t) {
+		c.errorf(e, "%s has type %s, want a number", what, t)
+	}
+}
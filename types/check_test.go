@@ -0,0 +1,163 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/itergia/pgql-go/catalog"
+	"github.com/itergia/pgql-go/parser"
+	"github.com/itergia/pgql-go/sema"
+)
+
+func newCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+
+	c := catalog.New("")
+	stmt, err := parser.ParseStatement("CREATE PROPERTY GRAPH g VERTEX TABLES (person PROPERTIES (name, CAST(age AS INT) AS age, CAST(born AS DATE) AS born), company PROPERTIES (CAST(size AS LONG) AS age)) EDGE TABLES (knows SOURCE person DESTINATION person PROPERTIES (CAST(w AS DOUBLE) AS weight))")
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+	if err := c.Exec(nil, stmt); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	return c
+}
+
+func check(t *testing.T, c *catalog.Catalog, s string) (*Info, error) {
+	t.Helper()
+
+	stmt, err := parser.ParseStatement(s)
+	if err != nil {
+		t.Fatalf("ParseStatement failed: %v", err)
+	}
+	sinfo, err := sema.Check(nil, stmt)
+	if err != nil {
+		t.Fatalf("sema.Check failed: %v", err)
+	}
+	tables := c.Tables(stmt, sinfo, c.LookupGraph("", "g"))
+	return Check(nil, stmt, sinfo, tables)
+}
+
+func TestCheck(t *testing.T) {
+	c := newCatalog(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  []string
+	}{
+		{
+			"literals",
+			"SELECT 1, 3000000000, 1.5, true, 'x', DATE '2000-01-02', TIME '12:00:00', TIME '12:00:00+01:00', TIMESTAMP '2000-01-02 12:00:00', ? FROM MATCH (a)",
+			[]string{"1 INTEGER", "3000000000 LONG", "1.5 DOUBLE", "TRUE BOOLEAN", "'x' STRING", "DATE '2000-01-02' DATE", "TIME '12:00:00' TIME", "TIME '12:00:00+01:00' TIME WITH TIME ZONE", "TIMESTAMP '2000-01-02 12:00:00' TIMESTAMP", "? UNKNOWN"},
+		},
+		{
+			"operators",
+			"SELECT 1 + 2 AS i, 1 + 2.0 AS d, -a.age AS n, 'a' || 'b' AS s, 1 < 2 AND NOT true AS b, DATE '2000-01-02' + INTERVAL '1' DAY AS dt, a.born < TIMESTAMP '2000-01-02 10:00:00' AS c FROM MATCH (a:person)",
+			[]string{"i INTEGER", "d DOUBLE", "n INTEGER", "s STRING", "b BOOLEAN", "dt DATE", "c BOOLEAN"},
+		},
+		{
+			"properties",
+			"SELECT a, e, a.name, a.age, a.born, b.age, e.weight FROM MATCH (a:person) -[e]-> (), MATCH (b:person|company)",
+			[]string{"a VERTEX", "e EDGE", "name UNKNOWN", "age INTEGER", "born DATE", "age LONG", "weight DOUBLE"},
+		},
+		{
+			"functions",
+			"SELECT CAST(a.name AS TIMESTAMP WITH TIME ZONE) AS c, CASE WHEN true THEN 1 ELSE 2.5 END AS k, EXTRACT(YEAR FROM a.born) AS y, LABEL(a) AS l, LABELS(a) AS ls, UPPER(a.name) AS u, ABS(a.age) AS x, SUBSTRING('abc' FROM 2) AS sub, m.upper(a.name) AS mu FROM MATCH (a:person)",
+			[]string{"c TIMESTAMP WITH TIME ZONE", "k DOUBLE", "y INTEGER", "l STRING", "ls LIST<STRING>", "u STRING", "x INTEGER", "sub STRING", "mu UNKNOWN"},
+		},
+		{
+			"id",
+			"SELECT ID(a) AS i, ID(e) AS j FROM MATCH (a) -[e]-> ()",
+			[]string{"i LONG", "j LONG"},
+		},
+		{
+			"intervalUnknown",
+			"SELECT a.name + INTERVAL '1' DAY AS x, INTERVAL '1' DAY + ? AS y, a.born + INTERVAL '1' DAY AS z FROM MATCH (a:person)",
+			[]string{"x UNKNOWN", "y UNKNOWN", "z DATE"},
+		},
+		{
+			"aggregates",
+			"SELECT COUNT(*) AS c, AVG(a.age) AS av, SUM(a.age) AS s, MIN(a.born) AS m, ARRAY_AGG(a.age) AS arr, LISTAGG(a.name, ',') AS l FROM MATCH (a:person)",
+			[]string{"c LONG", "av DOUBLE", "s LONG", "m DATE", "arr LIST<INTEGER>", "l STRING"},
+		},
+		{
+			"group",
+			"SELECT n, COUNT(*) AS c FROM MATCH (a:person) GROUP BY a.age AS n",
+			[]string{"n INTEGER", "c LONG"},
+		},
+		{
+			"quantified",
+			"SELECT e, SUM(e.weight) AS w FROM MATCH ANY CHEAPEST (a) (-[e]-> WHERE e.weight > 0 COST e.weight)* (b)",
+			[]string{"e LIST<EDGE>", "w DOUBLE"},
+		},
+		{
+			"subquery",
+			"SELECT (SELECT MAX(b.age) FROM MATCH (a) -> (b:person)) AS m FROM MATCH (a:person)",
+			[]string{"m INTEGER"},
+		},
+		{
+			"allOf",
+			"SELECT a.* PREFIX 'p_' FROM MATCH (a:person)",
+			[]string{"p_name UNKNOWN", "p_age INTEGER", "p_born DATE"},
+		},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			info, err := check(t, c, tst.Input)
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+
+			var got []string
+			for _, col := range info.Columns {
+				got = append(got, fmt.Sprintf("%s %v", col.Name, col.Type))
+			}
+			if diff := cmp.Diff(tst.Want, got); diff != "" {
+				t.Errorf("Columns: +got, -want:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	c := newCatalog(t)
+
+	tsts := []struct {
+		Name  string
+		Input string
+		Want  string
+	}{
+		{"compare", "SELECT a FROM MATCH (a:person) WHERE a.born = true", "at 1:38: cannot compare DATE and BOOLEAN"},
+		{"compareTime", "SELECT a FROM MATCH (a:person) WHERE a.born < TIME '10:00:00'", "at 1:38: cannot compare DATE and TIME"},
+		{"where", "SELECT a FROM MATCH (a:person) WHERE a.age", "at 1:38: WHERE condition has type INTEGER, want BOOLEAN"},
+		{"arith", "SELECT a.born * 2 FROM MATCH (a:person)", "at 1:8: mismatched types DATE and INTEGER for *"},
+		{"interval", "SELECT INTERVAL '1' DAY + INTERVAL '1' HOUR FROM MATCH (a)", "at 1:8: mismatched types INTERVAL and INTERVAL for +"},
+		{"concat", "SELECT a.age || 'x' FROM MATCH (a:person)", "at 1:8: || operand has type INTEGER, want STRING"},
+		{"case", "SELECT CASE WHEN true THEN 1 ELSE 'x' END FROM MATCH (a)", "at 1:35: CASE results have types INTEGER and STRING"},
+		{"extract", "SELECT EXTRACT(YEAR FROM a.age) FROM MATCH (a:person)", "at 1:26: cannot extract YEAR from INTEGER"},
+		{"avg", "SELECT AVG(a.born) FROM MATCH (a:person)", "at 1:12: AVG argument has type DATE, want a number"},
+		{"orderVertex", "SELECT a FROM MATCH (a) -> (b) WHERE a < b", "at 1:38: cannot order VERTEX values"},
+		{"cost", "SELECT a FROM MATCH ANY CHEAPEST (a) (-[e]-> COST e.weight > 1)* (b)", "at 1:51: COST has type BOOLEAN, want a number"},
+		{"assign", "UPDATE a SET (a.born = 1) FROM MATCH (a:person)", "at 1:24: cannot assign INTEGER to property born of type DATE"},
+	}
+	for _, tst := range tsts {
+		tst := tst
+		t.Run(tst.Name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := check(t, c, tst.Input)
+			if err == nil {
+				t.Fatalf("Check succeeded, want %q", tst.Want)
+			}
+			if got := err.Error(); got != tst.Want {
+				t.Errorf("Check: got %q, want %q", got, tst.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package types infers the types of PGQL expressions.
+//
+// Check computes the type of every expression of a statement, and the
+// result columns of queries, from literals, casts, operators and
+// functions, and from the property types declared in a catalog.
+// Values whose type cannot be known statically, like bind variables
+// and properties without a CAST, have the Unknown type, which is
+// compatible with every type.
+package types
+
+import (
+	"fmt"
+
+	"github.com/itergia/pgql-go/ast"
+)
+
+// A Type is a Basic type or a *List.
+type Type interface {
+	String() string
+}
+
+// Basic is a scalar type.
+type Basic int
+
+const (
+	Unknown Basic = iota
+	Boolean
+	Integer
+	Long
+	Float
+	Double
+	String
+	Date
+	Time
+	TimeTZ
+	Timestamp
+	TimestampTZ
+	Interval
+	Vertex
+	Edge
+)
+
+var basicStrings = [...]string{
+	Unknown:     "UNKNOWN",
+	Boolean:     "BOOLEAN",
+	Integer:     "INTEGER",
+	Long:        "LONG",
+	Float:       "FLOAT",
+	Double:      "DOUBLE",
+	String:      "STRING",
+	Date:        "DATE",
+	Time:        "TIME",
+	TimeTZ:      "TIME WITH TIME ZONE",
+	Timestamp:   "TIMESTAMP",
+	TimestampTZ: "TIMESTAMP WITH TIME ZONE",
+	Interval:    "INTERVAL",
+	Vertex:      "VERTEX",
+	Edge:        "EDGE",
+}
+
+// String returns the type as written in PGQL, e.g. "TIME WITH TIME
+// ZONE".
+func (t Basic) String() string {
+	if t >= Unknown && int(t) < len(basicStrings) {
+		return basicStrings[t]
+	}
+	return fmt.Sprintf("Basic(%d)", int(t))
+}
+
+// A List is the type of ARRAY_AGG results, and of variables inside
+// quantified path patterns.
+type List struct {
+	Elem Type
+}
+
+// String returns the type like "LIST<LONG>".
+func (t *List) String() string { return "LIST<" + t.Elem.String() + ">" }
+
+// FromDataType returns the type of a CAST, or Unknown. INT and
+// INTEGER are the same type.
+func FromDataType(dt ast.DataType) Type {
+	switch dt {
+	case ast.StringType:
+		return String
+	case ast.BooleanType:
+		return Boolean
+	case ast.IntegerType, ast.IntType:
+		return Integer
+	case ast.LongType:
+		return Long
+	case ast.FloatType:
+		return Float
+	case ast.DoubleType:
+		return Double
+	case ast.DateType:
+		return Date
+	case ast.TimeType:
+		return Time
+	case ast.TimeTZType:
+		return TimeTZ
+	case ast.TimestampType:
+		return Timestamp
+	case ast.TimestampTZType:
+		return TimestampTZ
+	default:
+		return Unknown
+	}
+}
+
+// Identical returns true if the types are the same.
+func Identical(a, b Type) bool {
+	if la, ok := a.(*List); ok {
+		lb, ok := b.(*List)
+		return ok && Identical(la.Elem, lb.Elem)
+	}
+	return a == b
+}
+
+// IsNumeric returns true for INTEGER, LONG, FLOAT and DOUBLE.
+func IsNumeric(t Type) bool {
+	return t == Integer || t == Long || t == Float || t == Double
+}
+
+// IsTemporal returns true for dates, times and timestamps.
+func IsTemporal(t Type) bool {
+	switch t {
+	case Date, Time, TimeTZ, Timestamp, TimestampTZ:
+		return true
+	}
+	return false
+}
+
+// IsUnknown returns true for the Unknown type.
+func IsUnknown(t Type) bool {
+	return t == Unknown
+}
+
+// Comparable returns true if values of the types can be compared.
+// Numbers can be compared with each other, times with or without a
+// time zone, dates and timestamps with or without a time zone, and
+// other types only with themselves.
+// Unknown is comparable with everything.
+func Comparable(a, b Type) bool {
+	switch {
+	case IsUnknown(a) || IsUnknown(b):
+		return true
+	case IsNumeric(a) && IsNumeric(b):
+		return true
+	case (a == Time || a == TimeTZ) && (b == Time || b == TimeTZ):
+		return true
+	case (a == Date || a == Timestamp || a == TimestampTZ) && (b == Date || b == Timestamp || b == TimestampTZ):
+		return true
+	default:
+		return Identical(a, b)
+	}
+}
+
+// Unify returns the type holding values of both types, like the
+// result of a CASE, or false if there is none. Numbers are widened.
+func Unify(a, b Type) (Type, bool) {
+	switch {
+	case IsUnknown(a):
+		return b, true
+	case IsUnknown(b) || Identical(a, b):
+		return a, true
+	case IsNumeric(a) && IsNumeric(b):
+		if a.(Basic) > b.(Basic) {
+			return a, true
+		}
+		return b, true
+	}
+
+	if la, ok := a.(*List); ok {
+		if lb, ok := b.(*List); ok {
+			if t, ok := Unify(la.Elem, lb.Elem); ok {
+				return &List{Elem: t}, true
+			}
+		}
+	}
+	return nil, false
+}